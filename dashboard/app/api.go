@@ -66,6 +66,7 @@ var apiHandlers = map[string]APIHandler{
 	"report_build_error":    nsHandler(apiReportBuildError),
 	"report_crash":          nsHandler(apiReportCrash),
 	"report_failed_repro":   nsHandler(apiReportFailedRepro),
+	"report_repro_check":    nsHandler(apiReportReproCheck),
 	"need_repro":            nsHandler(apiNeedRepro),
 	"manager_stats":         nsHandler(apiManagerStats),
 	"commit_poll":           nsHandler(apiCommitPoll),
@@ -1115,6 +1116,37 @@ func apiReportFailedRepro(c context.Context, ns string, payload io.Reader) (inte
 	return nil, saveFailedReproLog(c, bug, build, req.ReproLog)
 }
 
+func apiReportReproCheck(c context.Context, ns string, payload io.Reader) (interface{}, error) {
+	req := new(dashapi.ReproCheck)
+	if err := json.NewDecoder(payload).Decode(req); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal request: %w", err)
+	}
+	bug, err := findExistingBugForCrash(c, ns, []string{req.Title})
+	if err != nil {
+		return nil, err
+	}
+	if bug == nil {
+		return nil, fmt.Errorf("%v: can't find bug for crash %q", ns, req.Title)
+	}
+	var crashes []*Crash
+	keys, err := db.NewQuery("Crash").
+		Ancestor(bug.key(c)).
+		GetAll(c, &crashes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query crashes: %w", err)
+	}
+	for i, crash := range crashes {
+		if crash.BuildID != req.BuildID || crash.ReproSyz == 0 || crash.ReproStatus == req.Status {
+			continue
+		}
+		crash.ReproStatus = req.Status
+		if _, err := db.Put(c, keys[i], crash); err != nil {
+			return nil, fmt.Errorf("failed to put crash: %w", err)
+		}
+	}
+	return nil, nil
+}
+
 func saveFailedReproLog(c context.Context, bug *Bug, build *Build, log []byte) error {
 	now := timeNow(c)
 	bugKey := bug.key(c)
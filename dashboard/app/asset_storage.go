@@ -434,9 +434,21 @@ func (ad *crashAssetDeprecator) needThisCrashAsset(crashKey *db.Key, crashAsset
 		// They're not that big to set stricter limits.
 		return ad.bugStatusPolicy(crashKey, crashAsset)
 	}
+	if crashAsset.Type == dashapi.MemoryDump {
+		// Memory dumps are huge, so keep them only while the bug is open.
+		return ad.openBugPolicy(crashKey)
+	}
 	return false, fmt.Errorf("no deprecation policy for %s", crashAsset.Type)
 }
 
+func (ad *crashAssetDeprecator) openBugPolicy(crashKey *db.Key) (bool, error) {
+	bug := new(Bug)
+	if err := db.Get(ad.c, crashKey.Parent(), bug); err != nil {
+		return false, fmt.Errorf("failed to query bug: %w", err)
+	}
+	return bug.Status == BugStatusOpen, nil
+}
+
 func (ad *crashAssetDeprecator) bugStatusPolicy(crashKey *db.Key, crashAsset *Asset) (bool, error) {
 	bugKey := crashKey.Parent()
 	bug := new(Bug)
@@ -408,6 +408,7 @@ type uiCrash struct {
 	ReproSyzLink    string
 	ReproCLink      string
 	ReproIsRevoked  bool
+	ReproStatus     string
 	ReproLogLink    string
 	MachineInfoLink string
 	Assets          []*uiAsset
@@ -2088,6 +2089,7 @@ func makeUICrash(c context.Context, crash *Crash, build *Build) *uiCrash {
 		ReproCLink:      textLink(textReproC, crash.ReproC),
 		ReproLogLink:    textLink(textReproLog, crash.ReproLog),
 		ReproIsRevoked:  crash.ReproIsRevoked,
+		ReproStatus:     crash.ReproStatus,
 		MachineInfoLink: textLink(textMachineInfo, crash.MachineInfo),
 		Assets:          makeUIAssets(c, build, crash, true),
 	}
@@ -516,3 +516,43 @@ func TestReproTask(t *testing.T) {
 	c.expectOK(err)
 	c.expectEQ(resp.CrashLog, []byte(nil))
 }
+
+func TestReportReproCheck(t *testing.T) {
+	c := NewCtx(t)
+	defer c.Close()
+
+	build := testBuild(1)
+	c.client.UploadBuild(build)
+	crash := testCrashWithRepro(build, 1)
+	c.client.ReportCrash(crash)
+	bug, _ := c.loadSingleBug()
+	_, dbCrash, _ := c.loadBugInfo(bug)
+	c.expectEQ(dbCrash.ReproStatus, "")
+
+	c.expectOK(c.client.ReportReproCheck(&dashapi.ReproCheck{
+		BuildID:    build.ID,
+		Title:      crash.Title,
+		Reproduced: false,
+		Status:     "flaky",
+	}))
+	_, dbCrash, _ = c.loadBugInfo(bug)
+	c.expectEQ(dbCrash.ReproStatus, "flaky")
+
+	// Checks on other builds do not affect the crash.
+	build2 := testBuild(2)
+	c.client.UploadBuild(build2)
+	c.expectOK(c.client.ReportReproCheck(&dashapi.ReproCheck{
+		BuildID: build2.ID,
+		Title:   crash.Title,
+		Status:  "broken",
+	}))
+	_, dbCrash, _ = c.loadBugInfo(bug)
+	c.expectEQ(dbCrash.ReproStatus, "flaky")
+
+	client := c.makeClient(client1, password1, false)
+	c.expectFail("can't find bug", client.ReportReproCheck(&dashapi.ReproCheck{
+		BuildID: build.ID,
+		Title:   "unknown title",
+		Status:  "reliable",
+	}))
+}
@@ -376,6 +376,20 @@ func (dash *Dashboard) ReportFailedRepro(crash *CrashID) error {
 	return dash.Query("report_failed_repro", crash, nil)
 }
 
+// ReproCheck describes the result of a manager re-running an already found reproducer.
+type ReproCheck struct {
+	BuildID    string
+	Title      string
+	Reproduced bool
+	// Summary of the recent checks: "reliable", "flaky" or "broken".
+	Status string
+}
+
+// ReportReproCheck notifies dashboard about a re-run of the syz reproducer for the crash.
+func (dash *Dashboard) ReportReproCheck(req *ReproCheck) error {
+	return dash.Query("report_repro_check", req, nil)
+}
+
 type LogToReproReq struct {
 	BuildID string
 }
@@ -511,6 +525,7 @@ const (
 	KernelImage        AssetType = "kernel_image"
 	HTMLCoverageReport AssetType = "html_coverage_report"
 	MountInRepro       AssetType = "mount_in_repro"
+	MemoryDump         AssetType = "memory_dump"
 )
 
 type BisectResult struct {
@@ -0,0 +1,61 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Barrier synchronizes start of execution of pairs of programs in different procs
+// (requests with ExecFlag::Barrier). This is used to reproduce races between programs
+// executed concurrently (syz-execprog -concurrent), both programs of the pair are submitted
+// at the same time, and the barrier makes sure that their execution actually overlaps.
+//
+// The barrier is a counter of arrived programs in shared memory created by the runner.
+// Programs with even arrival numbers wait for the next one, programs with odd numbers
+// complete the pair. If the pair is not completed in time (e.g. the other proc is restarting),
+// the waiting program withdraws its arrival, so that the following programs are still paired correctly.
+class Barrier
+{
+public:
+	Barrier()
+	    : shmem_(kMemSize),
+	      arrived_(static_cast<std::atomic<uint64>*>(shmem_.Mem()))
+	{
+	}
+
+	Barrier(int fd, void* preferred = nullptr)
+	    : shmem_(fd, preferred, kMemSize, true),
+	      arrived_(static_cast<std::atomic<uint64>*>(shmem_.Mem()))
+	{
+	}
+
+	// Wait waits for the other program of the pair for up to timeout_ms.
+	// Returns false if the other program has not arrived.
+	bool Wait(uint64 timeout_ms)
+	{
+		uint64 n = arrived_->fetch_add(1);
+		if (n % 2)
+			return true;
+		uint64 start = current_time_ms();
+		// Spin, the other program needs to be released as soon as possible.
+		while (arrived_->load() < n + 2) {
+			if (current_time_ms() - start < timeout_ms)
+				continue;
+			uint64 expected = n + 1;
+			if (arrived_->compare_exchange_strong(expected, n))
+				return false;
+			// The other program arrived right now.
+			break;
+		}
+		return true;
+	}
+
+	int FD() const
+	{
+		return shmem_.FD();
+	}
+
+private:
+	static constexpr size_t kMemSize = 4 << 10;
+	ShmemFile shmem_;
+	std::atomic<uint64>* arrived_;
+
+	Barrier(const Barrier&) = delete;
+	Barrier& operator=(const Barrier&) = delete;
+};
@@ -525,7 +525,78 @@ static long syz_execute_func(volatile long text)
 }
 #endif
 
-#if SYZ_THREADED
+#if SYZ_CONCURRENT
+// The program consists of two parts that are executed concurrently in separate threads:
+// calls before CONCURRENT_SPLIT and the rest of the calls. This is used to reproduce races
+// between programs that were executed by different procs during fuzzing.
+struct concurrent_t {
+	int created, first, last;
+	event_t ready, done;
+};
+
+static struct concurrent_t concurrent[2];
+static void execute_call(int call);
+static int concurrent_started;
+
+static void* concurrent_thr(void* arg)
+{
+	struct concurrent_t* th = (struct concurrent_t*)arg;
+	for (;;) {
+		event_wait(&th->ready);
+		event_reset(&th->ready);
+		// Wait for the other part to start, so that the parts actually overlap.
+		__atomic_fetch_add(&concurrent_started, 1, __ATOMIC_RELAXED);
+		while (__atomic_load_n(&concurrent_started, __ATOMIC_RELAXED) < 2) {
+		}
+		int call;
+		for (call = th->first; call < th->last; call++)
+			execute_call(call);
+		event_set(&th->done);
+	}
+	return 0;
+}
+
+#if SYZ_REPEAT
+static void execute_one(void)
+#else
+static void loop(void)
+#endif
+{
+	if (write(1, "executing program\n", sizeof("executing program\n") - 1)) {
+	}
+#if SYZ_TRACE
+	fprintf(stderr, "### start\n");
+#endif
+	int i;
+	for (i = 0; i < 2; i++) {
+		struct concurrent_t* th = &concurrent[i];
+		if (!th->created) {
+			th->created = 1;
+			th->first = i ? /*{{{CONCURRENT_SPLIT}}}*/ : 0;
+			th->last = i ? /*{{{NUM_CALLS}}}*/ : /*{{{CONCURRENT_SPLIT}}}*/;
+			event_init(&th->ready);
+			event_init(&th->done);
+			event_set(&th->done);
+			thread_start(concurrent_thr, th);
+		}
+	}
+	// A part may still be running from the previous iteration, then we can't start a new one.
+	if (event_isset(&concurrent[0].done) && event_isset(&concurrent[1].done)) {
+		__atomic_store_n(&concurrent_started, 0, __ATOMIC_RELAXED);
+		for (i = 0; i < 2; i++) {
+			event_reset(&concurrent[i].done);
+			event_set(&concurrent[i].ready);
+		}
+	} else {
+		sleep_ms(1);
+	}
+	for (i = 0; i < 2; i++)
+		event_timedwait(&concurrent[i].done, /*{{{PROGRAM_TIMEOUT_MS}}}*/);
+#if SYZ_HAVE_CLOSE_FDS
+	close_fds();
+#endif
+}
+#elif SYZ_THREADED
 struct thread_t {
 	int created, call;
 	event_t ready, done;
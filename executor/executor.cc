@@ -246,6 +246,7 @@ const int kInFd = 3;
 const int kOutFd = 4;
 const int kMaxSignalFd = 5;
 const int kCoverFilterFd = 6;
+const int kBarrierFd = 7;
 static OutputData* output_data;
 static std::optional<ShmemBuilder> output_builder;
 static uint32 output_size;
@@ -278,6 +279,7 @@ static bool flag_collect_cover;
 static bool flag_collect_signal;
 static bool flag_dedup_cover;
 static bool flag_threaded;
+static bool flag_barrier;
 
 // If true, then executor should write the comparisons data to fuzzer.
 static bool flag_comparisons;
@@ -561,6 +563,7 @@ static feature_t features[] = {};
 #include "shmem.h"
 
 #include "conn.h"
+#include "barrier.h"
 #include "cover_filter.h"
 #include "files.h"
 #include "subprocess.h"
@@ -573,6 +576,7 @@ static feature_t features[] = {};
 
 static std::optional<CoverFilter> max_signal;
 static std::optional<CoverFilter> cover_filter;
+static std::optional<Barrier> barrier;
 
 #if SYZ_HAVE_SANDBOX_ANDROID
 static uint64 sandbox_arg = 0;
@@ -636,6 +640,10 @@ int main(int argc, char** argv)
 			cover_filter.emplace(kCoverFilterFd, reinterpret_cast<void*>(0x110f230000ull));
 			close(kCoverFilterFd);
 		}
+		if (fcntl(kBarrierFd, F_GETFD) != -1) {
+			barrier.emplace(kBarrierFd, reinterpret_cast<void*>(0x1112230000ull));
+			close(kBarrierFd);
+		}
 
 		setup_control_pipes();
 		receive_handshake();
@@ -869,6 +877,7 @@ void parse_execute(const execute_req& req)
 	flag_dedup_cover = req.exec_flags & (uint64)rpc::ExecFlag::DedupCover;
 	flag_comparisons = req.exec_flags & (uint64)rpc::ExecFlag::CollectComps;
 	flag_threaded = req.exec_flags & (uint64)rpc::ExecFlag::Threaded;
+	flag_barrier = req.exec_flags & (uint64)rpc::ExecFlag::Barrier;
 	all_call_signal = req.all_call_signal;
 	all_extra_signal = req.all_extra_signal;
 
@@ -953,6 +962,11 @@ void execute_one()
 	// Output buffer may be pkey-protected in snapshot mode, so don't write the output size
 	// (it's fixed and known anyway).
 	output_builder.emplace(output_data, output_size, !flag_snapshot);
+	if (flag_barrier && barrier) {
+		// The runner's timeout includes the wait, so don't spend the whole program timeout on it.
+		if (!barrier->Wait(program_timeout_ms / 4))
+			debug("barrier: the other program has not arrived\n");
+	}
 	uint64 start = current_time_ms();
 	uint8* input_pos = input_data;
 
@@ -1091,6 +1105,12 @@ void execute_one()
 			args[i] = read_arg(&input_pos);
 		for (uint64 i = num_args; i < kMaxArgs; i++)
 			args[i] = 0;
+		if (call_index < (int)snapshot_skip_calls) {
+			// The call was executed by the snapshot warm-up program, its results are already in place.
+			call_index++;
+			memset(&call_props, 0, sizeof(call_props));
+			continue;
+		}
 		thread_t* th = schedule_call(call_index++, call_num, copyout_index,
 					     num_args, args, input_pos, call_props);
 
@@ -1155,7 +1175,9 @@ void execute_one()
 	}
 
 #if SYZ_HAVE_CLOSE_FDS
-	close_fds();
+	// Resources created by the snapshot warm-up program must survive until the requests.
+	if (!snapshot_warmup_running)
+		close_fds();
 #endif
 
 	write_extra_output();
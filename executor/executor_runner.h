@@ -112,7 +112,7 @@ class Proc
 {
 public:
 	Proc(Connection& conn, const char* bin, ProcIDPool& proc_id_pool, int& restarting, const bool& corpus_triaged, int max_signal_fd,
-	     int cover_filter_fd, ProcOpts opts)
+	     int cover_filter_fd, int barrier_fd, ProcOpts opts)
 	    : conn_(conn),
 	      bin_(bin),
 	      proc_id_pool_(proc_id_pool),
@@ -121,6 +121,7 @@ public:
 	      corpus_triaged_(corpus_triaged),
 	      max_signal_fd_(max_signal_fd),
 	      cover_filter_fd_(cover_filter_fd),
+	      barrier_fd_(barrier_fd),
 	      opts_(opts),
 	      req_shmem_(kMaxInput),
 	      resp_shmem_(kMaxOutput),
@@ -225,6 +226,7 @@ private:
 	const bool& corpus_triaged_;
 	const int max_signal_fd_;
 	const int cover_filter_fd_;
+	const int barrier_fd_;
 	const ProcOpts opts_;
 	State state_ = State::Started;
 	std::optional<Subprocess> process_;
@@ -343,6 +345,7 @@ private:
 		    {resp_shmem_.FD(), kOutFd},
 		    {max_signal_fd_, kMaxSignalFd},
 		    {cover_filter_fd_, kCoverFilterFd},
+		    {barrier_fd_, kBarrierFd},
 		};
 		const char* argv[] = {bin_, "exec", nullptr};
 		process_.emplace(argv, fds);
@@ -566,7 +569,7 @@ public:
 		int cover_filter_fd = cover_filter_ ? cover_filter_->FD() : -1;
 		for (int i = 0; i < num_procs; i++)
 			procs_.emplace_back(new Proc(conn, bin, *proc_id_pool_, restarting_, corpus_triaged_,
-						     max_signal_fd, cover_filter_fd, proc_opts_));
+						     max_signal_fd, cover_filter_fd, barrier_.FD(), proc_opts_));
 
 		for (;;)
 			Loop();
@@ -577,6 +580,7 @@ private:
 	const int vm_index_;
 	std::optional<CoverFilter> max_signal_;
 	std::optional<CoverFilter> cover_filter_;
+	Barrier barrier_;
 	std::optional<ProcIDPool> proc_id_pool_;
 	std::vector<std::unique_ptr<Proc>> procs_;
 	std::deque<rpc::ExecRequestRawT> requests_;
@@ -1001,8 +1005,8 @@ static void runner(char** argv, int argc)
 	Connection conn(manager_addr, manager_port);
 
 	// This is required to make Subprocess fd remapping logic work.
-	// kCoverFilterFd is the largest fd we set in the child processes.
-	for (int fd = conn.FD(); fd < kCoverFilterFd;)
+	// kBarrierFd is the largest fd we set in the child processes.
+	for (int fd = conn.FD(); fd < kBarrierFd;)
 		fd = dup(fd);
 
 	Runner(conn, vm_index, argv[0]);
@@ -10,6 +10,12 @@
 #include <atomic>
 #include <string>
 #include <utility>
+#include <vector>
+
+#if GOOS_linux
+#include <linux/vm_sockets.h>
+#include <sys/socket.h>
+#endif
 
 #ifndef MADV_POPULATE_WRITE
 #define MADV_POPULATE_WRITE 23
@@ -36,9 +42,41 @@ static struct {
 	void* input;
 } ivs;
 
+// VMs without ivshmem devices (Firecracker) use a vsock connection to the host instead.
+// The header, input and output are then stored in the executor memory shared with the fork server,
+// and the state changes are sent/received as messages (see vm/firecracker/snapshot.go).
+static bool snapshot_vsock;
+
+struct SnapshotVsockMsg {
+	uint64 state;
+	uint32 size;
+	uint32 pad;
+};
+
+// Optional warm-up program that is executed once right before the snapshot is taken.
+// All requests start with the warm-up calls, which are not executed again,
+// requests refer to the results of the warm-up calls saved in results.
+static std::vector<uint8> snapshot_warmup;
+static bool snapshot_warmup_running;
+// The number of leading request calls that were already executed by the warm-up program.
+static uint64 snapshot_skip_calls;
+
+static void SnapshotMapMemory(void* input, void* output)
+{
+#if GOOS_linux
+	if (pkeys_enabled && pkey_mprotect(output, static_cast<uint64>(rpc::Const::MaxOutputSize),
+					   PROT_READ | PROT_WRITE, RESERVED_PKEY))
+		exitf("failed to pkey_mprotect output buffer");
+#endif
+	ivs.hdr = static_cast<rpc::SnapshotHeaderT*>(output);
+	ivs.input = input;
+	output_data = reinterpret_cast<OutputData*>(static_cast<char*>(output) + sizeof(rpc::SnapshotHeaderT));
+	output_size = static_cast<uint64>(rpc::Const::MaxOutputSize) - sizeof(rpc::SnapshotHeaderT);
+}
+
 // Finds qemu ivshmem device, see:
 // https://www.qemu.org/docs/master/specs/ivshmem-spec.html
-static void FindIvshmemDevices()
+static bool FindIvshmemDevices()
 {
 	std::string result;
 	DIR* devices = opendir("/sys/bus/pci/devices");
@@ -87,24 +125,132 @@ static void FindIvshmemDevices()
 			      input, static_cast<uint64>(rpc::Const::MaxInputSize));
 			debug("mapped shmem output at at %p/%llu\n",
 			      output, static_cast<uint64>(rpc::Const::MaxOutputSize));
-#if GOOS_linux
-			if (pkeys_enabled && pkey_mprotect(output, static_cast<uint64>(rpc::Const::MaxOutputSize),
-							   PROT_READ | PROT_WRITE, RESERVED_PKEY))
-				exitf("failed to pkey_mprotect output buffer");
-#endif
 		}
 		close(res2);
 	}
 	closedir(devices);
 	if (regs == nullptr || input == nullptr)
-		fail("cannot find ivshmem PCI devices");
+		return false;
 	ivs.doorbell = static_cast<uint32*>(regs) + 3;
-	ivs.hdr = static_cast<rpc::SnapshotHeaderT*>(output);
-	ivs.input = input;
-	output_data = reinterpret_cast<OutputData*>(static_cast<char*>(output) + sizeof(rpc::SnapshotHeaderT));
-	output_size = static_cast<uint64>(rpc::Const::MaxOutputSize) - sizeof(rpc::SnapshotHeaderT);
+	SnapshotMapMemory(input, output);
+	return true;
 }
 
+#if GOOS_linux
+// Must match snapshotPort in vm/firecracker/snapshot.go.
+constexpr uint32 kSnapshotVsockPort = 7788;
+const int kSnapshotFd = kExtraCoverFd - 1;
+
+static void SnapshotVsockConnect()
+{
+	for (int i = 0;; i++) {
+		int fd = socket(AF_VSOCK, SOCK_STREAM, 0);
+		if (fd == -1)
+			fail("socket(AF_VSOCK) failed");
+		struct sockaddr_vm addr = {};
+		addr.svm_family = AF_VSOCK;
+		addr.svm_cid = VMADDR_CID_HOST;
+		addr.svm_port = kSnapshotVsockPort;
+		if (connect(fd, reinterpret_cast<struct sockaddr*>(&addr), sizeof(addr)) == 0) {
+			// Use a high fd that is not closed by the test programs.
+			if (dup2(fd, kSnapshotFd) < 0)
+				fail("dup2 of the vsock fd failed");
+			close(fd);
+			return;
+		}
+		close(fd);
+		if (i == 1000)
+			fail("failed to connect to the host over vsock");
+		sleep_ms(10);
+	}
+}
+
+static void SnapshotVsockSetup()
+{
+	// The memory is shared, so that the fork server parent sees the state changes
+	// made by the test process.
+	void* input = mmap(nullptr, static_cast<uint64>(rpc::Const::MaxInputSize),
+			   PROT_READ | PROT_WRITE, MAP_SHARED | MAP_ANONYMOUS, -1, 0);
+	void* output = mmap(nullptr, static_cast<uint64>(rpc::Const::MaxOutputSize),
+			    PROT_READ | PROT_WRITE, MAP_SHARED | MAP_ANONYMOUS, -1, 0);
+	if (input == MAP_FAILED || output == MAP_FAILED)
+		fail("failed to mmap snapshot memory");
+	SnapshotMapMemory(input, output);
+	snapshot_vsock = true;
+	SnapshotVsockConnect();
+}
+
+static bool SnapshotVsockRead(void* data, size_t size)
+{
+	for (size_t pos = 0; pos < size;) {
+		ssize_t n = read(kSnapshotFd, static_cast<char*>(data) + pos, size - pos);
+		if (n == -1 && errno == EINTR)
+			continue;
+		if (n <= 0)
+			return false;
+		pos += n;
+	}
+	return true;
+}
+
+static void SnapshotVsockWrite(const volatile void* data, size_t size)
+{
+	for (size_t pos = 0; pos < size;) {
+		ssize_t n = write(kSnapshotFd, const_cast<char*>(static_cast<const volatile char*>(data)) + pos,
+				  size - pos);
+		if (n == -1 && errno == EINTR)
+			continue;
+		if (n <= 0)
+			fail("snapshot vsock write failed");
+		pos += n;
+	}
+}
+
+// SnapshotVsockRecv receives the next state (along with the input) from the host.
+// Firecracker resets vsock connections on snapshot restore, so after a restore
+// we reconnect and wait for the request on the new connection.
+static void SnapshotVsockRecv()
+{
+	for (;;) {
+		SnapshotVsockMsg msg;
+		if (SnapshotVsockRead(&msg, sizeof(msg))) {
+			if (msg.size > static_cast<uint64>(rpc::Const::MaxInputSize))
+				failmsg("too large snapshot input", "size=%u", msg.size);
+			if (SnapshotVsockRead(ivs.input, msg.size)) {
+				std::atomic_signal_fence(std::memory_order_seq_cst);
+				ivs.hdr->state = static_cast<rpc::SnapshotState>(msg.state);
+				return;
+			}
+		}
+		debug("snapshot vsock connection is reset, reconnecting\n");
+		close(kSnapshotFd);
+		SnapshotVsockConnect();
+	}
+}
+
+static void SnapshotVsockSend(rpc::SnapshotState state)
+{
+	SnapshotVsockMsg msg = {static_cast<uint64>(state), 0, 0};
+	if (state == rpc::SnapshotState::Executed || state == rpc::SnapshotState::Failed)
+		msg.size = ivs.hdr->output_size;
+	SnapshotVsockWrite(&msg, sizeof(msg));
+	SnapshotVsockWrite(reinterpret_cast<volatile char*>(ivs.hdr) + ivs.hdr->output_offset, msg.size);
+}
+#else
+static void SnapshotVsockSetup()
+{
+	fail("cannot find ivshmem PCI devices");
+}
+
+static void SnapshotVsockRecv()
+{
+}
+
+static void SnapshotVsockSend(rpc::SnapshotState state)
+{
+}
+#endif
+
 static void SnapshotSetup(char** argv, int argc)
 {
 	flag_snapshot = true;
@@ -116,10 +262,15 @@ static void SnapshotSetup(char** argv, int argc)
 	// This is required to turn off rate limiting of writes.
 	write_file("/proc/sys/kernel/printk_devkmsg", "on\n");
 #endif
-	FindIvshmemDevices();
+	if (!FindIvshmemDevices())
+		SnapshotVsockSetup();
 	// Wait for the host to write handshake_req into input memory.
-	while (ivs.hdr->state != rpc::SnapshotState::Handshake)
-		sleep_ms(10);
+	while (ivs.hdr->state != rpc::SnapshotState::Handshake) {
+		if (snapshot_vsock)
+			SnapshotVsockRecv();
+		else
+			sleep_ms(10);
+	}
 	auto msg = flatbuffers::GetRoot<rpc::SnapshotHandshake>(ivs.input);
 	handshake_req req = {
 	    .magic = kInMagic,
@@ -149,6 +300,8 @@ static void SnapshotSetup(char** argv, int argc)
 		if (reason)
 			failmsg("feature setup failed", "reason: %s", reason);
 	}
+	if (msg->warmup())
+		snapshot_warmup.assign(msg->warmup()->begin(), msg->warmup()->end());
 }
 
 constexpr size_t kOutputPopulate = 256 << 10;
@@ -164,6 +317,10 @@ static void SnapshotSetState(rpc::SnapshotState state)
 	      rpc::EnumNameSnapshotState(ivs.hdr->state), rpc::EnumNameSnapshotState(state));
 	std::atomic_signal_fence(std::memory_order_seq_cst);
 	ivs.hdr->state = state;
+	if (snapshot_vsock) {
+		SnapshotVsockSend(state);
+		return;
+	}
 	// The register contains VM index shifted by 16 (the host part is VM index 1)
 	// + interrup vector index (0 in our case).
 	*ivs.doorbell = 1 << 16;
@@ -200,8 +357,30 @@ static void SnapshotPrepareParent()
 }
 #endif
 
+// SnapshotWarmup executes the warm-up program in the test process, so that the resources
+// it creates (fds, mounts, etc) are captured in the snapshot.
+static void SnapshotWarmup()
+{
+	debug("SnapshotWarmup\n");
+	uint8* input_pos = snapshot_warmup.data();
+	uint64 num_calls = read_input(&input_pos);
+	// The calls are executed in the main thread, so they must not block.
+	// Worker threads are created only afterwards since they set up coverage on the first request.
+	flag_threaded = false;
+	input_data = snapshot_warmup.data();
+	snapshot_warmup_running = true;
+	execute_one();
+	snapshot_warmup_running = false;
+	snapshot_skip_calls = num_calls;
+}
+
 static void SnapshotStart()
 {
+	// Nested call from execute_one for the warm-up program.
+	if (snapshot_warmup_running)
+		return;
+	if (!snapshot_warmup.empty())
+		SnapshotWarmup();
 	debug("SnapshotStart\n");
 	CoverAccessScope scope(nullptr);
 	// Prefault as much memory as we can before the snapshot is taken.
@@ -231,8 +410,11 @@ static void SnapshotStart()
 	// Note: we don't use sleep in the loop because we may be snapshotted while in the sleep syscall.
 	// As the result each execution after snapshot restore will be slower as it will need to finish
 	// the sleep and return from the syscall.
-	while (ivs.hdr->state == rpc::SnapshotState::Ready)
-		;
+	// With vsock we block in read, but after restore it fails right away due to the connection reset.
+	while (ivs.hdr->state == rpc::SnapshotState::Ready) {
+		if (snapshot_vsock)
+			SnapshotVsockRecv();
+	}
 	if (ivs.hdr->state == rpc::SnapshotState::Snapshotted) {
 		// First time around, just acknowledge and wait for snapshot restart.
 		SnapshotSetState(rpc::SnapshotState::Executed);
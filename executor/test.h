@@ -365,6 +365,38 @@ static int test_glob()
 	return 0;
 }
 
+static int test_barrier()
+{
+	Barrier barrier;
+	Barrier other(barrier.FD());
+	// Nobody else arrives, so the arrival is withdrawn.
+	if (barrier.Wait(0)) {
+		printf("barrier: waited for nobody\n");
+		return 1;
+	}
+	int pid = fork();
+	if (pid < 0)
+		fail("fork failed");
+	if (pid == 0)
+		doexit(other.Wait(10000) ? 0 : 1);
+	sleep_ms(10);
+	if (!barrier.Wait(0)) {
+		printf("barrier: did not pair with the waiting process\n");
+		return 1;
+	}
+	int status = 0;
+	if (waitpid(pid, &status, 0) != pid || !WIFEXITED(status) || WEXITSTATUS(status) != 0) {
+		printf("barrier: waiting process failed, status=0x%x\n", status);
+		return 1;
+	}
+	// The pair is complete, the next arrival starts a new one.
+	if (other.Wait(0)) {
+		printf("barrier: paired with a completed pair\n");
+		return 1;
+	}
+	return 0;
+}
+
 static struct {
 	const char* name;
 	int (*f)();
@@ -380,6 +412,7 @@ static struct {
 #endif
     {"test_cover_filter", test_cover_filter},
     {"test_glob", test_glob},
+    {"test_barrier", test_barrier},
 };
 
 static int run_tests(const char* test)
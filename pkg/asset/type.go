@@ -57,6 +57,11 @@ var assetTypes = map[dashapi.AssetType]*TypeDescription{
 		// the omnipresent gzip compression.
 		customCompressor: gzipCompressor,
 	},
+	dashapi.MemoryDump: {
+		GetTitle: constTitle("guest memory dump"),
+		// The dumps are huge, it's unreasonable to mention them in emails.
+		NoReporting: true,
+	},
 }
 
 type QueryTypeTitle func(*targets.Target) string
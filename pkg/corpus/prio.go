@@ -72,6 +72,17 @@ func (corpus *Corpus) ChooseProgram(r *rand.Rand) *prog.Prog {
 	return corpus.chooseProgram(r)
 }
 
+// ChooseAreaProgram is like ChooseProgram, but only considers programs of the focus area
+// with the given index. Returns nil if the area has no programs yet.
+func (corpus *Corpus) ChooseAreaProgram(r *rand.Rand, area int) *prog.Prog {
+	corpus.mu.RLock()
+	defer corpus.mu.RUnlock()
+	if area < 0 || area >= len(corpus.focusAreas) {
+		return nil
+	}
+	return corpus.focusAreas[area].chooseProgram(r)
+}
+
 func (corpus *Corpus) Programs() []*prog.Prog {
 	corpus.mu.RLock()
 	defer corpus.mu.RUnlock()
@@ -117,3 +117,29 @@ func TestFocusAreas(t *testing.T) {
 	assert.InDelta(t, secondCount, TOTAL*0.3, TOTAL/25)
 	assert.InDelta(t, thirdCount, TOTAL*0.6, TOTAL/25)
 }
+
+func TestChooseAreaProgram(t *testing.T) {
+	target := getTarget(t, targets.TestOS, targets.TestArch64)
+	corpus := NewFocusedCorpus(context.Background(), nil, []FocusArea{
+		{CoverPCs: map[uint64]struct{}{0: {}, 1: {}}, Weight: 1},
+		{CoverPCs: map[uint64]struct{}{2: {}, 3: {}}, Weight: 1},
+		{CoverPCs: map[uint64]struct{}{4: {}}, Weight: 1},
+	})
+	rs := rand.NewSource(0)
+	first := map[*prog.Prog]bool{}
+	for i := 0; i < 10; i++ {
+		inp := generateRangedInput(target, rs, 0, 1)
+		first[inp.Prog] = true
+		corpus.Save(inp)
+	}
+	for i := 0; i < 10; i++ {
+		corpus.Save(generateRangedInput(target, rs, 2, 3))
+	}
+	rnd := rand.New(rs)
+	for i := 0; i < 100; i++ {
+		assert.True(t, first[corpus.ChooseAreaProgram(rnd, 0)])
+	}
+	// The area has no programs yet.
+	assert.Nil(t, corpus.ChooseAreaProgram(rnd, 2))
+	assert.Nil(t, corpus.ChooseAreaProgram(rnd, 3))
+}
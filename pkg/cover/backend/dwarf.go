@@ -37,6 +37,7 @@ type dwarfParams struct {
 	readModuleCoverPoints func(*targets.Target, *vminfo.KernelModule, *symbolInfo) ([2][]uint64, error)
 	readTextRanges        func(*vminfo.KernelModule) ([]pcRange, []*CompileUnit, error)
 	getCompilerVersion    func(string) string
+	symbolizer            *symbolizer.Config
 }
 
 type Arch struct {
@@ -253,7 +254,7 @@ func makeDWARFUnsafe(params *dwarfParams) (*Impl, error) {
 		Units:   allUnits,
 		Symbols: allSymbols,
 		Symbolize: func(pcs map[*vminfo.KernelModule][]uint64) ([]*Frame, error) {
-			return symbolize(target, &interner, kernelDirs, splitBuildDelimiters, pcs)
+			return symbolize(target, params.symbolizer, &interner, kernelDirs, splitBuildDelimiters, pcs)
 		},
 		CallbackPoints:  allCoverPoints[0],
 		PreciseCoverage: preciseCoverage,
@@ -495,8 +496,9 @@ func rustRanges(debugInfo *dwarf.Data, ent *dwarf.Entry) ([]rustRange, error) {
 	return ret, nil
 }
 
-func symbolizeModule(target *targets.Target, interner *symbolizer.Interner, kernelDirs *mgrconfig.KernelDirs,
-	splitBuildDelimiters []string, mod *vminfo.KernelModule, pcs []uint64) ([]*Frame, error) {
+func symbolizeModule(target *targets.Target, symbCfg *symbolizer.Config, interner *symbolizer.Interner,
+	kernelDirs *mgrconfig.KernelDirs, splitBuildDelimiters []string, mod *vminfo.KernelModule,
+	pcs []uint64) ([]*Frame, error) {
 	procs := min(runtime.GOMAXPROCS(0)/2, len(pcs)/1000)
 	const (
 		minProcs = 1
@@ -505,77 +507,45 @@ func symbolizeModule(target *targets.Target, interner *symbolizer.Interner, kern
 	// addr2line on a beefy vmlinux takes up to 1.6GB of RAM, so don't create too many of them.
 	procs = min(procs, maxProcs)
 	procs = max(procs, minProcs)
-	type symbolizerResult struct {
-		frames []symbolizer.Frame
-		err    error
-	}
-	symbolizerC := make(chan symbolizerResult, procs)
-	pcchan := make(chan []uint64, procs)
-	for p := 0; p < procs; p++ {
-		go func() {
-			symb := symbolizer.Make(target)
-			defer symb.Close()
-			var res symbolizerResult
-			for pcs := range pcchan {
-				for i, pc := range pcs {
-					if mod.Name == "" {
-						pcs[i] = pc
-					} else {
-						pcs[i] = pc - mod.Addr
-					}
-				}
-				frames, err := symb.Symbolize(mod.Path, pcs...)
-				if err != nil {
-					res.err = fmt.Errorf("failed to symbolize: %w", err)
-				}
-				res.frames = append(res.frames, frames...)
-			}
-			symbolizerC <- res
-		}()
+	if mod.Name != "" {
+		relPCs := make([]uint64, len(pcs))
+		for i, pc := range pcs {
+			relPCs[i] = pc - mod.Addr
+		}
+		pcs = relPCs
 	}
-	for i := 0; i < len(pcs); {
-		end := min(i+100, len(pcs))
-		pcchan <- pcs[i:end]
-		i = end
+	symbFrames, err := symbolizer.SymbolizeBatch(target, symbCfg, mod.Path, pcs, procs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to symbolize: %w", err)
 	}
-	close(pcchan)
-	var err0 error
 	var frames []*Frame
-	for p := 0; p < procs; p++ {
-		res := <-symbolizerC
-		if res.err != nil {
-			err0 = res.err
-		}
-		for _, frame := range res.frames {
-			name, path := CleanPath(frame.File, kernelDirs, splitBuildDelimiters)
-			pc := frame.PC
-			if mod.Name != "" {
-				pc = frame.PC + mod.Addr
-			}
-			frames = append(frames, &Frame{
-				Module:   mod,
-				PC:       pc,
-				Name:     interner.Do(name),
-				FuncName: frame.Func,
-				Path:     interner.Do(path),
-				Inline:   frame.Inline,
-				Range: Range{
-					StartLine: frame.Line,
-					StartCol:  0,
-					EndLine:   frame.Line,
-					EndCol:    LineEnd,
-				},
-			})
-		}
-	}
-	if err0 != nil {
-		return nil, err0
+	for _, frame := range symbFrames {
+		name, path := CleanPath(frame.File, kernelDirs, splitBuildDelimiters)
+		pc := frame.PC
+		if mod.Name != "" {
+			pc = frame.PC + mod.Addr
+		}
+		frames = append(frames, &Frame{
+			Module:   mod,
+			PC:       pc,
+			Name:     interner.Do(name),
+			FuncName: frame.Func,
+			Path:     interner.Do(path),
+			Inline:   frame.Inline,
+			Range: Range{
+				StartLine: frame.Line,
+				StartCol:  0,
+				EndLine:   frame.Line,
+				EndCol:    LineEnd,
+			},
+		})
 	}
 	return frames, nil
 }
 
-func symbolize(target *targets.Target, interner *symbolizer.Interner, kernelDirs *mgrconfig.KernelDirs,
-	splitBuildDelimiters []string, pcs map[*vminfo.KernelModule][]uint64) ([]*Frame, error) {
+func symbolize(target *targets.Target, symbCfg *symbolizer.Config, interner *symbolizer.Interner,
+	kernelDirs *mgrconfig.KernelDirs, splitBuildDelimiters []string,
+	pcs map[*vminfo.KernelModule][]uint64) ([]*Frame, error) {
 	var frames []*Frame
 	type frameResult struct {
 		frames []*Frame
@@ -584,7 +554,7 @@ func symbolize(target *targets.Target, interner *symbolizer.Interner, kernelDirs
 	frameC := make(chan frameResult, len(pcs))
 	for mod, pcs1 := range pcs {
 		go func(mod *vminfo.KernelModule, pcs []uint64) {
-			frames, err := symbolizeModule(target, interner, kernelDirs, splitBuildDelimiters, mod, pcs)
+			frames, err := symbolizeModule(target, symbCfg, interner, kernelDirs, splitBuildDelimiters, mod, pcs)
 			frameC <- frameResult{frames: frames, err: err}
 		}(mod, pcs1)
 	}
@@ -13,12 +13,13 @@ import (
 
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/symbolizer"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/sys/targets"
 )
 
 func makeELF(target *targets.Target, kernelDirs *mgrconfig.KernelDirs, splitBuildDelimiters, moduleObj []string,
-	hostModules []*vminfo.KernelModule) (*Impl, error) {
+	hostModules []*vminfo.KernelModule, symb *symbolizer.Config) (*Impl, error) {
 	return makeDWARF(&dwarfParams{
 		target:                target,
 		kernelDirs:            kernelDirs,
@@ -30,6 +31,7 @@ func makeELF(target *targets.Target, kernelDirs *mgrconfig.KernelDirs, splitBuil
 		readModuleCoverPoints: elfReadModuleCoverPoints,
 		readTextRanges:        elfReadTextRanges,
 		getCompilerVersion:    elfGetCompilerVersion,
+		symbolizer:            symb,
 	})
 }
 
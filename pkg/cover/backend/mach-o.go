@@ -11,12 +11,13 @@ import (
 	"strings"
 
 	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/symbolizer"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/sys/targets"
 )
 
 func makeMachO(target *targets.Target, kernelDirs *mgrconfig.KernelDirs,
-	moduleObj []string, hostModules []*vminfo.KernelModule) (*Impl, error) {
+	moduleObj []string, hostModules []*vminfo.KernelModule, symb *symbolizer.Config) (*Impl, error) {
 	return makeDWARF(&dwarfParams{
 		target:                target,
 		kernelDirs:            kernelDirs,
@@ -26,6 +27,7 @@ func makeMachO(target *targets.Target, kernelDirs *mgrconfig.KernelDirs,
 		readTextData:          machoReadTextData,
 		readModuleCoverPoints: machoReadModuleCoverPoints,
 		readTextRanges:        machoReadTextRanges,
+		symbolizer:            symb,
 	})
 }
 
@@ -74,6 +74,10 @@ func generateSandboxFunctionSignature(sandboxName string, sandboxArg int) string
 
 func (ctx *context) generateSource() ([]byte, error) {
 	ctx.filterCalls()
+	if ctx.opts.Concurrent > len(ctx.p.Calls) {
+		return nil, fmt.Errorf("csource: option Concurrent=%v exceeds the number of calls %v",
+			ctx.opts.Concurrent, len(ctx.p.Calls))
+	}
 	calls, vars, err := ctx.generateProgCalls(ctx.p, ctx.opts.Trace, ctx.opts.CallComments)
 	if err != nil {
 		return nil, err
@@ -130,6 +134,7 @@ func (ctx *context) generateSource() ([]byte, error) {
 	}
 	timeouts := ctx.sysTarget.Timeouts(ctx.opts.Slowdown)
 	replacements["PROGRAM_TIMEOUT_MS"] = fmt.Sprint(int(timeouts.Program / time.Millisecond))
+	replacements["CONCURRENT_SPLIT"] = fmt.Sprint(ctx.opts.Concurrent)
 	timeoutExpr := fmt.Sprint(int(timeouts.Syscall / time.Millisecond))
 	replacements["BASE_CALL_TIMEOUT_MS"] = timeoutExpr
 	for i, call := range ctx.p.Calls {
@@ -181,6 +186,9 @@ func (ctx *context) filterCalls() {
 			p = ctx.p.Clone()
 		}
 		p.RemoveCall(i)
+		if i < ctx.opts.Concurrent {
+			ctx.opts.Concurrent--
+		}
 	}
 	ctx.p = p
 }
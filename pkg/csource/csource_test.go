@@ -95,6 +95,18 @@ func testTarget(t *testing.T, target *prog.Target, full bool) {
 		p.Calls = append(p.Calls, minimized.Calls...)
 		opts = allOptionsPermutations(target.OS)
 	}
+	// Concurrent depends on the program, so it's not enumerated by allOptions* functions.
+	concurrent := Options{
+		Threaded:   true,
+		Repeat:     true,
+		Sandbox:    "none",
+		UseTmpDir:  true,
+		Slowdown:   1,
+		Concurrent: len(p.Calls) / 2,
+	}
+	if concurrent.Check(target.OS) == nil {
+		opts = append(opts, concurrent)
+	}
 	// Test various call properties.
 	if len(p.Calls) > 0 {
 		p.Calls[0].Props.FailNth = 1
@@ -8,7 +8,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/google/syzkaller/pkg/flatrpc"
@@ -53,6 +55,15 @@ type Options struct {
 
 	CallComments bool `json:"callcomments,omitempty"`
 
+	// If non-0, the program consists of two parts that are executed concurrently
+	// on different threads/procs: the first Concurrent calls and the rest of the calls.
+	Concurrent int `json:"concurrent,omitempty"`
+
+	// If set, syz-execprog runs explicitly disable the features that are not enabled above.
+	// Otherwise syz-execprog uses its default set of features (older repro options
+	// may lack the newer feature fields, and older syz-execprog may not know them).
+	ExplicitFeatures bool `json:"explicit_features,omitempty"`
+
 	LegacyOptions
 }
 
@@ -77,6 +88,12 @@ func (opts Options) Check(OS string) error {
 		// Collide requires threaded.
 		return errors.New("option Collide without Threaded")
 	}
+	if opts.Concurrent < 0 {
+		return errors.New("negative option Concurrent")
+	}
+	if opts.Concurrent != 0 && (!opts.Threaded || opts.Collide) {
+		return errors.New("option Concurrent without Threaded or with Collide")
+	}
 	if !opts.Repeat {
 		if opts.Procs > 1 {
 			// This does not affect generated code.
@@ -284,6 +301,73 @@ func DeserializeOptions(data []byte) (Options, error) {
 	return opts, err
 }
 
+// ParseOptions parses the options from the "# {Threaded:true Repeat:true ...}" header
+// that syz-manager writes at the beginning of the syz reproducers it saves.
+// The options that are not present in the header keep their default values.
+func ParseOptions(prog []byte) (Options, error) {
+	line, _, _ := bytes.Cut(prog, []byte{'\n'})
+	line = bytes.TrimSpace(bytes.TrimPrefix(line, []byte("#")))
+	if !bytes.HasPrefix(line, []byte("{")) || !bytes.HasSuffix(line, []byte("}")) {
+		return Options{}, errors.New("no options header")
+	}
+	return parseHeader(string(line[1 : len(line)-1]))
+}
+
+// parseHeader parses the "{Threaded:true Repeat:true ... LegacyOptions:{Collide:false ...}}"
+// form of the options, i.e. the options struct printed with %+v:
+// string values must not contain spaces, colons or braces and the only nested struct is LegacyOptions.
+func parseHeader(data string) (Options, error) {
+	opts := Options{
+		Slowdown: 1,
+		CloseFDs: true,
+	}
+	const legacyPrefix = "LegacyOptions:{"
+	if pos := strings.Index(data, legacyPrefix); pos != -1 {
+		if !strings.HasSuffix(data, "}") {
+			return opts, errors.New("bad options header: unterminated LegacyOptions")
+		}
+		data = data[:pos] + data[pos+len(legacyPrefix):len(data)-1]
+	}
+	if strings.ContainsAny(data, "{}") {
+		return opts, errors.New("bad options header: unexpected nested struct")
+	}
+	data = strings.ReplaceAll(data, "Sandbox: ", "Sandbox:empty ")
+	val := reflect.ValueOf(&opts).Elem()
+	for _, token := range strings.Fields(data) {
+		key, value, ok := strings.Cut(token, ":")
+		if !ok || strings.Contains(value, ":") {
+			return opts, fmt.Errorf("bad options token %q", token)
+		}
+		field := val.FieldByName(key)
+		if !field.IsValid() {
+			// Options that were removed since the reproducer was saved.
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Bool:
+			v, err := strconv.ParseBool(value)
+			if err != nil {
+				return opts, fmt.Errorf("bad value of %v: %w", key, err)
+			}
+			field.SetBool(v)
+		case reflect.Int:
+			v, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return opts, fmt.Errorf("bad value of %v: %w", key, err)
+			}
+			field.SetInt(v)
+		case reflect.String:
+			if value == "empty" {
+				value = ""
+			}
+			field.SetString(value)
+		default:
+			return opts, fmt.Errorf("unsupported option %v", key)
+		}
+	}
+	return opts, nil
+}
+
 type Feature struct {
 	Description string
 	Enabled     bool
@@ -360,6 +444,57 @@ func PrintAvailableFeaturesFlags() {
 	}
 }
 
+// featureFields maps the feature names used by ParseFeaturesFlags to the corresponding Options fields.
+func (opts *Options) featureFields() map[string]*bool {
+	return map[string]*bool{
+		"tun":         &opts.NetInjection,
+		"net_dev":     &opts.NetDevices,
+		"net_reset":   &opts.NetReset,
+		"cgroups":     &opts.Cgroups,
+		"binfmt_misc": &opts.BinfmtMisc,
+		"close_fds":   &opts.CloseFDs,
+		"devlink_pci": &opts.DevlinkPCI,
+		"nic_vf":      &opts.NicVF,
+		"usb":         &opts.USB,
+		"vhci":        &opts.VhciInjection,
+		"wifi":        &opts.Wifi,
+		"ieee802154":  &opts.IEEE802154,
+		"sysctl":      &opts.Sysctl,
+		"swap":        &opts.Swap,
+	}
+}
+
+// EnabledFeatures returns the sorted names of the features enabled in opts.
+func (opts Options) EnabledFeatures() []string {
+	return opts.featureNames(true)
+}
+
+// DisabledFeatures returns the sorted names of the features disabled in opts
+// (suitable for the -disable flag of syz-execprog and syz-prog2c).
+func (opts Options) DisabledFeatures() []string {
+	return opts.featureNames(false)
+}
+
+func (opts Options) featureNames(enabled bool) []string {
+	var names []string
+	for name, field := range opts.featureFields() {
+		if *field == enabled {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DisableFeature disables the feature with the given name (see EnabledFeatures).
+func (opts *Options) DisableFeature(name string) {
+	field := opts.featureFields()[name]
+	if field == nil {
+		panic(fmt.Sprintf("unknown feature %q", name))
+	}
+	*field = false
+}
+
 // This is the main configuration used by executor, only for testing.
 var ExecutorOpts = Options{
 	Threaded:  true,
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/google/syzkaller/sys/targets"
@@ -267,7 +268,9 @@ func enumerateField(OS string, opt Options, field int) []Options {
 			fld.SetInt(val)
 			opts = append(opts, opt)
 		}
-	} else if fldName == "LegacyOptions" {
+	} else if fldName == "LegacyOptions" || fldName == "Concurrent" || fldName == "ExplicitFeatures" {
+		// Concurrent depends on the program, it's tested separately.
+		// ExplicitFeatures does not affect the generated C code.
 		opts = append(opts, opt)
 	} else if fld.Kind() == reflect.Bool {
 		for _, v := range []bool{false, true} {
@@ -286,6 +289,42 @@ func enumerateField(OS string, opt Options, field int) []Options {
 	return checked
 }
 
+func TestParseOptionsHeader(t *testing.T) {
+	for _, opts := range allOptionsSingle(targets.Linux) {
+		data := []byte(fmt.Sprintf("# %+v\ngetpid()\n", opts))
+		got, err := ParseOptions(data)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", data, err)
+		}
+		if !reflect.DeepEqual(got, opts) {
+			t.Fatalf("opts changed, got:\n%+v\nwant:\n%+v", got, opts)
+		}
+	}
+	if _, err := ParseOptions([]byte("getpid()\n")); err == nil {
+		t.Fatalf("parsed options of a program without a header")
+	}
+	// A header saved before some options were added keeps the defaults for them.
+	got, err := ParseOptions([]byte("# {Threaded:true Repeat:true Procs:2 Sandbox:none}\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Options{Threaded: true, Repeat: true, Procs: 2, Slowdown: 1, Sandbox: "none", CloseFDs: true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("opts changed, got:\n%+v\nwant:\n%+v", got, want)
+	}
+	for _, data := range []string{
+		"# {Threaded:true Sandbox:a:b}",
+		"# {Threaded:true Nested:{Foo:true}}",
+		"# {Threaded:true LegacyOptions:{Collide:false}",
+		"# {Threaded:yes}",
+		"# {Threaded}",
+	} {
+		if _, err := ParseOptions([]byte(data)); err == nil {
+			t.Errorf("parsed bad header %q", data)
+		}
+	}
+}
+
 func TestParseFeaturesFlags(t *testing.T) {
 	tests := []struct {
 		Enable   string
@@ -382,3 +421,32 @@ func TestParseFeaturesFlags(t *testing.T) {
 		}
 	}
 }
+
+func TestFeatureNames(t *testing.T) {
+	opts := Options{}
+	if len(opts.featureFields()) != len(defaultFeatures(false)) {
+		t.Fatalf("featureFields and defaultFeatures are out of sync")
+	}
+	opts = Options{
+		NetInjection: true,
+		Cgroups:      true,
+		Swap:         true,
+	}
+	opts.DisableFeature("cgroups")
+	if got, want := fmt.Sprint(opts.EnabledFeatures()), "[swap tun]"; got != want {
+		t.Fatalf("enabled features: got %v, want %v", got, want)
+	}
+	disabled := opts.DisabledFeatures()
+	if len(disabled)+2 != len(defaultFeatures(false)) {
+		t.Fatalf("bad disabled features: %v", disabled)
+	}
+	features, err := ParseFeaturesFlags("none", strings.Join(disabled, ","), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, feature := range features {
+		if want := *opts.featureFields()[name]; feature.Enabled != want {
+			t.Fatalf("feature %v: got %v, want %v", name, feature.Enabled, want)
+		}
+	}
+}
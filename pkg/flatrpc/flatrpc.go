@@ -394,6 +394,7 @@ const (
 	ExecFlagDedupCover    ExecFlag = 4
 	ExecFlagCollectComps  ExecFlag = 8
 	ExecFlagThreaded      ExecFlag = 16
+	ExecFlagBarrier       ExecFlag = 32
 )
 
 var EnumNamesExecFlag = map[ExecFlag]string{
@@ -402,6 +403,7 @@ var EnumNamesExecFlag = map[ExecFlag]string{
 	ExecFlagDedupCover:    "DedupCover",
 	ExecFlagCollectComps:  "CollectComps",
 	ExecFlagThreaded:      "Threaded",
+	ExecFlagBarrier:       "Barrier",
 }
 
 var EnumValuesExecFlag = map[string]ExecFlag{
@@ -410,6 +412,7 @@ var EnumValuesExecFlag = map[string]ExecFlag{
 	"DedupCover":    ExecFlagDedupCover,
 	"CollectComps":  ExecFlagCollectComps,
 	"Threaded":      ExecFlagThreaded,
+	"Barrier":       ExecFlagBarrier,
 }
 
 func (v ExecFlag) String() string {
@@ -3489,12 +3492,17 @@ type SnapshotHandshakeT struct {
 	Features         Feature `json:"features"`
 	EnvFlags         ExecEnv `json:"env_flags"`
 	SandboxArg       int64   `json:"sandbox_arg"`
+	Warmup           []byte  `json:"warmup"`
 }
 
 func (t *SnapshotHandshakeT) Pack(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	if t == nil {
 		return 0
 	}
+	warmupOffset := flatbuffers.UOffsetT(0)
+	if t.Warmup != nil {
+		warmupOffset = builder.CreateByteString(t.Warmup)
+	}
 	SnapshotHandshakeStart(builder)
 	SnapshotHandshakeAddCoverEdges(builder, t.CoverEdges)
 	SnapshotHandshakeAddKernel64Bit(builder, t.Kernel64Bit)
@@ -3504,6 +3512,7 @@ func (t *SnapshotHandshakeT) Pack(builder *flatbuffers.Builder) flatbuffers.UOff
 	SnapshotHandshakeAddFeatures(builder, t.Features)
 	SnapshotHandshakeAddEnvFlags(builder, t.EnvFlags)
 	SnapshotHandshakeAddSandboxArg(builder, t.SandboxArg)
+	SnapshotHandshakeAddWarmup(builder, warmupOffset)
 	return SnapshotHandshakeEnd(builder)
 }
 
@@ -3516,6 +3525,7 @@ func (rcv *SnapshotHandshake) UnPackTo(t *SnapshotHandshakeT) {
 	t.Features = rcv.Features()
 	t.EnvFlags = rcv.EnvFlags()
 	t.SandboxArg = rcv.SandboxArg()
+	t.Warmup = rcv.WarmupBytes()
 }
 
 func (rcv *SnapshotHandshake) UnPack() *SnapshotHandshakeT {
@@ -3650,8 +3660,42 @@ func (rcv *SnapshotHandshake) MutateSandboxArg(n int64) bool {
 	return rcv._tab.MutateInt64Slot(18, n)
 }
 
+func (rcv *SnapshotHandshake) Warmup(j int) byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetByte(a + flatbuffers.UOffsetT(j*1))
+	}
+	return 0
+}
+
+func (rcv *SnapshotHandshake) WarmupLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *SnapshotHandshake) WarmupBytes() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *SnapshotHandshake) MutateWarmup(j int, n byte) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(20))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateByte(a+flatbuffers.UOffsetT(j*1), n)
+	}
+	return false
+}
+
 func SnapshotHandshakeStart(builder *flatbuffers.Builder) {
-	builder.StartObject(8)
+	builder.StartObject(9)
 }
 func SnapshotHandshakeAddCoverEdges(builder *flatbuffers.Builder, coverEdges bool) {
 	builder.PrependBoolSlot(0, coverEdges, false)
@@ -3677,6 +3721,12 @@ func SnapshotHandshakeAddEnvFlags(builder *flatbuffers.Builder, envFlags ExecEnv
 func SnapshotHandshakeAddSandboxArg(builder *flatbuffers.Builder, sandboxArg int64) {
 	builder.PrependInt64Slot(7, sandboxArg, 0)
 }
+func SnapshotHandshakeAddWarmup(builder *flatbuffers.Builder, warmup flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(8, flatbuffers.UOffsetT(warmup), 0)
+}
+func SnapshotHandshakeStartWarmupVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(1, numElems, 1)
+}
 func SnapshotHandshakeEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -638,24 +638,26 @@ enum class ExecFlag : uint64_t {
   DedupCover = 4ULL,
   CollectComps = 8ULL,
   Threaded = 16ULL,
+  Barrier = 32ULL,
   NONE = 0,
-  ANY = 31ULL
+  ANY = 63ULL
 };
 FLATBUFFERS_DEFINE_BITMASK_OPERATORS(ExecFlag, uint64_t)
 
-inline const ExecFlag (&EnumValuesExecFlag())[5] {
+inline const ExecFlag (&EnumValuesExecFlag())[6] {
   static const ExecFlag values[] = {
     ExecFlag::CollectSignal,
     ExecFlag::CollectCover,
     ExecFlag::DedupCover,
     ExecFlag::CollectComps,
-    ExecFlag::Threaded
+    ExecFlag::Threaded,
+    ExecFlag::Barrier
   };
   return values;
 }
 
 inline const char * const *EnumNamesExecFlag() {
-  static const char * const names[17] = {
+  static const char * const names[33] = {
     "CollectSignal",
     "CollectCover",
     "",
@@ -672,13 +674,29 @@ inline const char * const *EnumNamesExecFlag() {
     "",
     "",
     "Threaded",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "",
+    "Barrier",
     nullptr
   };
   return names;
 }
 
 inline const char *EnumNameExecFlag(ExecFlag e) {
-  if (flatbuffers::IsOutRange(e, ExecFlag::CollectSignal, ExecFlag::Threaded)) return "";
+  if (flatbuffers::IsOutRange(e, ExecFlag::CollectSignal, ExecFlag::Barrier)) return "";
   const size_t index = static_cast<size_t>(e) - static_cast<size_t>(ExecFlag::CollectSignal);
   return EnumNamesExecFlag()[index];
 }
@@ -2738,6 +2756,7 @@ struct SnapshotHandshakeT : public flatbuffers::NativeTable {
   rpc::Feature features = static_cast<rpc::Feature>(0);
   rpc::ExecEnv env_flags = static_cast<rpc::ExecEnv>(0);
   int64_t sandbox_arg = 0;
+  std::vector<uint8_t> warmup{};
 };
 
 struct SnapshotHandshake FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
@@ -2751,7 +2770,8 @@ struct SnapshotHandshake FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
     VT_PROGRAM_TIMEOUT_MS = 12,
     VT_FEATURES = 14,
     VT_ENV_FLAGS = 16,
-    VT_SANDBOX_ARG = 18
+    VT_SANDBOX_ARG = 18,
+    VT_WARMUP = 20
   };
   bool cover_edges() const {
     return GetField<uint8_t>(VT_COVER_EDGES, 0) != 0;
@@ -2777,6 +2797,9 @@ struct SnapshotHandshake FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
   int64_t sandbox_arg() const {
     return GetField<int64_t>(VT_SANDBOX_ARG, 0);
   }
+  const flatbuffers::Vector<uint8_t> *warmup() const {
+    return GetPointer<const flatbuffers::Vector<uint8_t> *>(VT_WARMUP);
+  }
   bool Verify(flatbuffers::Verifier &verifier) const {
     return VerifyTableStart(verifier) &&
            VerifyField<uint8_t>(verifier, VT_COVER_EDGES, 1) &&
@@ -2787,6 +2810,8 @@ struct SnapshotHandshake FLATBUFFERS_FINAL_CLASS : private flatbuffers::Table {
            VerifyField<uint64_t>(verifier, VT_FEATURES, 8) &&
            VerifyField<uint64_t>(verifier, VT_ENV_FLAGS, 8) &&
            VerifyField<int64_t>(verifier, VT_SANDBOX_ARG, 8) &&
+           VerifyOffset(verifier, VT_WARMUP) &&
+           verifier.VerifyVector(warmup()) &&
            verifier.EndTable();
   }
   SnapshotHandshakeT *UnPack(const flatbuffers::resolver_function_t *_resolver = nullptr) const;
@@ -2822,6 +2847,9 @@ struct SnapshotHandshakeBuilder {
   void add_sandbox_arg(int64_t sandbox_arg) {
     fbb_.AddElement<int64_t>(SnapshotHandshake::VT_SANDBOX_ARG, sandbox_arg, 0);
   }
+  void add_warmup(flatbuffers::Offset<flatbuffers::Vector<uint8_t>> warmup) {
+    fbb_.AddOffset(SnapshotHandshake::VT_WARMUP, warmup);
+  }
   explicit SnapshotHandshakeBuilder(flatbuffers::FlatBufferBuilder &_fbb)
         : fbb_(_fbb) {
     start_ = fbb_.StartTable();
@@ -2842,11 +2870,13 @@ inline flatbuffers::Offset<SnapshotHandshake> CreateSnapshotHandshake(
     int32_t program_timeout_ms = 0,
     rpc::Feature features = static_cast<rpc::Feature>(0),
     rpc::ExecEnv env_flags = static_cast<rpc::ExecEnv>(0),
-    int64_t sandbox_arg = 0) {
+    int64_t sandbox_arg = 0,
+    flatbuffers::Offset<flatbuffers::Vector<uint8_t>> warmup = 0) {
   SnapshotHandshakeBuilder builder_(_fbb);
   builder_.add_sandbox_arg(sandbox_arg);
   builder_.add_env_flags(env_flags);
   builder_.add_features(features);
+  builder_.add_warmup(warmup);
   builder_.add_program_timeout_ms(program_timeout_ms);
   builder_.add_syscall_timeout_ms(syscall_timeout_ms);
   builder_.add_slowdown(slowdown);
@@ -2855,6 +2885,31 @@ inline flatbuffers::Offset<SnapshotHandshake> CreateSnapshotHandshake(
   return builder_.Finish();
 }
 
+inline flatbuffers::Offset<SnapshotHandshake> CreateSnapshotHandshakeDirect(
+    flatbuffers::FlatBufferBuilder &_fbb,
+    bool cover_edges = false,
+    bool kernel_64_bit = false,
+    int32_t slowdown = 0,
+    int32_t syscall_timeout_ms = 0,
+    int32_t program_timeout_ms = 0,
+    rpc::Feature features = static_cast<rpc::Feature>(0),
+    rpc::ExecEnv env_flags = static_cast<rpc::ExecEnv>(0),
+    int64_t sandbox_arg = 0,
+    const std::vector<uint8_t> *warmup = nullptr) {
+  auto warmup__ = warmup ? _fbb.CreateVector<uint8_t>(*warmup) : 0;
+  return rpc::CreateSnapshotHandshake(
+      _fbb,
+      cover_edges,
+      kernel_64_bit,
+      slowdown,
+      syscall_timeout_ms,
+      program_timeout_ms,
+      features,
+      env_flags,
+      sandbox_arg,
+      warmup__);
+}
+
 flatbuffers::Offset<SnapshotHandshake> CreateSnapshotHandshake(flatbuffers::FlatBufferBuilder &_fbb, const SnapshotHandshakeT *_o, const flatbuffers::rehasher_function_t *_rehasher = nullptr);
 
 struct SnapshotRequestT : public flatbuffers::NativeTable {
@@ -3723,6 +3778,7 @@ inline void SnapshotHandshake::UnPackTo(SnapshotHandshakeT *_o, const flatbuffer
   { auto _e = features(); _o->features = _e; }
   { auto _e = env_flags(); _o->env_flags = _e; }
   { auto _e = sandbox_arg(); _o->sandbox_arg = _e; }
+  { auto _e = warmup(); if (_e) { _o->warmup.resize(_e->size()); std::copy(_e->begin(), _e->end(), _o->warmup.begin()); } }
 }
 
 inline flatbuffers::Offset<SnapshotHandshake> SnapshotHandshake::Pack(flatbuffers::FlatBufferBuilder &_fbb, const SnapshotHandshakeT* _o, const flatbuffers::rehasher_function_t *_rehasher) {
@@ -3741,6 +3797,7 @@ inline flatbuffers::Offset<SnapshotHandshake> CreateSnapshotHandshake(flatbuffer
   auto _features = _o->features;
   auto _env_flags = _o->env_flags;
   auto _sandbox_arg = _o->sandbox_arg;
+  auto _warmup = _o->warmup.size() ? _fbb.CreateVector(_o->warmup) : 0;
   return rpc::CreateSnapshotHandshake(
       _fbb,
       _cover_edges,
@@ -3750,7 +3807,8 @@ inline flatbuffers::Offset<SnapshotHandshake> CreateSnapshotHandshake(flatbuffer
       _program_timeout_ms,
       _features,
       _env_flags,
-      _sandbox_arg);
+      _sandbox_arg,
+      _warmup);
 }
 
 inline SnapshotRequestT *SnapshotRequest::UnPack(const flatbuffers::resolver_function_t *_resolver) const {
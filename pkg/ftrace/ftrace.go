@@ -0,0 +1,84 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package ftrace generates shell scripts that set up and collect kernel tracing (tracefs)
+// on Linux targets, and extracts the trace buffer dumped on oops from the console output.
+package ftrace
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+)
+
+// Older kernels don't mount tracefs separately, it's only available in debugfs.
+const tracefsDir = "T=/sys/kernel/tracing; [ -e $T/trace ] || T=/sys/kernel/debug/tracing; "
+
+// SetupScript returns a shell script that resets the tracing state and enables
+// the configured events/functions. ftrace_dump_on_oops makes the kernel dump the trace
+// buffer to the console if it crashes, otherwise the buffer is read by CollectScript.
+func SetupScript(cfg *mgrconfig.Ftrace) string {
+	buf := new(bytes.Buffer)
+	buf.WriteString(tracefsDir)
+	buf.WriteString("echo 0 > $T/tracing_on && echo nop > $T/current_tracer && echo > $T/trace && ")
+	fmt.Fprintf(buf, "echo %v > $T/buffer_size_kb && ", cfg.BufferSizeKB)
+	buf.WriteString("echo > $T/set_event && ")
+	for _, event := range cfg.Events {
+		fmt.Fprintf(buf, "echo '%v' >> $T/set_event && ", event)
+	}
+	if len(cfg.Functions) != 0 {
+		buf.WriteString("echo > $T/set_graph_function && ")
+		for _, fn := range cfg.Functions {
+			fmt.Fprintf(buf, "echo '%v' >> $T/set_graph_function && ", fn)
+		}
+		buf.WriteString("echo function_graph > $T/current_tracer && ")
+	}
+	buf.WriteString("echo 1 > /proc/sys/kernel/ftrace_dump_on_oops && echo 1 > $T/tracing_on")
+	return buf.String()
+}
+
+// CollectScript stops tracing and prints the trace buffer.
+const CollectScript = tracefsDir + "echo 0 > $T/tracing_on; cat $T/trace"
+
+const (
+	dumpStart = "Dumping ftrace buffer:"
+	dumpDelim = "---------------------------------"
+)
+
+// ExtractDump extracts the trace buffer dumped by the kernel on oops
+// (with ftrace_dump_on_oops enabled) from the console output.
+func ExtractDump(output []byte) []byte {
+	pos := bytes.Index(output, []byte(dumpStart))
+	if pos == -1 {
+		return nil
+	}
+	// The dump is enclosed into delimiter lines, if the end is missing,
+	// the kernel did not manage to dump the whole buffer, so take everything.
+	dump := output[pos:]
+	if start := bytes.Index(dump, []byte(dumpDelim)); start != -1 {
+		if end := bytes.Index(dump[start+len(dumpDelim):], []byte(dumpDelim)); end != -1 {
+			end += start + 2*len(dumpDelim)
+			if nl := bytes.IndexByte(dump[end:], '\n'); nl != -1 {
+				end += nl + 1
+			} else {
+				end = len(dump)
+			}
+			dump = dump[:end]
+		}
+	}
+	return dump
+}
+
+// Header describes the traced events/functions, it's prepended to the saved trace.
+func Header(cfg *mgrconfig.Ftrace) []byte {
+	var what []string
+	if len(cfg.Events) != 0 {
+		what = append(what, "events: "+strings.Join(cfg.Events, " "))
+	}
+	if len(cfg.Functions) != 0 {
+		what = append(what, "functions: "+strings.Join(cfg.Functions, " "))
+	}
+	return []byte(fmt.Sprintf("ftrace %v\n\n<...>\n", strings.Join(what, ", ")))
+}
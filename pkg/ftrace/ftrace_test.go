@@ -0,0 +1,66 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package ftrace
+
+import (
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetupScript(t *testing.T) {
+	script := SetupScript(&mgrconfig.Ftrace{
+		Events:       []string{"sched:sched_switch", "kmem:*"},
+		Functions:    []string{"ext4_*"},
+		BufferSizeKB: 128,
+	})
+	assert.Equal(t, "T=/sys/kernel/tracing; [ -e $T/trace ] || T=/sys/kernel/debug/tracing; "+
+		"echo 0 > $T/tracing_on && echo nop > $T/current_tracer && echo > $T/trace && "+
+		"echo 128 > $T/buffer_size_kb && echo > $T/set_event && "+
+		"echo 'sched:sched_switch' >> $T/set_event && echo 'kmem:*' >> $T/set_event && "+
+		"echo > $T/set_graph_function && echo 'ext4_*' >> $T/set_graph_function && "+
+		"echo function_graph > $T/current_tracer && "+
+		"echo 1 > /proc/sys/kernel/ftrace_dump_on_oops && echo 1 > $T/tracing_on", script)
+}
+
+func TestExtractDump(t *testing.T) {
+	tests := []struct {
+		output string
+		dump   string
+	}{
+		{
+			output: "[   10.1] BUG: KASAN: use-after-free in foo\n",
+			dump:   "",
+		},
+		{
+			output: `[   10.1] BUG: KASAN: use-after-free in foo
+[   10.2] Dumping ftrace buffer:
+[   10.2] ---------------------------------
+[   10.3] syz-exec-100  0d... 1us : sched_switch: prev_comm=syz-executor
+[   10.3] ---------------------------------
+[   10.4] Kernel panic - not syncing: KASAN: panic_on_warn set ...
+`,
+			dump: `Dumping ftrace buffer:
+[   10.2] ---------------------------------
+[   10.3] syz-exec-100  0d... 1us : sched_switch: prev_comm=syz-executor
+[   10.3] ---------------------------------
+`,
+		},
+		{
+			// The dump is cut off.
+			output: `[   10.2] Dumping ftrace buffer:
+[   10.2] ---------------------------------
+[   10.3] syz-exec-100  0d... 1us : sched_switch: prev_comm=syz-executor
+`,
+			dump: `Dumping ftrace buffer:
+[   10.2] ---------------------------------
+[   10.3] syz-exec-100  0d... 1us : sched_switch: prev_comm=syz-executor
+`,
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.dump, string(ExtractDump([]byte(test.output))), "test #%v", i)
+	}
+}
@@ -84,6 +84,7 @@ type execQueues struct {
 	candidateQueue       *queue.PlainQueue
 	triageQueue          *queue.DynamicOrderer
 	smashQueue           *queue.PlainQueue
+	skipQueue            int
 	source               queue.Source
 }
 
@@ -93,24 +94,50 @@ func newExecQueues(fuzzer *Fuzzer) execQueues {
 		candidateQueue:       queue.Plain(),
 		triageQueue:          queue.DynamicOrder(),
 		smashQueue:           queue.Plain(),
+		// Alternate smash jobs with exec/fuzz to spread attention to the wider area.
+		skipQueue: 3,
 	}
-	// Alternate smash jobs with exec/fuzz to spread attention to the wider area.
-	skipQueue := 3
 	if fuzzer.Config.PatchTest {
 		// When we do patch fuzzing, we do not focus on finding and persisting
 		// new coverage that much, so it's reasonable to spend more time just
 		// mutating various corpus programs.
-		skipQueue = 2
+		ret.skipQueue = 2
 	}
+	ret.source = ret.order(fuzzer.genFuzz)
+	return ret
+}
+
+func (eq *execQueues) order(genFuzz func() *queue.Request) queue.Source {
 	// Sources are listed in the order, in which they will be polled.
-	ret.source = queue.Order(
-		ret.triageCandidateQueue,
-		ret.candidateQueue,
-		ret.triageQueue,
-		queue.Alternate(ret.smashQueue, skipQueue),
-		queue.Callback(fuzzer.genFuzz),
+	return queue.Order(
+		eq.triageCandidateQueue,
+		eq.candidateQueue,
+		eq.triageQueue,
+		queue.Alternate(eq.smashQueue, eq.skipQueue),
+		queue.Callback(genFuzz),
 	)
-	return ret
+}
+
+// FocusAreaSource returns a source that serves the same requests as the fuzzer itself,
+// except that the fuzzed programs are only mutated from the corpus programs of the focus area
+// with the given index (e.g. because the VM state is prepared for that area).
+// If prefix is not nil, all fuzzed programs start with the prefix calls, and their resources
+// are bound to the resources produced by the prefix (see prog.Prog.WithPrefix).
+func (fuzzer *Fuzzer) FocusAreaSource(area int, prefix *prog.Prog) queue.Source {
+	return fuzzer.execQueues.order(func() *queue.Request {
+		req := fuzzer.genFuzzFrom(func(rnd *rand.Rand) *prog.Prog {
+			return fuzzer.Config.Corpus.ChooseAreaProgram(rnd, area)
+		})
+		if prefix != nil && !req.Prog.HasPrefix(prefix) {
+			// Mutated programs of the area normally already start with the prefix.
+			p := req.Prog.WithPrefix(prefix)
+			for len(p.Calls) > prog.MaxCalls {
+				p.RemoveCall(len(p.Calls) - 1)
+			}
+			req.Prog = p
+		}
+		return req
+	})
 }
 
 func (fuzzer *Fuzzer) CandidatesToTriage() int {
@@ -279,6 +306,10 @@ func signalPrio(p *prog.Prog, info *flatrpc.CallInfo, call int) (prio uint8) {
 }
 
 func (fuzzer *Fuzzer) genFuzz() *queue.Request {
+	return fuzzer.genFuzzFrom(fuzzer.Config.Corpus.ChooseProgram)
+}
+
+func (fuzzer *Fuzzer) genFuzzFrom(choose func(*rand.Rand) *prog.Prog) *queue.Request {
 	// Either generate a new input or mutate an existing one.
 	mutateRate := 0.95
 	if !fuzzer.Config.Coverage {
@@ -289,7 +320,7 @@ func (fuzzer *Fuzzer) genFuzz() *queue.Request {
 	var req *queue.Request
 	rnd := fuzzer.rand()
 	if rnd.Float64() < mutateRate {
-		req = mutateProgRequest(fuzzer, rnd)
+		req = mutateProgRequest(fuzzer, rnd, choose)
 	}
 	if req == nil {
 		req = genProgRequest(fuzzer, rnd)
@@ -92,6 +92,41 @@ func TestFuzz(t *testing.T) {
 	}
 }
 
+func TestFocusAreaSourcePrefix(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	calls := map[*prog.Syscall]bool{}
+	for _, c := range target.Syscalls {
+		calls[c] = true
+	}
+	fuzzer := NewFuzzer(ctx, &Config{
+		Corpus:       corpus.NewFocusedCorpus(ctx, nil, []corpus.FocusArea{{Weight: 1}}),
+		Coverage:     true,
+		EnabledCalls: calls,
+	}, rand.New(testutil.RandSource(t)), target)
+	prefix, err := target.Deserialize([]byte("test$res0()\n"), prog.Strict)
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := fuzzer.FocusAreaSource(0, prefix)
+	for i := 0; i < 300; i++ {
+		req := source.Next()
+		// Triage and smash requests are not changed.
+		fuzzed := req.Stat == fuzzer.statExecGenerate || req.Stat == fuzzer.statExecFuzz ||
+			req.Stat == fuzzer.statExecCollide
+		if fuzzed && !req.Prog.HasPrefix(prefix) {
+			t.Fatalf("the program does not start with the prefix:\n%s", req.Prog.Serialize())
+		}
+		assert.LessOrEqual(t, len(req.Prog.Calls), prog.MaxCalls)
+		res, _, _ := emulateExec(req)
+		req.Done(res)
+	}
+}
+
 func BenchmarkFuzzer(b *testing.B) {
 	b.ReportAllocs()
 	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64Fuzz)
@@ -52,8 +52,8 @@ func genProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
 	}
 }
 
-func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand) *queue.Request {
-	p := fuzzer.Config.Corpus.ChooseProgram(rnd)
+func mutateProgRequest(fuzzer *Fuzzer, rnd *rand.Rand, choose func(*rand.Rand) *prog.Prog) *queue.Request {
+	p := choose(rnd)
 	if p == nil {
 		return nil
 	}
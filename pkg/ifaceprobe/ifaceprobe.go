@@ -68,7 +68,7 @@ type fileDesc struct {
 }
 
 func (pr *prober) run() (*Info, error) {
-	symb := symbolizer.Make(pr.cfg.SysTarget)
+	symb := symbolizer.Make(pr.cfg.SysTarget, pr.cfg.SymbolizerConfig())
 	defer symb.Close()
 
 	for _, glob := range globList() {
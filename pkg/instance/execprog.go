@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/ftrace"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
@@ -25,6 +26,8 @@ type OptionalConfig struct {
 	OldFlagsCompatMode bool
 	BeforeContextLen   int
 	StraceBin          string
+	// If set, the program is run with kernel tracing enabled and RunResult.Trace contains the trace.
+	Ftrace *mgrconfig.Ftrace
 }
 
 type ExecProgInstance struct {
@@ -40,6 +43,9 @@ type RunResult struct {
 	Output   []byte
 	Report   *report.Report
 	Duration time.Duration
+	Trace    []byte // only if OptionalConfig.Ftrace is set
+	// Tracing setup/collection errors don't fail the run, they are recorded here instead.
+	TraceError error
 }
 
 const (
@@ -120,6 +126,13 @@ func (inst *ExecProgInstance) runCommand(command string, duration time.Duration,
 		command = inst.StraceBin + filterCalls + ` -s 100 -x -f ` + command
 		prefixOutput = []byte(fmt.Sprintf("%s\n\n<...>\n", command))
 	}
+	var traceErr error
+	if inst.Ftrace != nil {
+		if _, err := inst.runFtraceScript(ftrace.SetupScript(inst.Ftrace)); err != nil {
+			traceErr = fmt.Errorf("failed to set up ftrace: %w", err)
+			inst.Logf(0, "%v", traceErr)
+		}
+	}
 	optionalBeforeContext := func(*vm.RunOptions) {}
 	if inst.BeforeContextLen != 0 {
 		optionalBeforeContext = vm.WithBeforeContext(inst.BeforeContextLen)
@@ -145,11 +158,27 @@ func (inst *ExecProgInstance) runCommand(command string, duration time.Duration,
 		}
 		inst.Logf(2, "program crashed: %v", rep.Title)
 	}
-	return &RunResult{
+	res := &RunResult{
 		Output:   append(prefixOutput, output...),
 		Report:   rep,
 		Duration: time.Since(start),
-	}, nil
+	}
+	if inst.Ftrace != nil && traceErr == nil {
+		trace := ftrace.ExtractDump(output)
+		if rep == nil {
+			// The kernel is still alive, so read the buffer directly.
+			trace, err = inst.runFtraceScript(ftrace.CollectScript)
+			if err != nil {
+				traceErr = fmt.Errorf("failed to collect ftrace: %w", err)
+				inst.Logf(0, "%v", traceErr)
+			}
+		}
+		if len(trace) != 0 {
+			res.Trace = append(ftrace.Header(inst.Ftrace), trace...)
+		}
+	}
+	res.TraceError = traceErr
+	return res, nil
 }
 
 func (inst *ExecProgInstance) runBinary(bin string, duration time.Duration) (*RunResult, error) {
@@ -0,0 +1,27 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package instance
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/syzkaller/vm"
+)
+
+// runFtraceScript runs a tracing setup/collection script in the VM and returns its output.
+func (inst *ExecProgInstance) runFtraceScript(script string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*inst.mgrCfg.Timeouts.Scale)
+	defer cancel()
+	output, reps, err := inst.VMInstance.Run(ctx, inst.reporter, script, vm.WithExitCondition(vm.ExitNormal))
+	if err != nil {
+		return nil, err
+	}
+	if len(reps) != 0 {
+		return nil, fmt.Errorf("%v:\n%s", reps[0].Title, bytes.TrimSpace(reps[0].Output))
+	}
+	return output, nil
+}
@@ -476,22 +476,32 @@ func ExecprogCmd(execprog, executor, OS, arch, vmType string, opts csource.Optio
 	if targets.Get(OS, arch).HostFuzzer {
 		osArg = " -os=" + OS
 	}
+	procs := opts.Procs
 	optionalArg := ""
+	if opts.Concurrent != 0 {
+		// The two parts of the program must be executed on different procs.
+		procs = max(procs, 2)
+		optionalArg += fmt.Sprintf(" -concurrent=%v", opts.Concurrent)
+	}
 	if opts.Fault && opts.FaultCall >= 0 {
-		optionalArg = fmt.Sprintf(" -fault_call=%v -fault_nth=%v",
+		optionalArg += fmt.Sprintf(" -fault_call=%v -fault_nth=%v",
 			opts.FaultCall, opts.FaultNth)
 	}
 	if optionalFlags {
-		optionalArg += " " + tool.OptionalFlags([]tool.Flag{
+		flags := []tool.Flag{
 			{Name: "slowdown", Value: fmt.Sprint(slowdown)},
 			{Name: "sandbox_arg", Value: fmt.Sprint(opts.SandboxArg)},
 			{Name: "type", Value: fmt.Sprint(vmType)},
-		})
+		}
+		if disabled := opts.DisabledFeatures(); opts.ExplicitFeatures && len(disabled) != 0 {
+			flags = append(flags, tool.Flag{Name: "disable", Value: strings.Join(disabled, ",")})
+		}
+		optionalArg += " " + tool.OptionalFlags(flags)
 	}
 	return fmt.Sprintf("%v -executor=%v -arch=%v%v -sandbox=%v"+
 		" -procs=%v -repeat=%v -threaded=%v -collide=%v -cover=0%v %v",
 		execprog, executor, arch, osArg, sandbox,
-		opts.Procs, repeatCount, opts.Threaded, opts.Collide,
+		procs, repeatCount, opts.Threaded, opts.Collide,
 		optionalArg, progFile)
 }
 
@@ -99,6 +99,27 @@ func TestExecprogCmd(t *testing.T) {
 	}
 }
 
+func TestExecprogCmdDisable(t *testing.T) {
+	opts := csource.Options{
+		Sandbox:      "none",
+		Procs:        1,
+		NetInjection: true,
+	}
+	// Unless explicitly requested, syz-execprog keeps its default set of features.
+	cmdLine := ExecprogCmd("syz-execprog", "syz-executor", targets.Linux, targets.AMD64, "qemu",
+		opts, true, 1, "myprog")
+	if strings.Contains(cmdLine, "disable") {
+		t.Errorf("unexpected -disable flag: %v", cmdLine)
+	}
+	opts.ExplicitFeatures = true
+	cmdLine = ExecprogCmd("syz-execprog", "syz-executor", targets.Linux, targets.AMD64, "qemu",
+		opts, true, 1, "myprog")
+	if !strings.Contains(cmdLine, "disable") || !strings.Contains(cmdLine, "net_dev") ||
+		strings.Contains(cmdLine, "tun") {
+		t.Errorf("bad -disable flag: %v", cmdLine)
+	}
+}
+
 func TestRunnerCmd(t *testing.T) {
 	flags := flag.NewFlagSet("", flag.ContinueOnError)
 	flagFwdAddr := flags.String("addr", "", "verifier rpc address")
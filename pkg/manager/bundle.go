@@ -0,0 +1,134 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/symbolizer"
+	"github.com/google/syzkaller/pkg/vcs"
+)
+
+// CrashBundle describes a self-contained crash archive that can be handed over
+// to people without access to the workdir or the dashboard.
+// Besides the bundle.json file, the archive contains the files of the crash dir
+// (logs, symbolized reports, reproducers) and the kernel config.
+type CrashBundle struct {
+	Title          string
+	OS             string
+	Arch           string
+	VMType         string
+	KernelCommit   string `json:",omitempty"`
+	VmlinuxBuildID string `json:",omitempty"`
+	// The syz-execprog command that replays repro.prog with the original options.
+	// Binary paths are relative to the syzkaller bin dir in the VM.
+	ExecprogCmd string `json:",omitempty"`
+}
+
+const (
+	bundleInfoFileName   = "bundle.json"
+	bundleKernelConfName = "kernel.config"
+)
+
+// ExportBundle writes a tar.gz crash bundle for the crash id to w.
+func (cs *CrashStore) ExportBundle(id string, cfg *mgrconfig.Config, w io.Writer) error {
+	crashDir := filepath.Join(cs.BaseDir, "crashes", id)
+	desc, err := os.ReadFile(filepath.Join(crashDir, "description"))
+	if err != nil {
+		return fmt.Errorf("failed to read crash %v: %w", id, err)
+	}
+	tmpDir, err := os.MkdirTemp("", "syz-crash-bundle")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	files, err := osutil.ListDir(crashDir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		if f == memoryDumpFileName {
+			// It's too large to be shared this way, it can be downloaded separately.
+			continue
+		}
+		if err := osutil.CopyFile(filepath.Join(crashDir, f), filepath.Join(tmpDir, f)); err != nil {
+			return err
+		}
+	}
+	bundle := &CrashBundle{
+		Title:  strings.TrimSpace(string(desc)),
+		OS:     cfg.TargetOS,
+		Arch:   cfg.TargetArch,
+		VMType: cfg.Type,
+	}
+	if cfg.KernelObj != "" {
+		kernelConfig := filepath.Join(cfg.KernelObj, ".config")
+		if osutil.IsExist(kernelConfig) {
+			err := osutil.CopyFile(kernelConfig, filepath.Join(tmpDir, bundleKernelConfName))
+			if err != nil {
+				return err
+			}
+		}
+		kernelObject := filepath.Join(cfg.KernelObj, cfg.SysTarget.KernelObject)
+		if osutil.IsExist(kernelObject) {
+			// Not all kernel builds have build IDs, so it's not an error.
+			bundle.VmlinuxBuildID, _ = symbolizer.ReadBuildID(kernelObject)
+		}
+	}
+	if cfg.KernelSrc != "" {
+		repo, err := vcs.NewRepo(cfg.TargetOS, cfg.Type, cfg.KernelSrc, vcs.OptPrecious, vcs.OptDontSandbox)
+		if err == nil {
+			if commit, err := repo.Commit(vcs.HEAD); err == nil {
+				bundle.KernelCommit = commit.Hash
+			}
+		}
+	}
+	if osutil.IsExist(filepath.Join(crashDir, reproFileName)) {
+		_, opts, err := loadRepro(crashDir)
+		if err != nil {
+			return err
+		}
+		bundle.ExecprogCmd = instance.ExecprogCmd("./syz-execprog", "./syz-executor",
+			cfg.TargetOS, cfg.TargetArch, cfg.Type, opts, true, cfg.Timeouts.Slowdown, reproFileName)
+	}
+	data, err := json.MarshalIndent(bundle, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := osutil.WriteFile(filepath.Join(tmpDir, bundleInfoFileName), data); err != nil {
+		return err
+	}
+	return osutil.TarGzDirectory(tmpDir, w)
+}
+
+// ExtractBundle unpacks a bundle created by ExportBundle into dir.
+func ExtractBundle(r io.Reader, dir string) (*CrashBundle, error) {
+	if err := osutil.ExtractTarGz(r, dir); err != nil {
+		return nil, fmt.Errorf("failed to extract the bundle: %w", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, bundleInfoFileName))
+	if err != nil {
+		return nil, fmt.Errorf("not a crash bundle: %w", err)
+	}
+	bundle := new(CrashBundle)
+	if err := json.Unmarshal(data, bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", bundleInfoFileName, err)
+	}
+	return bundle, nil
+}
+
+// BundleRepro returns the syz reproducer file and its options from an extracted bundle.
+func BundleRepro(dir string) (string, csource.Options, error) {
+	return loadRepro(dir)
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/repro"
 	"github.com/google/syzkaller/prog"
 )
 
@@ -29,8 +30,11 @@ type CrashStore struct {
 }
 
 const reproFileName = "repro.prog"
+const reproOptsFileName = "repro.opts"
 const cReproFileName = "repro.cprog"
 const straceFileName = "strace.log"
+const ftraceFileName = "ftrace.log"
+const memoryDumpFileName = "memory.dump"
 
 const MaxReproAttempts = 3
 
@@ -94,10 +98,26 @@ func (cs *CrashStore) SaveCrash(crash *Crash) (bool, error) {
 	if err := report.AddTitleStat(filepath.Join(dir, "title-stat"), reps); err != nil {
 		return false, fmt.Errorf("report.AddTitleStat: %w", err)
 	}
+	// Memory dumps are huge, so we keep only the first one.
+	dumpFile := filepath.Join(dir, memoryDumpFileName)
+	if crash.MemoryDump != "" && !osutil.IsExist(dumpFile) {
+		if err := osutil.Rename(crash.MemoryDump, dumpFile); err != nil {
+			return false, fmt.Errorf("failed to save memory dump: %w", err)
+		}
+	}
 
 	return first, nil
 }
 
+// HasMemoryDump returns whether a memory dump is saved for the crash.
+func (cs *CrashStore) HasMemoryDump(title string) bool {
+	return osutil.IsExist(filepath.Join(cs.path(title), memoryDumpFileName))
+}
+
+func (cs *CrashStore) MemoryDumpFile(id string) string {
+	return filepath.Join(cs.BaseDir, "crashes", id, memoryDumpFileName)
+}
+
 func (cs *CrashStore) HasRepro(title string) bool {
 	return osutil.IsExist(filepath.Join(cs.path(title), reproFileName))
 }
@@ -140,6 +160,7 @@ func (cs *CrashStore) SaveRepro(res *ReproResult, progText, cProgText []byte) er
 	}
 	// TODO: detect and handle errors below as well.
 	osutil.WriteFile(filepath.Join(dir, reproFileName), progText)
+	osutil.WriteFile(filepath.Join(dir, reproOptsFileName), repro.Opts.Serialize())
 	if cs.Tag != "" {
 		osutil.WriteFile(filepath.Join(dir, "repro.tag"), []byte(cs.Tag))
 	}
@@ -172,12 +193,42 @@ func (cs *CrashStore) SaveRepro(res *ReproResult, progText, cProgText []byte) er
 			osutil.WriteFile(filepath.Join(dir, straceFileName), res.Strace.Output)
 		}
 	}
+	if res.Ftrace != nil {
+		if res.Ftrace.Error != nil {
+			osutil.WriteFile(filepath.Join(dir, "ftrace.error"),
+				[]byte(fmt.Sprintf("%v", res.Ftrace.Error)))
+		}
+		if len(res.Ftrace.Trace) > 0 {
+			osutil.WriteFile(filepath.Join(dir, ftraceFileName), res.Ftrace.Trace)
+		}
+	}
 	if reproLog := res.Stats.FullLog(); len(reproLog) > 0 {
 		osutil.WriteFile(filepath.Join(dir, "repro.stats"), reproLog)
 	}
 	return nil
 }
 
+// SaveReproForRecheck stores only the syz reproducer of the bug (without logs and reports),
+// so that it can be rechecked later when the full results were uploaded to the dashboard.
+func (cs *CrashStore) SaveReproForRecheck(res *repro.Result, progText []byte) error {
+	dir := cs.path(res.Report.Title)
+	osutil.MkdirAll(dir)
+	files := map[string][]byte{
+		"description":     []byte(res.Report.Title + "\n"),
+		reproFileName:     progText,
+		reproOptsFileName: res.Opts.Serialize(),
+	}
+	if cs.Tag != "" {
+		files["repro.tag"] = []byte(cs.Tag)
+	}
+	for name, data := range files {
+		if err := osutil.WriteFile(filepath.Join(dir, name), data); err != nil {
+			return fmt.Errorf("failed to save repro: %w", err)
+		}
+	}
+	return nil
+}
+
 type BugReport struct {
 	Title  string
 	Tag    string
@@ -222,7 +273,10 @@ type BugInfo struct {
 	HasRepro      bool
 	HasCRepro     bool
 	StraceFile    string // relative to the workdir
+	FtraceFile    string // relative to the workdir
+	MemoryDump    bool
 	ReproAttempts int
+	ReproStatus   ReproStatus
 	Crashes       []*CrashInfo
 	Rank          int
 }
@@ -271,7 +325,17 @@ func (cs *CrashStore) BugInfo(id string, full bool) (*BugInfo, error) {
 			ret.HasCRepro = true
 		} else if f == straceFileName {
 			ret.StraceFile = filepath.Join(dir, f)
-		} else if strings.HasPrefix(f, "repro") {
+		} else if f == ftraceFileName {
+			ret.FtraceFile = filepath.Join(dir, f)
+		} else if f == memoryDumpFileName {
+			ret.MemoryDump = true
+		} else if f == reproChecksFileName {
+			checks, err := readReproChecks(dir)
+			if err != nil {
+				return nil, err
+			}
+			ret.ReproStatus = ReproCheckStatus(checks)
+		} else if strings.HasPrefix(f, "repro") && f != reproOptsFileName && f != portabilityFileName {
 			ret.ReproAttempts++
 		}
 	}
@@ -295,7 +359,14 @@ func (cs *CrashStore) BugInfo(id string, full bool) (*BugInfo, error) {
 	return ret, nil
 }
 
+// BugList returns the bugs that have crash logs stored locally.
 func (cs *CrashStore) BugList() ([]*BugInfo, error) {
+	return cs.bugList(false)
+}
+
+// bugList optionally also returns bugs that only have a reproducer saved for rechecking
+// (see SaveReproForRecheck). These have no logs and are not shown in the crash list.
+func (cs *CrashStore) bugList(includeRecheck bool) ([]*BugInfo, error) {
 	dirs, err := osutil.ListDir(filepath.Join(cs.BaseDir, "crashes"))
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -314,6 +385,9 @@ func (cs *CrashStore) BugList() ([]*BugInfo, error) {
 			lastErr = err
 			continue
 		}
+		if len(info.Crashes) == 0 && !includeRecheck {
+			continue
+		}
 		ret = append(ret, info)
 	}
 	sort.Slice(ret, func(i, j int) bool {
@@ -4,11 +4,18 @@
 package manager
 
 import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/pkg/repro"
 	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -62,6 +69,34 @@ func TestEmptyCrashList(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestCrashMemoryDump(t *testing.T) {
+	crashStore := &CrashStore{
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 10,
+	}
+	assert.False(t, crashStore.HasMemoryDump("Title A"))
+	for i := 0; i < 2; i++ {
+		dump := filepath.Join(t.TempDir(), "dump")
+		assert.NoError(t, os.WriteFile(dump, []byte{byte(i)}, 0600))
+		_, err := crashStore.SaveCrash(&Crash{
+			Report: &report.Report{
+				Title:  "Title A",
+				Output: []byte("ABCD"),
+			},
+			MemoryDump: dump,
+		})
+		assert.NoError(t, err)
+	}
+	assert.True(t, crashStore.HasMemoryDump("Title A"))
+	info, err := crashStore.BugInfo(crashHash("Title A"), false)
+	assert.NoError(t, err)
+	assert.True(t, info.MemoryDump)
+	// Only the first dump is kept.
+	data, err := os.ReadFile(crashStore.MemoryDumpFile(crashHash("Title A")))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0}, data)
+}
+
 func TestMaxCrashLogs(t *testing.T) {
 	crashStore := &CrashStore{
 		BaseDir:      t.TempDir(),
@@ -113,3 +148,96 @@ func TestCrashRepro(t *testing.T) {
 	assert.Equal(t, []byte("c prog text"), report.CProg)
 	assert.Equal(t, []byte("Some report"), report.Report)
 }
+
+func TestCrashBundle(t *testing.T) {
+	crashStore := &CrashStore{
+		Tag:          "abcd",
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 5,
+	}
+	_, err := crashStore.SaveCrash(&Crash{Report: &report.Report{
+		Title:  "Some title",
+		Output: []byte("Some output"),
+		Report: []byte("Some report"),
+	}})
+	assert.NoError(t, err)
+	opts := csource.DefaultOpts(&mgrconfig.Config{})
+	opts.Procs = 3
+	err = crashStore.SaveRepro(&ReproResult{
+		Repro: &repro.Result{
+			Report: &report.Report{Title: "Some title"},
+			Prog:   &prog.Prog{},
+			Opts:   opts,
+		},
+	}, []byte("prog text"), nil)
+	assert.NoError(t, err)
+
+	cfg := &mgrconfig.Config{
+		Type: "qemu",
+		Derived: mgrconfig.Derived{
+			TargetOS:   targets.Linux,
+			TargetArch: targets.AMD64,
+			Timeouts:   targets.Timeouts{Slowdown: 1},
+		},
+	}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, crashStore.ExportBundle(crashHash("Some title"), cfg, buf))
+
+	dir := t.TempDir()
+	bundle, err := ExtractBundle(buf, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "Some title", bundle.Title)
+	assert.Equal(t, targets.Linux, bundle.OS)
+	assert.Contains(t, bundle.ExecprogCmd, "-procs=3")
+	assert.Contains(t, bundle.ExecprogCmd, "repro.prog")
+	for name, content := range map[string]string{
+		"log0": "Some output",
+		"tag0": "abcd",
+	} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+	progFile, reproOpts, err := BundleRepro(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "repro.prog"), progFile)
+	assert.Equal(t, opts, reproOpts)
+}
+
+func TestCrashBundleLegacyRepro(t *testing.T) {
+	crashStore := &CrashStore{
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 5,
+	}
+	opts := csource.DefaultOpts(&mgrconfig.Config{})
+	opts.Procs = 3
+	err := crashStore.SaveRepro(&ReproResult{
+		Repro: &repro.Result{
+			Report: &report.Report{Title: "Some title"},
+			Prog:   &prog.Prog{},
+			Opts:   opts,
+		},
+	}, []byte(fmt.Sprintf("# %+v\nprog text\n", opts)), nil)
+	assert.NoError(t, err)
+	// Crashes saved before repro.opts was introduced only have the options in the program header.
+	crashDir := filepath.Join(crashStore.BaseDir, "crashes", crashHash("Some title"))
+	assert.NoError(t, os.Remove(filepath.Join(crashDir, reproOptsFileName)))
+
+	cfg := &mgrconfig.Config{
+		Type: "qemu",
+		Derived: mgrconfig.Derived{
+			TargetOS:   targets.Linux,
+			TargetArch: targets.AMD64,
+			Timeouts:   targets.Timeouts{Slowdown: 1},
+		},
+	}
+	buf := new(bytes.Buffer)
+	assert.NoError(t, crashStore.ExportBundle(crashHash("Some title"), cfg, buf))
+	dir := t.TempDir()
+	bundle, err := ExtractBundle(buf, dir)
+	assert.NoError(t, err)
+	assert.Contains(t, bundle.ExecprogCmd, "-procs=3")
+	_, reproOpts, err := BundleRepro(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, opts, reproOpts)
+}
@@ -17,6 +17,7 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -56,9 +57,14 @@ type HTTPServer struct {
 	ReproLoop   *ReproLoop
 	Pool        *vm.Dispatcher
 	Pools       map[string]*vm.Dispatcher
+	Console     *vm.ConsoleRecorder // console output recordings of Pool VMs (optional)
 	TogglePause func(paused bool)
+	// Re-reads the config file and applies the fields that can change at runtime.
+	ReloadConfig func() (*mgrconfig.ReloadResult, error)
 
 	// Can be set dynamically after calling Serve.
+	// The config with the fields reloaded at runtime (Cfg itself is never modified).
+	ReloadedCfg     atomic.Pointer[mgrconfig.Config]
 	Corpus          atomic.Pointer[corpus.Corpus]
 	Fuzzer          atomic.Pointer[fuzzer.Fuzzer]
 	Cover           atomic.Pointer[CoverageInfo]
@@ -101,6 +107,7 @@ func (serv *HTTPServer) Serve(ctx context.Context) error {
 	handle("/prio", serv.httpPrio)
 	handle("/rawcover", serv.httpRawCover)
 	handle("/rawcoverfiles", serv.httpRawCoverFiles)
+	handle("/reload", serv.httpReload)
 	handle("/stats", serv.httpStats)
 	handle("/subsystemcover", serv.httpSubsystemCover)
 	handle("/syscalls", serv.httpSyscalls)
@@ -108,7 +115,9 @@ func (serv *HTTPServer) Serve(ctx context.Context) error {
 	handle("/vms", serv.httpVMs)
 	// keep-sorted end
 	if serv.CrashStore != nil {
+		handle("/bundle", serv.httpBundle)
 		handle("/crash", serv.httpCrash)
+		handle("/memorydump", serv.httpMemoryDump)
 		handle("/report", serv.httpReport)
 	}
 	// Browsers like to request this, without special handler this goes to / handler.
@@ -177,7 +186,28 @@ func (serv *HTTPServer) httpMain(w http.ResponseWriter, r *http.Request) {
 }
 
 func (serv *HTTPServer) httpConfig(w http.ResponseWriter, r *http.Request) {
-	serv.jsonPage(w, r, "config", serv.Cfg)
+	cfg := serv.Cfg
+	if reloaded := serv.ReloadedCfg.Load(); reloaded != nil {
+		cfg = reloaded
+	}
+	serv.jsonPage(w, r, "config", cfg)
+}
+
+func (serv *HTTPServer) httpReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "only POST method supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if serv.ReloadConfig == nil {
+		http.Error(w, "config reload is not implemented", http.StatusNotImplemented)
+		return
+	}
+	res, err := serv.ReloadConfig()
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to reload config: %v", err), http.StatusBadRequest)
+		return
+	}
+	serv.jsonPage(w, r, "config reload", res)
 }
 
 func (serv *HTTPServer) jsonPage(w http.ResponseWriter, r *http.Request, title string, data any) {
@@ -301,16 +331,38 @@ func (serv *HTTPServer) httpVMs(w http.ResponseWriter, r *http.Request) {
 			info.State = "waiting"
 		case dispatcher.StateRunning:
 			info.State = "running: " + state.Status
+		case dispatcher.StateQuarantined:
+			info.State = fmt.Sprintf("quarantined for %v",
+				time.Until(state.Health.QuarantinedUntil).Truncate(time.Second))
 		}
 		if state.Reserved {
 			info.State = "[reserved] " + info.State
 		}
+		if state.Parked {
+			info.State = "[parked] " + info.State
+		}
+		if health := state.Health; health.BootFailures+health.InfraErrors+health.LostConnections != 0 {
+			info.Health = fmt.Sprintf("boot: %v, infra: %v, lost: %v, in a row: %v",
+				health.BootFailures, health.InfraErrors, health.LostConnections, health.Consecutive)
+		}
 		if state.MachineInfo != nil {
 			info.MachineInfo = fmt.Sprintf("/vm?type=machine-info&id=%d", id)
 		}
 		if state.DetailedStatus != nil {
 			info.DetailedStatus = fmt.Sprintf("/vm?type=detailed-status&id=%v", id)
 		}
+		if serv.Console != nil && r.FormValue("pool") == "" {
+			records, err := serv.Console.List(id)
+			if err != nil {
+				log.Logf(0, "failed to list console records: %v", err)
+			}
+			for _, rec := range records {
+				info.Console = append(info.Console, UIConsoleRecord{
+					Seq:  rec.Seq,
+					Link: fmt.Sprintf("/vm?type=console&id=%v&run=%v", id, rec.Seq),
+				})
+			}
+		}
 		data.VMs = append(data.VMs, info)
 	}
 	executeTemplate(w, vmsTemplate, data)
@@ -340,11 +392,46 @@ func (serv *HTTPServer) httpVM(w http.ResponseWriter, r *http.Request) {
 		if info.DetailedStatus != nil {
 			w.Write(info.DetailedStatus())
 		}
+	case "console":
+		serv.httpVMConsole(w, r, id)
 	default:
 		w.Write([]byte("unknown info type"))
 	}
 }
 
+func (serv *HTTPServer) httpVMConsole(w http.ResponseWriter, r *http.Request, id int) {
+	if serv.Console == nil || r.FormValue("pool") != "" {
+		http.Error(w, "console recording is not enabled", http.StatusBadRequest)
+		return
+	}
+	records, err := serv.Console.List(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, rec := range records {
+		if r.FormValue("run") != fmt.Sprint(rec.Seq) {
+			continue
+		}
+		f, err := os.Open(rec.File)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer f.Close()
+		_, chunks, err := vm.ReadConsoleRecord(f)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintf(w, "VM #%v run %v started at %v\n%v\n\n",
+			id, rec.Seq, rec.Start.Format(time.DateTime), rec.Command)
+		vm.FormatConsoleRecord(w, chunks)
+		return
+	}
+	http.Error(w, "no such console record", http.StatusNotFound)
+}
+
 func makeUICrashType(info *BugInfo, startTime time.Time, repros map[string]bool) UICrashType {
 	var crashes []UICrash
 	for _, crash := range info.Crashes {
@@ -398,13 +485,58 @@ func (serv *HTTPServer) httpCrash(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "failed to read crash info", http.StatusInternalServerError)
 		return
 	}
+	checks, err := serv.CrashStore.ReproChecks(crashID)
+	if err != nil {
+		http.Error(w, "failed to read repro checks", http.StatusInternalServerError)
+		return
+	}
+	slices.Reverse(checks)
+	portability, err := serv.CrashStore.Portability(crashID)
+	if err != nil {
+		http.Error(w, "failed to read repro portability", http.StatusInternalServerError)
+		return
+	}
 	data := UICrashPage{
 		UIPageHeader: serv.pageHeader(r, info.Title),
 		UICrashType:  makeUICrashType(info, serv.StartTime, nil),
+		ReproChecks:  checks,
+		Portability:  portability,
 	}
 	executeTemplate(w, crashTemplate, data)
 }
 
+func (serv *HTTPServer) httpBundle(w http.ResponseWriter, r *http.Request) {
+	crashID := r.FormValue("id")
+	if !crashIDRe.MatchString(crashID) {
+		http.Error(w, "invalid crash ID", http.StatusBadRequest)
+		return
+	}
+	buf := new(bytes.Buffer)
+	if err := serv.CrashStore.ExportBundle(crashID, serv.Cfg, buf); err != nil {
+		http.Error(w, fmt.Sprintf("failed to export the crash: %v", err), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/gzip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=crash-%v.tar.gz", crashID))
+	w.Write(buf.Bytes())
+}
+
+func (serv *HTTPServer) httpMemoryDump(w http.ResponseWriter, r *http.Request) {
+	crashID := r.FormValue("id")
+	if !crashIDRe.MatchString(crashID) {
+		http.Error(w, "invalid crash ID", http.StatusBadRequest)
+		return
+	}
+	file := serv.CrashStore.MemoryDumpFile(crashID)
+	if _, err := os.Stat(file); err != nil {
+		http.Error(w, "no memory dump for the crash", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=memory-%v.dump", crashID))
+	http.ServeFile(w, r, file)
+}
+
 func (serv *HTTPServer) httpCorpus(w http.ResponseWriter, r *http.Request) {
 	corpus := serv.Corpus.Load()
 	if corpus == nil {
@@ -1068,8 +1200,15 @@ type UIVMInfo struct {
 	Name           string
 	State          string
 	Since          time.Duration
+	Health         string
 	MachineInfo    string
 	DetailedStatus string
+	Console        []UIConsoleRecord
+}
+
+type UIConsoleRecord struct {
+	Seq  int
+	Link string
 }
 
 type UISyscallsData struct {
@@ -1080,6 +1219,8 @@ type UISyscallsData struct {
 type UICrashPage struct {
 	UIPageHeader
 	UICrashType
+	ReproChecks []ReproCheck
+	Portability []PortabilityCheck
 }
 
 type UICrashType struct {
@@ -0,0 +1,75 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// PortabilityCheck is the result of running the reproducer on a configuration
+// other than the one it was found on (another arch, a compat 32-bit target,
+// VM type, sandbox or sanitizer build).
+type PortabilityCheck struct {
+	Time time.Time
+	// The name of the manager config the reproducer was run with.
+	Config string
+	// The target in the OS/VMArch/Arch form, e.g. linux/amd64/386.
+	Target  string
+	VMType  string
+	Sandbox string
+	// The number of reproducer runs, we stop after the first crash.
+	Runs       int
+	Reproduced bool
+	// The title of the crash that was actually observed, if any.
+	Title string `json:",omitempty"`
+	// Set if the reproducer could not be run with the config at all,
+	// e.g. it uses syscalls that do not exist on the target.
+	Error string `json:",omitempty"`
+}
+
+const portabilityFileName = "repro.portability"
+
+// SavePortability replaces the portability check results stored in the crash dir.
+func SavePortability(dir string, checks []PortabilityCheck) error {
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	for _, check := range checks {
+		if err := enc.Encode(check); err != nil {
+			return err
+		}
+	}
+	return osutil.WriteFile(filepath.Join(dir, portabilityFileName), buf.Bytes())
+}
+
+// Portability returns the results of the last portability check of the bug.
+func (cs *CrashStore) Portability(id string) ([]PortabilityCheck, error) {
+	return readPortability(filepath.Join(cs.BaseDir, "crashes", id))
+}
+
+func readPortability(dir string) ([]PortabilityCheck, error) {
+	data, err := os.ReadFile(filepath.Join(dir, portabilityFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var checks []PortabilityCheck
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var check PortabilityCheck
+		if err := dec.Decode(&check); err != nil {
+			return nil, fmt.Errorf("failed to parse %v: %w", portabilityFileName, err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
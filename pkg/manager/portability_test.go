@@ -0,0 +1,53 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPortability(t *testing.T) {
+	const title = "Some title"
+	crashStore := testCrashStore(t, "", title)
+	id := crashHash(title)
+
+	checks, err := crashStore.Portability(id)
+	assert.NoError(t, err)
+	assert.Empty(t, checks)
+
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	want := []PortabilityCheck{
+		{
+			Time:       now,
+			Config:     "arm64",
+			Target:     "linux/arm64",
+			VMType:     "qemu",
+			Sandbox:    "none",
+			Runs:       1,
+			Reproduced: true,
+			Title:      title,
+		},
+		{
+			Time:    now,
+			Config:  "386",
+			Target:  "linux/amd64/386",
+			VMType:  "qemu",
+			Sandbox: "none",
+			Error:   "unknown syscall",
+		},
+	}
+	assert.NoError(t, SavePortability(filepath.Join(crashStore.BaseDir, "crashes", id), want))
+	checks, err = crashStore.Portability(id)
+	assert.NoError(t, err)
+	assert.Equal(t, want, checks)
+
+	// The results are not a failed reproduction attempt.
+	info, err := crashStore.BugInfo(id, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, info.ReproAttempts)
+}
@@ -20,6 +20,7 @@ type ReproResult struct {
 	Crash  *Crash // the original crash
 	Repro  *repro.Result
 	Strace *repro.StraceResult
+	Ftrace *repro.FtraceResult
 	Stats  *repro.Stats
 	Err    error
 }
@@ -30,14 +31,18 @@ type Crash struct {
 	FromDashboard bool // .. or from dashboard
 	Manual        bool
 	FullRepro     bool // used by the diff fuzzer to do a full scale reproduction
+	Recheck       bool // re-run the already found reproducer to track its reliability
 	*report.Report
 	TailReports []*report.Report
+	MemoryDump  string // guest memory dump file taken after the crash (optional)
 }
 
 func (c *Crash) FullTitle() string {
 	suffix := ""
 	if c.FullRepro {
 		suffix = " (full)"
+	} else if c.Recheck {
+		suffix = " (recheck)"
 	}
 	if c.Report.Title != "" {
 		return c.Report.Title + suffix
@@ -149,6 +154,10 @@ func (r *ReproLoop) popCrash() *Crash {
 		if base.FullRepro != new.FullRepro {
 			return new.FullRepro
 		}
+		// Rechecks of existing reproducers only use otherwise idle VMs.
+		if base.Recheck != new.Recheck {
+			return !new.Recheck
+		}
 		// The more times we failed, the less likely we are to actually
 		// find a reproducer. Give preference to not yet attempted repro runs.
 		baseTitle, newTitle := base.FullTitle(), new.FullTitle()
@@ -266,6 +275,11 @@ func (r *ReproLoop) calculateReproVMs(repros int) int {
 }
 
 func (r *ReproLoop) handle(ctx context.Context, crash *Crash) {
+	if crash.Recheck {
+		// The result of the check is recorded by the manager itself.
+		r.mgr.RunRepro(ctx, crash)
+		return
+	}
 	log.Logf(0, "start reproducing '%v'", crash.FullTitle())
 
 	res := r.mgr.RunRepro(ctx, crash)
@@ -0,0 +1,189 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// ReproCheck is the result of a single re-run of an already found reproducer.
+type ReproCheck struct {
+	Time       time.Time
+	Tag        string
+	Reproduced bool
+	// The title of the crash that was actually observed, if any.
+	Title string `json:",omitempty"`
+}
+
+type ReproStatus string
+
+const (
+	ReproUnchecked ReproStatus = ""
+	ReproReliable  ReproStatus = "reliable"
+	ReproFlaky     ReproStatus = "flaky"
+	ReproBroken    ReproStatus = "broken"
+)
+
+const (
+	reproChecksFileName = "repro.checks"
+	// How many latest checks we keep and consider for the status.
+	maxReproChecks = 10
+	// If that many latest checks failed, the reproducer is considered broken.
+	brokenReproChecks = 3
+)
+
+// ReproCheckStatus summarizes the check history (oldest first).
+func ReproCheckStatus(checks []ReproCheck) ReproStatus {
+	if len(checks) == 0 {
+		return ReproUnchecked
+	}
+	failed, lastFailed := 0, 0
+	for _, check := range checks {
+		if check.Reproduced {
+			lastFailed = 0
+			continue
+		}
+		failed++
+		lastFailed++
+	}
+	switch {
+	case lastFailed >= brokenReproChecks:
+		return ReproBroken
+	case failed != 0:
+		return ReproFlaky
+	}
+	return ReproReliable
+}
+
+// SaveReproCheck appends the check result to the history of the bug
+// and returns the updated status of the reproducer.
+func (cs *CrashStore) SaveReproCheck(title string, check ReproCheck) (ReproStatus, error) {
+	dir := cs.path(title)
+	checks, err := readReproChecks(dir)
+	if err != nil {
+		return ReproUnchecked, err
+	}
+	checks = append(checks, check)
+	if len(checks) > maxReproChecks {
+		checks = checks[len(checks)-maxReproChecks:]
+	}
+	buf := new(bytes.Buffer)
+	enc := json.NewEncoder(buf)
+	for _, check := range checks {
+		if err := enc.Encode(check); err != nil {
+			return ReproUnchecked, err
+		}
+	}
+	if err := osutil.WriteFile(filepath.Join(dir, reproChecksFileName), buf.Bytes()); err != nil {
+		return ReproUnchecked, err
+	}
+	return ReproCheckStatus(checks), nil
+}
+
+// ReproChecks returns the check history of the bug (oldest first).
+func (cs *CrashStore) ReproChecks(id string) ([]ReproCheck, error) {
+	return readReproChecks(filepath.Join(cs.BaseDir, "crashes", id))
+}
+
+// ReproToRecheck returns the bug whose reproducer should be re-run next.
+// Reproducers that have never been checked on the current tag go first,
+// then the ones that were checked more than period ago.
+func (cs *CrashStore) ReproToRecheck(tag string, period time.Duration) (string, bool) {
+	bugs, err := cs.bugList(true)
+	if err != nil {
+		return "", false
+	}
+	var best *BugInfo
+	var bestTime time.Time
+	for _, bug := range bugs {
+		if !bug.HasRepro {
+			continue
+		}
+		checks, err := cs.ReproChecks(bug.ID)
+		if err != nil {
+			continue
+		}
+		var last time.Time
+		if len(checks) != 0 && checks[len(checks)-1].Tag == tag {
+			last = checks[len(checks)-1].Time
+		}
+		if time.Since(last) < period {
+			continue
+		}
+		if best == nil || last.Before(bestTime) {
+			best, bestTime = bug, last
+		}
+	}
+	if best == nil {
+		return "", false
+	}
+	return best.Title, true
+}
+
+// ReproProg returns the stored syz reproducer of the bug and its options.
+func (cs *CrashStore) ReproProg(title string) ([]byte, csource.Options, error) {
+	progFile, opts, err := loadRepro(cs.path(title))
+	if err != nil {
+		return nil, opts, err
+	}
+	data, err := os.ReadFile(progFile)
+	return data, opts, err
+}
+
+func loadRepro(dir string) (string, csource.Options, error) {
+	progFile := filepath.Join(dir, reproFileName)
+	if !osutil.IsExist(progFile) {
+		return "", csource.Options{}, fmt.Errorf("there is no syz reproducer")
+	}
+	optsData, err := os.ReadFile(filepath.Join(dir, reproOptsFileName))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return "", csource.Options{}, err
+		}
+		// Reproducers saved before repro.opts was introduced only have the options
+		// in the header of the program.
+		progData, err := os.ReadFile(progFile)
+		if err != nil {
+			return "", csource.Options{}, err
+		}
+		opts, err := csource.ParseOptions(progData)
+		if err != nil {
+			return "", csource.Options{}, fmt.Errorf("failed to parse repro options: %w", err)
+		}
+		return progFile, opts, nil
+	}
+	opts, err := csource.DeserializeOptions(optsData)
+	if err != nil {
+		return "", csource.Options{}, fmt.Errorf("failed to parse repro options: %w", err)
+	}
+	return progFile, opts, nil
+}
+
+func readReproChecks(dir string) ([]ReproCheck, error) {
+	data, err := os.ReadFile(filepath.Join(dir, reproChecksFileName))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var checks []ReproCheck
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for dec.More() {
+		var check ReproCheck
+		if err := dec.Decode(&check); err != nil {
+			return nil, fmt.Errorf("failed to parse %v: %w", reproChecksFileName, err)
+		}
+		checks = append(checks, check)
+	}
+	return checks, nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/repro"
+	"github.com/google/syzkaller/prog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReproCheckStatus(t *testing.T) {
+	ok := ReproCheck{Reproduced: true}
+	fail := ReproCheck{}
+	tests := []struct {
+		checks []ReproCheck
+		status ReproStatus
+	}{
+		{nil, ReproUnchecked},
+		{[]ReproCheck{ok, ok}, ReproReliable},
+		{[]ReproCheck{ok, fail, ok}, ReproFlaky},
+		{[]ReproCheck{fail, fail}, ReproFlaky},
+		{[]ReproCheck{ok, fail, fail, fail}, ReproBroken},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.status, ReproCheckStatus(test.checks), "test #%v", i)
+	}
+}
+
+// testCrashStore returns a crash store with a single saved crash.
+func testCrashStore(t *testing.T, tag, title string) *CrashStore {
+	crashStore := &CrashStore{
+		Tag:          tag,
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 5,
+	}
+	_, err := crashStore.SaveCrash(&Crash{Report: &report.Report{
+		Title:  title,
+		Output: []byte("Some output"),
+	}})
+	require.NoError(t, err)
+	return crashStore
+}
+
+func TestReproRecheck(t *testing.T) {
+	const title = "Some title"
+	crashStore := testCrashStore(t, "abcd", title)
+
+	// Nothing to recheck until there's a reproducer.
+	_, ok := crashStore.ReproToRecheck("abcd", time.Hour)
+	assert.False(t, ok)
+
+	opts := csource.DefaultOpts(&mgrconfig.Config{})
+	err := crashStore.SaveRepro(&ReproResult{
+		Repro: &repro.Result{
+			Report: &report.Report{Title: title},
+			Prog:   &prog.Prog{},
+			Opts:   opts,
+		},
+	}, []byte("prog text"), nil)
+	assert.NoError(t, err)
+
+	got, ok := crashStore.ReproToRecheck("abcd", time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, title, got)
+
+	progData, gotOpts, err := crashStore.ReproProg(title)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("prog text"), progData)
+	assert.Equal(t, opts, gotOpts)
+
+	status, err := crashStore.SaveReproCheck(title, ReproCheck{Time: time.Now(), Tag: "abcd", Reproduced: true})
+	assert.NoError(t, err)
+	assert.Equal(t, ReproReliable, status)
+
+	// Checked recently on this tag.
+	_, ok = crashStore.ReproToRecheck("abcd", time.Hour)
+	assert.False(t, ok)
+	// But the kernel has changed since then.
+	_, ok = crashStore.ReproToRecheck("efgh", time.Hour)
+	assert.True(t, ok)
+
+	for i := 0; i < maxReproChecks+1; i++ {
+		status, err = crashStore.SaveReproCheck(title, ReproCheck{Time: time.Now(), Tag: "efgh", Title: "Other"})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, ReproBroken, status)
+	checks, err := crashStore.ReproChecks(crashHash(title))
+	assert.NoError(t, err)
+	assert.Len(t, checks, maxReproChecks)
+
+	info, err := crashStore.BugInfo(crashHash(title), false)
+	assert.NoError(t, err)
+	assert.Equal(t, ReproBroken, info.ReproStatus)
+	assert.Equal(t, 1, info.ReproAttempts)
+}
+
+func TestReproRecheckDashboard(t *testing.T) {
+	crashStore := &CrashStore{
+		Tag:          "abcd",
+		BaseDir:      t.TempDir(),
+		MaxCrashLogs: 5,
+	}
+	// With a dashboard, only the reproducer itself is stored locally.
+	const title = "Some title"
+	opts := csource.DefaultOpts(&mgrconfig.Config{})
+	err := crashStore.SaveReproForRecheck(&repro.Result{
+		Report: &report.Report{Title: title},
+		Opts:   opts,
+	}, []byte("prog text"))
+	assert.NoError(t, err)
+
+	got, ok := crashStore.ReproToRecheck("abcd", time.Hour)
+	assert.True(t, ok)
+	assert.Equal(t, title, got)
+	progData, gotOpts, err := crashStore.ReproProg(title)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte("prog text"), progData)
+	assert.Equal(t, opts, gotOpts)
+	// The bug has no logs, so it must not appear in the crash list.
+	list, err := crashStore.BugList()
+	assert.NoError(t, err)
+	assert.Empty(t, list)
+}
@@ -61,7 +61,7 @@ func TestReproOrder(t *testing.T) {
 	}
 	obj := NewReproLoop(mock, 1, false)
 
-	// The right order is A B C.
+	// The right order is A B C, rechecks (D) go last.
 	crashes := []*Crash{
 		{
 			Report:        &report.Report{Title: "A"},
@@ -76,11 +76,17 @@ func TestReproOrder(t *testing.T) {
 			Report:  &report.Report{Title: "C"},
 			FromHub: true,
 		},
+		{
+			Report:  &report.Report{Title: "D"},
+			Recheck: true,
+		},
 	}
 
+	obj.Enqueue(crashes[3])
 	obj.Enqueue(crashes[2])
 	obj.Enqueue(crashes[1])
 	obj.Enqueue(crashes[0])
+	obj.Enqueue(crashes[3])
 	obj.Enqueue(crashes[1])
 	obj.Enqueue(crashes[0])
 	obj.Enqueue(crashes[2])
@@ -89,9 +95,9 @@ func TestReproOrder(t *testing.T) {
 	defer cancel()
 	go obj.Loop(ctx)
 
-	for i := 0; i < len(crashes)*2; i++ {
+	for _, i := range []int{0, 1, 2, 0, 1, 2, 3, 3} {
 		called := <-mock.run
-		assert.Equal(t, crashes[i%len(crashes)], called.crash)
+		assert.Equal(t, crashes[i], called.crash)
 		called.ret <- &ReproResult{}
 	}
 }
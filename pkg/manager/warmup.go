@@ -0,0 +1,132 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/prog"
+)
+
+// SnapshotWarmup is a program that is executed once before the VM is snapshotted in snapshot mode.
+// All test programs executed in the VM start with its calls and can use the resources it has created.
+type SnapshotWarmup struct {
+	Area string
+	// Index of the focus area in the manager config.
+	Index int
+	Prog  *prog.Prog
+}
+
+// Test programs start with the warm-up calls and must not exceed prog.MaxCalls,
+// so leave most of the calls to the test programs.
+const maxWarmupCalls = prog.MaxCalls / 4
+
+// SnapshotWarmups holds warm-up programs of the focus areas.
+type SnapshotWarmups struct {
+	areas   []mgrconfig.FocusArea
+	warmups []*SnapshotWarmup
+}
+
+// LoadSnapshotWarmups loads warm-up programs of all focus areas.
+// Returns nil if no focus area has a warm-up program.
+func LoadSnapshotWarmups(cfg *mgrconfig.Config) (*SnapshotWarmups, error) {
+	ret := &SnapshotWarmups{
+		areas: cfg.Experimental.FocusAreas,
+	}
+	found := false
+	for i, area := range ret.areas {
+		if area.SnapshotWarmup == "" {
+			ret.warmups = append(ret.warmups, nil)
+			continue
+		}
+		data, err := os.ReadFile(area.SnapshotWarmup)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read snapshot warm-up program: %w", err)
+		}
+		p, err := cfg.Target.Deserialize(data, prog.NonStrict)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse snapshot warm-up program %v: %w",
+				area.SnapshotWarmup, err)
+		}
+		if len(p.Calls) == 0 || len(p.Calls) > maxWarmupCalls {
+			return nil, fmt.Errorf("snapshot warm-up program %v has %v calls, want [1, %v]",
+				area.SnapshotWarmup, len(p.Calls), maxWarmupCalls)
+		}
+		name := area.Name
+		if name == "" {
+			name = fmt.Sprintf("#%v", i)
+		}
+		ret.warmups = append(ret.warmups, &SnapshotWarmup{
+			Area:  name,
+			Index: i,
+			Prog:  p,
+		})
+		found = true
+	}
+	if !found {
+		return nil, nil
+	}
+	return ret, nil
+}
+
+// WithWarmup returns the program to execute in place of p in the VM with the warm-up.
+// Programs fuzzed for the focus area already start with the warm-up calls and refer to
+// their resources (see fuzzer.FocusAreaSource), they are returned as is.
+// Other programs (e.g. candidates) get the warm-up calls prepended, but their resources are
+// not bound to the resources created by the warm-up, so that they are executed as they are
+// (it's what the fuzzer gets the feedback for).
+func (sw *SnapshotWarmup) WithWarmup(p *prog.Prog) (*prog.Prog, error) {
+	if p.HasPrefix(sw.Prog) {
+		return p, nil
+	}
+	if len(sw.Prog.Calls)+len(p.Calls) > prog.MaxCalls {
+		return nil, fmt.Errorf("the program has too many calls to prepend the snapshot warm-up (%v+%v > %v)",
+			len(sw.Prog.Calls), len(p.Calls), prog.MaxCalls)
+	}
+	return prog.Concat(sw.Prog, p), nil
+}
+
+// Serialize serializes p returned by WithWarmup (or the warm-up program itself) for execution.
+// Results of the warm-up calls are always copied out, so that the programs can refer to
+// the resources created by the warm-up executed before the snapshot.
+func (sw *SnapshotWarmup) Serialize(p *prog.Prog) ([]byte, error) {
+	return p.SerializeForExecKeepResults(len(sw.Prog.Calls))
+}
+
+// List returns all warm-up programs.
+func (sw *SnapshotWarmups) List() []*SnapshotWarmup {
+	if sw == nil {
+		return nil
+	}
+	var ret []*SnapshotWarmup
+	for _, warmup := range sw.warmups {
+		if warmup != nil {
+			ret = append(ret, warmup)
+		}
+	}
+	return ret
+}
+
+// Pick returns the warm-up program for the VM with the given index.
+// VMs are distributed among focus areas proportionally to the area weights.
+// The result is nil if the VM falls into an area without a warm-up program.
+func (sw *SnapshotWarmups) Pick(index, count int) *SnapshotWarmup {
+	if sw == nil {
+		return nil
+	}
+	total := 0.0
+	for _, area := range sw.areas {
+		total += area.Weight
+	}
+	pos := (float64(index%count) + 0.5) / float64(count) * total
+	for i, area := range sw.areas {
+		if pos < area.Weight {
+			return sw.warmups[i]
+		}
+		pos -= area.Weight
+	}
+	return sw.warmups[len(sw.warmups)-1]
+}
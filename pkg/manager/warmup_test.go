@@ -0,0 +1,70 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package manager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotWarmups(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "warmup")
+	require.NoError(t, osutil.WriteFile(file, []byte("test$res0()\n")))
+	cfg := &mgrconfig.Config{}
+	cfg.Target = target
+	cfg.Experimental.FocusAreas = []mgrconfig.FocusArea{
+		{Name: "res", Weight: 3, SnapshotWarmup: file},
+		{Weight: 1},
+	}
+
+	warmups, err := LoadSnapshotWarmups(cfg)
+	require.NoError(t, err)
+	var picked []string
+	for i := 0; i < 8; i++ {
+		area := ""
+		if warmup := warmups.Pick(i, 8); warmup != nil {
+			area = warmup.Area
+			assert.Len(t, warmup.Prog.Calls, 1)
+		}
+		picked = append(picked, area)
+	}
+	assert.Equal(t, []string{"res", "res", "res", "res", "res", "res", "", ""}, picked)
+	require.Len(t, warmups.List(), 1)
+	warmup := warmups.List()[0]
+	assert.Equal(t, 0, warmup.Index)
+
+	// The test program is executed as is after the warm-up calls.
+	p, err := target.Deserialize([]byte("test$res1(0xffff)\n"), prog.Strict)
+	require.NoError(t, err)
+	full, err := warmup.WithWarmup(p)
+	require.NoError(t, err)
+	assert.Equal(t, "test$res0()\ntest$res1(0xffff)\n", string(full.Serialize()))
+	p, err = target.Deserialize([]byte(strings.Repeat("test$res1(0xffff)\n", prog.MaxCalls)), prog.Strict)
+	require.NoError(t, err)
+	_, err = warmup.WithWarmup(p)
+	assert.Error(t, err)
+
+	// Programs fuzzed for the area already start with the warm-up calls and use their resources.
+	p, err = target.Deserialize([]byte("r0 = test$res0()\ntest$res1(r0)\n"), prog.Strict)
+	require.NoError(t, err)
+	full, err = warmup.WithWarmup(p)
+	require.NoError(t, err)
+	assert.Equal(t, p, full)
+
+	cfg.Experimental.FocusAreas[0].SnapshotWarmup = ""
+	warmups, err = LoadSnapshotWarmups(cfg)
+	require.NoError(t, err)
+	assert.Nil(t, warmups)
+	assert.Nil(t, warmups.Pick(0, 1))
+}
@@ -118,6 +118,24 @@ type Config struct {
 	// Maximum number of logs to store per crash (default: 100).
 	MaxCrashLogs int `json:"max_crash_logs"`
 
+	// Number of the last runs per VM to keep the full timestamped console output of (default: 0).
+	// The output is saved compressed in workdir/console and is available on the VMs page.
+	// Can be replayed with tools/syz-console.
+	ConsoleRecords int `json:"console_records,omitempty"`
+
+	// Commands to collect additional kernel state over ssh when crashes are detected
+	// (e.g. /proc/slabinfo, lockdep state, ftrace buffer). The output is attached to the crash log.
+	// If not specified, the built-in set for the target OS is used (see vm/vmimpl/linux.go),
+	// the configured list replaces it, an empty list disables the diagnostics.
+	// Each command takes time since the VM may be hung, so enable only the useful ones. E.g.:
+	//	"diagnostics": [
+	//		{"name": "blocked tasks", "command": "echo w > /proc/sysrq-trigger; echo d > /proc/sysrq-trigger",
+	//			"types": ["HANG"], "console": true},
+	//		{"name": "ftrace", "command": "tail -n 1000 /sys/kernel/debug/tracing/trace", "types": ["HANG"]},
+	//		{"name": "slabinfo", "command": "cat /proc/slabinfo", "types": ["LEAK", "DoS"], "strip_pointers": true}
+	//	]
+	Diagnostics []Diagnostic `json:"diagnostics,omitempty"`
+
 	// Type of sandbox to use during fuzzing:
 	// "none": test under root;
 	//      don't do anything special beyond resource sandboxing,
@@ -139,7 +157,7 @@ type Config struct {
 
 	// Enables snapshotting mode. In this mode VM is snapshotted and restarted from the snapshot
 	// before executing each test program. This provides better reproducibility and avoids global
-	// accumulated state. Currently only qemu VMs and Linux support this mode.
+	// accumulated state. Currently only qemu and firecracker VMs and Linux support this mode.
 	Snapshot bool `json:"snapshot"`
 
 	// Use KCOV coverage (default: true).
@@ -156,6 +174,12 @@ type Config struct {
 
 	// Reproduce, localize and minimize crashers (default: true).
 	Reproduce bool `json:"reproduce"`
+	// How strictly reproducers are checked to trigger the original bug (optional):
+	// "title" (default) only compares crash titles, "type" also compares crash types,
+	// "access" also compares the kind, direction and object cache of bad memory accesses
+	// reported by sanitizers, "stacks" also requires the allocation/free stacks to overlap.
+	// The stricter levels prevent accepting reproducers of a different bug with a similar title.
+	ReproVerify string `json:"repro_verify,omitempty"`
 
 	// The number of VMs that are reserved to only perform fuzzing and nothing else.
 	// Can be helpful e.g. to ensure that the pool of fuzzing VMs is never exhausted and
@@ -163,6 +187,24 @@ type Config struct {
 	// By default the value is 0, i.e. all VMs can be used for all purposes.
 	FuzzingVMs int `json:"fuzzing_vms,omitempty"`
 
+	// The number of consecutive boot failures or infrastructure errors after which a VM
+	// is temporarily taken out of rotation (optional). The quarantine period doubles
+	// with every next failure. Kernel crashes (including lost connections) are not counted.
+	// By default the value is 0, i.e. quarantine is disabled.
+	VMQuarantine int `json:"vm_quarantine,omitempty"`
+
+	// Dynamically adjust the number of running VMs to the load of the host machine (optional).
+	// VMs are stopped when the host is under CPU/memory pressure (e.g. someone compiles
+	// a kernel on the same machine) or when VMs fail to boot, and are gradually restarted
+	// when the host becomes idle. VMs used for bug reproduction are never stopped.
+	// The host load is only available on linux hosts, elsewhere only boot failures are considered.
+	// A sample config:
+	// "autoscale": {
+	//    "min_vms": 2,
+	//    "max_cpu_pressure": 20
+	// }
+	Autoscale *AutoscaleConfig `json:"autoscale,omitempty"`
+
 	// Keep existing programs in the corpus even if they no longer pass syscall filters.
 	// By default it is true, as this is the desired behavior when executing syzkaller
 	// locally.
@@ -195,6 +237,25 @@ type Config struct {
 	// image instead of copying it from the host (default: false).
 	StraceBinOnTarget bool `json:"strace_bin_on_target"`
 
+	// Kernel tracing (ftrace) configuration (Linux only, optional).
+	// If set, for each reproducer syzkaller will run it once more with the configured
+	// trace events/functions enabled in the guest and save the trace buffer next to the report.
+	// For example:
+	//	"ftrace": {
+	//		"events": ["sched:sched_switch", "kmem:kmalloc"],
+	//		"functions": ["ext4_*"]
+	//	}
+	Ftrace *Ftrace `json:"ftrace,omitempty"`
+
+	// Symbolizer used for crash reports and coverage (optional).
+	// Symbolizing large kernels is slow, llvm-symbolizer is faster than addr2line
+	// and the cache allows to reuse results across restarts and tools. For example:
+	//	"symbolizer": {
+	//		"backend": "llvm-symbolizer",
+	//		"cache_dir": "/var/cache/syzkaller-symbolizer"
+	//	}
+	Symbolizer *Symbolizer `json:"symbolizer,omitempty"`
+
 	// File in PATH to syz-execprog/executor on the target. If set,
 	// syzkaller will expect the execprog/executor binaries to be part of
 	// the target image instead of copying them from the host.
@@ -260,6 +321,23 @@ type Experimental struct {
 	EnableKFuzzTest bool `json:"enable_kfuzztest"`
 }
 
+type AutoscaleConfig struct {
+	// Bounds for the number of running VMs (default: 1 and the number of VMs in the pool).
+	MinVMs int `json:"min_vms,omitempty"`
+	MaxVMs int `json:"max_vms,omitempty"`
+	// Stop VMs when CPU or memory pressure exceeds these values (default: 20 and 10).
+	// Pressure is the share of time (in percent) during which some tasks were stalled,
+	// see Documentation/accounting/psi.rst in the Linux kernel.
+	MaxCPUPressure float64 `json:"max_cpu_pressure,omitempty"`
+	MaxMemPressure float64 `json:"max_mem_pressure,omitempty"`
+	// Start more VMs only when CPU pressure is below this value (default: 5).
+	MinCPUPressure float64 `json:"min_cpu_pressure,omitempty"`
+	// Stop VMs when more than this share of VM boots fail (default: 0.5).
+	MaxBootFailureRate float64 `json:"max_boot_failure_rate,omitempty"`
+	// How often to re-evaluate the load, in seconds (default: 30).
+	Interval int `json:"interval,omitempty"`
+}
+
 type FocusArea struct {
 	// Name allows to display detailed statistics for every focus area.
 	Name string `json:"name"`
@@ -278,6 +356,55 @@ type FocusArea struct {
 
 	// Weight is a positive number that determines how much focus should be put on this area.
 	Weight float64 `json:"weight"`
+
+	// SnapshotWarmup is a file with a program that sets up state for this area (e.g. opens devices,
+	// mounts file systems, creates sockets). In snapshot mode, the program is executed once before
+	// the VM is snapshotted, and all test programs start with its calls (which are not executed again),
+	// so they run against the state it has set up. VMs are distributed among the focus areas according
+	// to their weights, and VMs with a warm-up program only fuzz the programs of their area.
+	// Programs generated and mutated for the area use the resources created by the warm-up program.
+	SnapshotWarmup string `json:"snapshot_warmup,omitempty"`
+}
+
+type Diagnostic struct {
+	// Name is printed in the output before the command output.
+	Name string `json:"name"`
+	// Shell command to run in the VM.
+	Command string `json:"command"`
+	// Crash types (see pkg/report/crash) to run the command for, e.g. "HANG" or "LOCKDEP".
+	// "UNKNOWN" matches crashes without a type, e.g. "no output from test machine".
+	// If neither types nor title are specified, the command is run for all crashes.
+	Types []string `json:"types,omitempty"`
+	// Regexp to match crash titles against (optional).
+	Title string `json:"title,omitempty"`
+	// The command output is truncated to this size in bytes (default: 64KB).
+	MaxOutput int `json:"max_output,omitempty"`
+	// Remove kernel pointer values from the output, they take lots of space but don't add any value.
+	StripPointers bool `json:"strip_pointers,omitempty"`
+	// The command makes the kernel print into the console (e.g. sysrq), so the console output
+	// needs to be collected for some time after the command.
+	Console bool `json:"console,omitempty"`
+}
+
+type Ftrace struct {
+	// Trace events to enable in the "subsystem:event" format (see set_event in the tracefs docs),
+	// a whole subsystem can be enabled with "subsystem:*".
+	Events []string `json:"events,omitempty"`
+	// Functions to trace with the function_graph tracer (set_graph_function),
+	// may contain glob patterns.
+	Functions []string `json:"functions,omitempty"`
+	// Per-CPU trace buffer size in KB (default: 256).
+	// Note: the buffer is dumped over the console if the kernel crashes,
+	// large buffers slow down crash reporting and may not be captured completely.
+	BufferSizeKB int `json:"buffer_size_kb,omitempty"`
+}
+
+type Symbolizer struct {
+	// Symbolizer backend: "addr2line" (default) or "llvm-symbolizer".
+	Backend string `json:"backend,omitempty"`
+	// Directory for the persistent cache of symbolization results keyed by the binary build ID.
+	// The directory can be shared by several managers and tools.
+	CacheDir string `json:"cache_dir,omitempty"`
 }
 
 type Subsystem struct {
@@ -10,10 +10,12 @@ import (
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"slices"
 	"strings"
 
 	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/symbolizer"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/prog"
 	_ "github.com/google/syzkaller/sys" // most mgrconfig users want targets too
@@ -176,11 +178,33 @@ func Complete(cfg *Config) error {
 	if cfg.Procs < 1 || cfg.Procs > prog.MaxPids {
 		return fmt.Errorf("bad config param procs: '%v', want [1, %v]", cfg.Procs, prog.MaxPids)
 	}
+	if cfg.ConsoleRecords < 0 {
+		return fmt.Errorf("bad config param console_records: %v", cfg.ConsoleRecords)
+	}
+	for i, diag := range cfg.Diagnostics {
+		if diag.Command == "" || diag.MaxOutput < 0 {
+			return fmt.Errorf("bad config param diagnostics[%v]: empty command or negative max_output", i)
+		}
+		if _, err := regexp.Compile(diag.Title); err != nil {
+			return fmt.Errorf("bad config param diagnostics[%v]: bad title regexp: %w", i, err)
+		}
+	}
+	if err := cfg.completeFtrace(); err != nil {
+		return err
+	}
+	if err := cfg.completeSymbolizer(); err != nil {
+		return err
+	}
 	switch cfg.Sandbox {
 	case "none", "setuid", "namespace", "android":
 	default:
 		return fmt.Errorf("config param sandbox must contain one of none/setuid/namespace/android")
 	}
+	switch cfg.ReproVerify {
+	case "", "title", "type", "access", "stacks":
+	default:
+		return fmt.Errorf("config param repro_verify must contain one of title/type/access/stacks")
+	}
 	if err := cfg.checkSSHParams(); err != nil {
 		return err
 	}
@@ -193,6 +217,12 @@ func Complete(cfg *Config) error {
 	if cfg.FuzzingVMs < 0 {
 		return fmt.Errorf("fuzzing_vms cannot be less than 0")
 	}
+	if cfg.VMQuarantine < 0 {
+		return fmt.Errorf("vm_quarantine cannot be less than 0")
+	}
+	if err := cfg.completeAutoscale(); err != nil {
+		return err
+	}
 
 	var err error
 	cfg.Syscalls, err = ParseEnabledSyscalls(cfg.Target, cfg.EnabledSyscalls, cfg.DisabledSyscalls,
@@ -217,6 +247,43 @@ func Complete(cfg *Config) error {
 	return nil
 }
 
+func (cfg *Config) completeAutoscale() error {
+	as := cfg.Autoscale
+	if as == nil {
+		return nil
+	}
+	if as.MinVMs == 0 {
+		as.MinVMs = 1
+	}
+	if as.MaxCPUPressure == 0 {
+		as.MaxCPUPressure = 20
+	}
+	if as.MaxMemPressure == 0 {
+		as.MaxMemPressure = 10
+	}
+	if as.MinCPUPressure == 0 {
+		as.MinCPUPressure = 5
+	}
+	if as.MaxBootFailureRate == 0 {
+		as.MaxBootFailureRate = 0.5
+	}
+	if as.Interval == 0 {
+		as.Interval = 30
+	}
+	switch {
+	case as.MinVMs < 0 || as.MaxVMs < 0:
+		return fmt.Errorf("autoscale: min_vms and max_vms cannot be negative")
+	case as.MaxVMs != 0 && as.MaxVMs < as.MinVMs:
+		return fmt.Errorf("autoscale: max_vms (%v) is less than min_vms (%v)", as.MaxVMs, as.MinVMs)
+	case as.MinCPUPressure > as.MaxCPUPressure:
+		return fmt.Errorf("autoscale: min_cpu_pressure (%v) is greater than max_cpu_pressure (%v)",
+			as.MinCPUPressure, as.MaxCPUPressure)
+	case as.Interval < 0:
+		return fmt.Errorf("autoscale: interval cannot be negative")
+	}
+	return nil
+}
+
 func (cfg *Config) completeServices() error {
 	if cfg.HubClient != "" {
 		if err := checkNonEmpty(
@@ -366,6 +433,66 @@ func (cfg *Config) completeBinaries() error {
 	return nil
 }
 
+// Event and function names are passed to a shell in the VM, so we restrict them
+// to the characters that can actually appear in them (plus glob patterns).
+var ftraceNameRe = regexp.MustCompile(`^[a-zA-Z0-9_.:*?\[\]-]+$`)
+
+func (cfg *Config) completeFtrace() error {
+	ftrace := cfg.Ftrace
+	if ftrace == nil {
+		return nil
+	}
+	if cfg.TargetOS != targets.Linux {
+		return fmt.Errorf("ftrace is only supported on linux")
+	}
+	return CompleteFtrace(ftrace)
+}
+
+// CompleteFtrace checks the ftrace config and fills in the defaults.
+func CompleteFtrace(ftrace *Ftrace) error {
+	if len(ftrace.Events) == 0 && len(ftrace.Functions) == 0 {
+		return fmt.Errorf("bad config param ftrace: no events or functions specified")
+	}
+	for _, name := range append(append([]string{}, ftrace.Events...), ftrace.Functions...) {
+		if !ftraceNameRe.MatchString(name) {
+			return fmt.Errorf("bad config param ftrace: bad event/function name %q", name)
+		}
+	}
+	if ftrace.BufferSizeKB < 0 {
+		return fmt.Errorf("bad config param ftrace: negative buffer_size_kb")
+	}
+	if ftrace.BufferSizeKB == 0 {
+		ftrace.BufferSizeKB = 256
+	}
+	return nil
+}
+
+func (cfg *Config) completeSymbolizer() error {
+	symb := cfg.Symbolizer
+	if symb == nil {
+		return nil
+	}
+	if symb.Backend != "" && !slices.Contains(symbolizer.Backends, symb.Backend) {
+		return fmt.Errorf("bad config param symbolizer: unknown backend %q, supported: %q",
+			symb.Backend, symbolizer.Backends)
+	}
+	if symb.CacheDir != "" {
+		symb.CacheDir = osutil.Abs(symb.CacheDir)
+	}
+	return nil
+}
+
+// SymbolizerConfig returns the symbolizer configuration (nil means the default symbolizer).
+func (cfg *Config) SymbolizerConfig() *symbolizer.Config {
+	if cfg.Symbolizer == nil {
+		return nil
+	}
+	return &symbolizer.Config{
+		Backend:  cfg.Symbolizer.Backend,
+		CacheDir: cfg.Symbolizer.CacheDir,
+	}
+}
+
 func (cfg *Config) completeFocusAreas() error {
 	names := map[string]bool{}
 	seenEmptyFilter := false
@@ -379,6 +506,15 @@ func (cfg *Config) completeFocusAreas() error {
 		if area.Weight <= 0 {
 			return fmt.Errorf("focus area #%d: negative weight", i)
 		}
+		if area.SnapshotWarmup != "" {
+			if !cfg.Snapshot {
+				return fmt.Errorf("focus area #%d: snapshot_warmup requires snapshot mode", i)
+			}
+			if !osutil.IsExist(area.SnapshotWarmup) {
+				return fmt.Errorf("focus area #%d: snapshot_warmup file %q does not exist",
+					i, area.SnapshotWarmup)
+			}
+		}
 		if area.Filter.Empty() {
 			if seenEmptyFilter {
 				return fmt.Errorf("there must be only one focus area with an empty filter")
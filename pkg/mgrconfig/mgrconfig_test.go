@@ -4,6 +4,8 @@
 package mgrconfig_test
 
 import (
+	"bytes"
+	"os"
 	"path/filepath"
 	"testing"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/google/syzkaller/vm/gce"
 	"github.com/google/syzkaller/vm/proxyapp"
 	"github.com/google/syzkaller/vm/qemu"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestCanned(t *testing.T) {
@@ -66,3 +69,31 @@ func TestMatchSyscall(t *testing.T) {
 		}
 	}
 }
+
+func TestReload(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("testdata", "qemu.cfg"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := LoadData(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Formatting-only changes of the VM config must not be reported.
+	newData := bytes.ReplaceAll(data, []byte(`"count": 16`), []byte(`"count":   16`))
+	newData = bytes.ReplaceAll(newData, []byte(`"some known bug"`), []byte(`"another known bug"`))
+	newData = bytes.ReplaceAll(newData, []byte(`"procs": 4`), []byte(`"procs": 8`))
+	res, err := cfg.Reload(newData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, []string{"suppressions"}, res.Applied)
+	assert.Equal(t, []string{"procs"}, res.Restart)
+	assert.Equal(t, []string{"another known bug"}, cfg.Suppressions)
+	assert.Equal(t, 4, cfg.Procs)
+
+	// Invalid configs must not change anything.
+	_, err = cfg.Reload(bytes.ReplaceAll(data, []byte(`"procs": 4`), []byte(`"procs": 1000`)))
+	assert.Error(t, err)
+	assert.Equal(t, []string{"another known bug"}, cfg.Suppressions)
+}
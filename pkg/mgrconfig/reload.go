@@ -0,0 +1,97 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package mgrconfig
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// runtimeFields are config fields (identified by their json names) that a running
+// syz-manager can pick up without a restart.
+var runtimeFields = map[string]bool{
+	"email_addrs":  true,
+	"ignores":      true,
+	"interests":    true,
+	"reproduce":    true,
+	"suppressions": true,
+}
+
+type ReloadResult struct {
+	// Changed fields that were updated in the config.
+	Applied []string
+	// Changed fields that will only take effect after a restart.
+	Restart []string
+}
+
+func (res *ReloadResult) Changed(field string) bool {
+	for _, name := range res.Applied {
+		if name == field {
+			return true
+		}
+	}
+	return false
+}
+
+// Reload validates the new config data and updates the fields of cfg that
+// can be changed at runtime. The remaining changed fields are only reported.
+// On error, cfg is left unchanged.
+func (cfg *Config) Reload(data []byte) (*ReloadResult, error) {
+	newCfg, err := LoadData(data)
+	if err != nil {
+		return nil, err
+	}
+	res := new(ReloadResult)
+	var updates [][2]reflect.Value
+	err = diffFields(reflect.ValueOf(cfg).Elem(), reflect.ValueOf(newCfg).Elem(), "", res, &updates)
+	if err != nil {
+		return nil, err
+	}
+	for _, upd := range updates {
+		upd[0].Set(upd[1])
+	}
+	return res, nil
+}
+
+func diffFields(cur, upd reflect.Value, prefix string, res *ReloadResult, updates *[][2]reflect.Value) error {
+	for i := 0; i < cur.NumField(); i++ {
+		field := cur.Type().Field(i)
+		tag := strings.Split(field.Tag.Get("json"), ",")[0]
+		if tag == "-" || !field.IsExported() {
+			continue
+		}
+		name := tag
+		if name == "" {
+			name = strings.ToLower(field.Name)
+			if field.Type.Kind() == reflect.Struct {
+				if err := diffFields(cur.Field(i), upd.Field(i), prefix+name+".", res, updates); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		name = prefix + name
+		// Compare serialized values, so that e.g. formatting of raw VM configs does not matter.
+		curData, err := json.Marshal(cur.Field(i).Interface())
+		if err != nil {
+			return fmt.Errorf("failed to serialize %v: %w", name, err)
+		}
+		updData, err := json.Marshal(upd.Field(i).Interface())
+		if err != nil {
+			return fmt.Errorf("failed to serialize %v: %w", name, err)
+		}
+		if string(curData) == string(updData) {
+			continue
+		}
+		if !runtimeFields[name] {
+			res.Restart = append(res.Restart, name)
+			continue
+		}
+		res.Applied = append(res.Applied, name)
+		*updates = append(*updates, [2]reflect.Value{cur.Field(i), upd.Field(i)})
+	}
+	return nil
+}
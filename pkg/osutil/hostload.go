@@ -0,0 +1,108 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package osutil
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// HostLoad describes how busy the host machine is.
+type HostLoad struct {
+	// Share of time (in percent) during which some tasks were stalled waiting for CPU
+	// over the last 10 seconds.
+	CPUPressure float64
+	// Same, but for memory.
+	MemPressure float64
+	// Share of the physical memory (in percent) that is available for new allocations.
+	MemAvailable float64
+}
+
+func (load HostLoad) String() string {
+	return fmt.Sprintf("cpu pressure %.1f%%, memory pressure %.1f%%, available memory %.1f%%",
+		load.CPUPressure, load.MemPressure, load.MemAvailable)
+}
+
+// readHostLoad reads the load from a procfs mounted at root.
+// If the kernel does not support pressure stall information, CPU pressure is
+// estimated from the load average and memory pressure is left zero.
+func readHostLoad(root string, numCPU int) (HostLoad, error) {
+	var load HostLoad
+	memInfo, err := os.ReadFile(filepath.Join(root, "meminfo"))
+	if err != nil {
+		return load, err
+	}
+	if load.MemAvailable, err = parseMemAvailable(memInfo); err != nil {
+		return load, err
+	}
+	if cpu, err := os.ReadFile(filepath.Join(root, "pressure", "cpu")); err == nil {
+		if load.CPUPressure, err = parsePressure(cpu); err != nil {
+			return load, err
+		}
+		mem, err := os.ReadFile(filepath.Join(root, "pressure", "memory"))
+		if err != nil {
+			return load, err
+		}
+		if load.MemPressure, err = parsePressure(mem); err != nil {
+			return load, err
+		}
+		return load, nil
+	}
+	loadAvg, err := os.ReadFile(filepath.Join(root, "loadavg"))
+	if err != nil {
+		return load, err
+	}
+	fields := strings.Fields(string(loadAvg))
+	if len(fields) == 0 {
+		return load, fmt.Errorf("unexpected loadavg format: %q", loadAvg)
+	}
+	avg, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return load, fmt.Errorf("unexpected loadavg format: %q", loadAvg)
+	}
+	// Runnable tasks in excess of the number of CPUs have to wait.
+	load.CPUPressure = min(100, max(0, (avg/float64(numCPU)-1)*100))
+	return load, nil
+}
+
+// parsePressure extracts the "some avg10" value from a /proc/pressure file.
+func parsePressure(data []byte) (float64, error) {
+	for s := bufio.NewScanner(bytes.NewReader(data)); s.Scan(); {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 || fields[0] != "some" {
+			continue
+		}
+		val, ok := strings.CutPrefix(fields[1], "avg10=")
+		if !ok {
+			break
+		}
+		return strconv.ParseFloat(val, 64)
+	}
+	return 0, fmt.Errorf("unexpected pressure file format: %q", data)
+}
+
+func parseMemAvailable(data []byte) (float64, error) {
+	vals := make(map[string]uint64)
+	for s := bufio.NewScanner(bytes.NewReader(data)); s.Scan(); {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		val, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		vals[strings.TrimSuffix(fields[0], ":")] = val
+	}
+	total, available := vals["MemTotal"], vals["MemAvailable"]
+	if total == 0 {
+		return 0, fmt.Errorf("no MemTotal in meminfo")
+	}
+	return float64(available) * 100 / float64(total), nil
+}
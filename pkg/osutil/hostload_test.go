@@ -0,0 +1,42 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package osutil
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadHostLoad(t *testing.T) {
+	const memInfo = `MemTotal:       16000000 kB
+MemFree:         1000000 kB
+MemAvailable:    4000000 kB
+`
+	dir := t.TempDir()
+	require.NoError(t, WriteFile(filepath.Join(dir, "meminfo"), []byte(memInfo)))
+	require.NoError(t, WriteFile(filepath.Join(dir, "loadavg"), []byte("12.00 8.00 4.00 3/900 12345\n")))
+
+	// No pressure stall information, the load average is used instead.
+	load, err := readHostLoad(dir, 8)
+	require.NoError(t, err)
+	assert.Equal(t, HostLoad{CPUPressure: 50, MemAvailable: 25}, load)
+
+	require.NoError(t, MkdirAll(filepath.Join(dir, "pressure")))
+	require.NoError(t, WriteFile(filepath.Join(dir, "pressure", "cpu"),
+		[]byte("some avg10=12.50 avg60=3.00 avg300=1.00 total=123\n"+
+			"full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n")))
+	require.NoError(t, WriteFile(filepath.Join(dir, "pressure", "memory"),
+		[]byte("some avg10=1.25 avg60=0.00 avg300=0.00 total=1\n"+
+			"full avg10=1.00 avg60=0.00 avg300=0.00 total=1\n")))
+	load, err = readHostLoad(dir, 8)
+	require.NoError(t, err)
+	assert.Equal(t, HostLoad{CPUPressure: 12.5, MemPressure: 1.25, MemAvailable: 25}, load)
+
+	require.NoError(t, WriteFile(filepath.Join(dir, "pressure", "memory"), []byte("garbage")))
+	_, err = readHostLoad(dir, 8)
+	assert.Error(t, err)
+}
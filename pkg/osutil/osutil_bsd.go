@@ -6,8 +6,10 @@
 package osutil
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"time"
 )
 
@@ -23,6 +25,10 @@ func SystemMemorySize() uint64 {
 	return 0
 }
 
+func ReadHostLoad() (HostLoad, error) {
+	return HostLoad{}, fmt.Errorf("host load is not supported on %v", runtime.GOOS)
+}
+
 func prolongPipe(r, w *os.File) {
 }
 
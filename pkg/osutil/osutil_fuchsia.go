@@ -6,8 +6,10 @@
 package osutil
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"time"
 )
 
@@ -18,6 +20,9 @@ func creationTime(fi os.FileInfo) time.Time {
 func HandleInterrupts(shutdown chan struct{}) {
 }
 
+func HandleHangups(fn func()) {
+}
+
 func RemoveAll(dir string) error {
 	return os.RemoveAll(dir)
 }
@@ -26,6 +31,10 @@ func SystemMemorySize() uint64 {
 	return 0
 }
 
+func ReadHostLoad() (HostLoad, error) {
+	return HostLoad{}, fmt.Errorf("host load is not supported on %v", runtime.GOOS)
+}
+
 func ProcessExitStatus(ps *os.ProcessState) int {
 	// TODO: can be extracted from ExitStatus string.
 	return 0
@@ -8,6 +8,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strconv"
 	"strings"
 	"sync"
@@ -45,6 +46,11 @@ func SystemMemorySize() uint64 {
 	return uint64(info.Totalram) // nolint:unconvert
 }
 
+// ReadHostLoad returns the current load of the host machine.
+func ReadHostLoad() (HostLoad, error) {
+	return readHostLoad("/proc", runtime.NumCPU())
+}
+
 func removeImmutable(fname string) error {
 	// Reset FS_XFLAG_IMMUTABLE/FS_XFLAG_APPEND.
 	fd, err := syscall.Open(fname, syscall.O_RDONLY, 0)
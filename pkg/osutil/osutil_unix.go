@@ -85,6 +85,17 @@ func HandleInterrupts(shutdown chan struct{}) {
 	}()
 }
 
+// HandleHangups calls fn every time the process receives SIGHUP.
+func HandleHangups(fn func()) {
+	go func() {
+		c := make(chan os.Signal, 1)
+		signal.Notify(c, syscall.SIGHUP)
+		for range c {
+			fn()
+		}
+	}()
+}
+
 func LongPipe() (io.ReadCloser, io.WriteCloser, error) {
 	r, w, err := os.Pipe()
 	if err != nil {
@@ -4,8 +4,10 @@
 package osutil
 
 import (
+	"fmt"
 	"os"
 	"os/exec"
+	"runtime"
 	"syscall"
 	"time"
 )
@@ -17,6 +19,9 @@ func creationTime(fi os.FileInfo) time.Time {
 func HandleInterrupts(shutdown chan struct{}) {
 }
 
+func HandleHangups(fn func()) {
+}
+
 func RemoveAll(dir string) error {
 	return os.RemoveAll(dir)
 }
@@ -25,6 +30,10 @@ func SystemMemorySize() uint64 {
 	return 0
 }
 
+func ReadHostLoad() (HostLoad, error) {
+	return HostLoad{}, fmt.Errorf("host load is not supported on %v", runtime.GOOS)
+}
+
 func prolongPipe(r, w *os.File) {
 }
 
@@ -6,6 +6,7 @@ package osutil
 import (
 	"archive/tar"
 	"compress/gzip"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
@@ -65,3 +66,50 @@ func tarDirectory(dir string, writer io.Writer) error {
 		return err
 	})
 }
+
+// ExtractTarGz unpacks an archive created by TarGzDirectory into dir.
+func ExtractTarGz(reader io.Reader, dir string) error {
+	gzr, err := gzip.NewReader(reader)
+	if err != nil {
+		return err
+	}
+	defer gzr.Close()
+	return extractTar(gzr, dir)
+}
+
+func extractTar(reader io.Reader, dir string) error {
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := filepath.FromSlash(header.Name)
+		if !filepath.IsLocal(name) {
+			return fmt.Errorf("archive entry %q points outside of the target dir", header.Name)
+		}
+		path := filepath.Join(dir, name)
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := MkdirAll(path); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := MkdirAll(filepath.Dir(path)); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, DefaultFilePerm)
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(f, tr)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
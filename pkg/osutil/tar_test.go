@@ -7,6 +7,8 @@ import (
 	"archive/tar"
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -47,3 +49,36 @@ func TestTarDirectory(t *testing.T) {
 
 	assert.Equal(t, items, found)
 }
+
+func TestExtractTarGz(t *testing.T) {
+	dir := t.TempDir()
+	items := map[string]string{
+		"file1.txt":     "first file content",
+		"dir/file2.txt": "second file content",
+		"empty.txt":     "",
+	}
+	require.NoError(t, FillDirectory(dir, items))
+
+	var buf bytes.Buffer
+	require.NoError(t, TarGzDirectory(dir, &buf))
+
+	outDir := t.TempDir()
+	require.NoError(t, ExtractTarGz(&buf, outDir))
+	for name, content := range items {
+		data, err := os.ReadFile(filepath.Join(outDir, filepath.FromSlash(name)))
+		require.NoError(t, err)
+		assert.Equal(t, content, string(data))
+	}
+}
+
+func TestExtractTarOutside(t *testing.T) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	require.NoError(t, tw.WriteHeader(&tar.Header{
+		Name:     "../evil.txt",
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+	}))
+	require.NoError(t, tw.Close())
+	assert.Error(t, extractTar(&buf, t.TempDir()))
+}
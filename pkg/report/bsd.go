@@ -52,7 +52,7 @@ func (ctx *bsd) Parse(output []byte) *Report {
 }
 
 func (ctx *bsd) Symbolize(rep *Report) error {
-	symb := symbolizer.Make(ctx.config.target)
+	symb := symbolizer.Make(ctx.config.target, ctx.config.symbolizer)
 	defer symb.Close()
 	var symbolized []byte
 	prefix := rep.reportPrefixLen
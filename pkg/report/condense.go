@@ -0,0 +1,168 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+const (
+	// Number of programs of the crashed proc to keep.
+	condensePrograms = 3
+	// Number of console lines right before the crash to keep.
+	condenseContextLines = 30
+	// Number of lines to keep after a potentially related kernel message.
+	condenseMessageLines = 10
+	// Number of lines to keep after the start of the crash.
+	// Report end positions only cover the oops header lines, so we can't rely on them.
+	condenseReportLines = 300
+	// Runs of dropped lines shorter than this are kept as is.
+	condenseMinCut = 3
+)
+
+var (
+	condenseProgramRe = regexp.MustCompile(`executing program (\d+)(?: \(id=(\d+)\))?`)
+	// Section headers added by rpcserver.PrependExecuting.
+	condenseSectionRe = regexp.MustCompile(`^(?:last executing test programs|kernel console output.*):$`)
+	// Kernel messages that may be related to the crash, but are not reports themselves
+	// (or are ignored/suppressed reports).
+	condenseMessageRe = regexp.MustCompile(`WARNING:|BUG:|INFO:|FAULT_INJECTION:|kernel BUG|` +
+		`general protection fault|Oops|cut here|lockdep|possible .*locking`)
+)
+
+// CondenseLog returns a shorter version of rep.Output suitable for attaching to bug reports.
+// It keeps the programs recently executed by the crashed proc (or the last program
+// of each proc if the proc is unknown), earlier reports and kernel messages that may be related
+// to the crash, the lines right before the crash and the crash itself.
+// The rest is replaced with "<<cut N lines out>>" markers.
+func (reporter *Reporter) CondenseLog(rep *Report) []byte {
+	output := rep.Output
+	lines := bytes.SplitAfter(output, []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	offsets := make([]int, len(lines)+1)
+	for i, line := range lines {
+		offsets[i+1] = offsets[i] + len(line)
+	}
+	keep := make([]bool, len(lines))
+	keepRange := func(start, end int) {
+		for i := range lines {
+			if offsets[i+1] > start && offsets[i] < end {
+				keep[i] = true
+			}
+		}
+	}
+	lineAt := func(pos int) int {
+		for i := range lines {
+			if offsets[i+1] > pos {
+				return i
+			}
+		}
+		return len(lines)
+	}
+	crashLine := lineAt(rep.StartPos)
+
+	for _, prog := range condensePickPrograms(lines[:crashLine], rep.Executor) {
+		for i := prog.first; i < prog.last; i++ {
+			keep[i] = true
+		}
+	}
+	for i, line := range lines[:crashLine] {
+		if condenseSectionRe.Match(bytes.TrimSpace(line)) {
+			keep[i] = true
+		}
+		if condenseMessageRe.Match(line) {
+			for j := i; j < min(i+condenseMessageLines, crashLine); j++ {
+				keep[j] = true
+			}
+		}
+	}
+	for _, other := range ParseAll(reporter, output[:rep.StartPos]) {
+		end := offsets[min(lineAt(other.StartPos)+condenseMessageLines, crashLine)]
+		keepRange(other.StartPos, max(other.EndPos, end))
+	}
+	for i := max(crashLine-condenseContextLines, 0); i < min(crashLine+condenseReportLines, len(lines)); i++ {
+		keep[i] = true
+	}
+	keepRange(rep.StartPos, rep.EndPos)
+
+	buf := new(bytes.Buffer)
+	for i := 0; i < len(lines); {
+		if keep[i] {
+			buf.Write(lines[i])
+			i++
+			continue
+		}
+		end := i
+		for end < len(lines) && !keep[end] {
+			end++
+		}
+		if end-i < condenseMinCut {
+			buf.Write(output[offsets[i]:offsets[end]])
+		} else {
+			fmt.Fprintf(buf, "<<cut %d lines out>>\n", end-i)
+		}
+		i = end
+	}
+	return buf.Bytes()
+}
+
+type condenseProgram struct {
+	proc  int
+	id    int
+	first int
+	last  int
+}
+
+func condensePickPrograms(lines [][]byte, executor *ExecutorInfo) []*condenseProgram {
+	var progs []*condenseProgram
+	for i := 0; i < len(lines); i++ {
+		match := condenseProgramRe.FindSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		prog := &condenseProgram{id: -1, first: i}
+		prog.proc, _ = strconv.Atoi(string(match[1]))
+		if match[2] != nil {
+			prog.id, _ = strconv.Atoi(string(match[2]))
+		}
+		// The program continues until an empty line or the next program.
+		for i++; i < len(lines); i++ {
+			if len(bytes.TrimSpace(lines[i])) == 0 || condenseProgramRe.Match(lines[i]) {
+				break
+			}
+		}
+		prog.last = i
+		i--
+		progs = append(progs, prog)
+	}
+	var res []*condenseProgram
+	if executor == nil {
+		// We don't know which proc has crashed, take the last program of each proc.
+		seen := make(map[int]bool)
+		for i := len(progs) - 1; i >= 0; i-- {
+			if !seen[progs[i].proc] {
+				seen[progs[i].proc] = true
+				res = append(res, progs[i])
+			}
+		}
+		return res
+	}
+	procProgs := 0
+	for i := len(progs) - 1; i >= 0; i-- {
+		prog := progs[i]
+		if prog.proc != executor.ProcID {
+			continue
+		}
+		if procProgs < condensePrograms || prog.id == executor.ExecID {
+			res = append(res, prog)
+		}
+		procProgs++
+	}
+	return res
+}
@@ -0,0 +1,69 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCondenseLog(t *testing.T) {
+	reporter, _ := prepareLinuxReporter(t, targets.AMD64)
+	buf := new(strings.Builder)
+	buf.WriteString("last executing test programs:\n\n")
+	for id := 10; id < 20; id++ {
+		fmt.Fprintf(buf, "1.%vs ago: executing program %v (id=%v):\nopenat(0x0, &(0x7f0000000000)='./file%v\\x00', 0x0, 0x0)\n\n",
+			20-id, id%2, id, id)
+	}
+	buf.WriteString("kernel console output (not intermixed with test programs):\n\n")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(buf, "[   %v.000000][ T1000] audit: type=1400 audit(%v): noise\n", i, i)
+	}
+	buf.WriteString("[  100.000000][ T1000] FAULT_INJECTION: forcing a failure.\n")
+	for i := 0; i < 100; i++ {
+		fmt.Fprintf(buf, "[  %v.000000][ T1000] audit: type=1400 audit(%v): noise\n", 101+i, i)
+	}
+	buf.WriteString(`[  300.000000][ T5000] ==================================================================
+[  300.000000][ T5000] BUG: KASAN: use-after-free in foo+0x10/0x20
+[  300.000000][ T5000] Read of size 8 at addr ffff888012345678 by task syz.1.11/5000
+[  300.000000][ T5000] CPU: 0 UID: 0 PID: 5000 Comm: syz.1.11 Not tainted 6.12.0 #0
+[  300.000000][ T5000] Call Trace:
+[  300.000000][ T5000]  <TASK>
+[  300.000000][ T5000]  foo+0x10/0x20
+[  300.000000][ T5000]  __x64_sys_openat+0x10/0x20
+[  300.000000][ T5000]  </TASK>
+[  300.000000][ T5000] ==================================================================
+`)
+	rep := reporter.Parse([]byte(buf.String()))
+	require.NotNil(t, rep)
+	require.Equal(t, &ExecutorInfo{ProcID: 1, ExecID: 11}, rep.Executor)
+	log := string(reporter.CondenseLog(rep))
+	assert.Less(t, len(log), len(rep.Output)/2)
+
+	// Programs of proc 1: the last 3 and the crashed one.
+	for id := 10; id < 20; id++ {
+		assert.Equal(t, id == 11 || id == 15 || id == 17 || id == 19,
+			strings.Contains(log, fmt.Sprintf("(id=%v)", id)), "program %v", id)
+	}
+	assert.Contains(t, log, "last executing test programs:\n")
+	assert.Contains(t, log, "kernel console output (not intermixed with test programs):\n")
+	assert.Contains(t, log, "FAULT_INJECTION: forcing a failure.\n")
+	assert.Contains(t, log, "<<cut 101 lines out>>\n")
+	assert.Contains(t, log, "audit(99): noise\n")
+	assert.NotContains(t, log, "audit(50): noise\n")
+	assert.True(t, strings.HasSuffix(log, string(rep.Output[rep.StartPos:])))
+
+	// Without the executor info, the last program of each proc is kept.
+	rep.Executor = nil
+	log = string(reporter.CondenseLog(rep))
+	for id := 10; id < 20; id++ {
+		assert.Equal(t, id == 18 || id == 19,
+			strings.Contains(log, fmt.Sprintf("(id=%v)", id)), "program %v", id)
+	}
+}
@@ -42,6 +42,9 @@ const (
 	MemorySafetyUBSAN       = Type("MEMORY_SAFETY_UBSAN")
 	NullPtrDerefBUG         = Type("NULL-POINTER-DEREFERENCE")
 	RefcountWARNING         = Type("REFCOUNT_WARNING")
+	RustArithmetic          = Type("RUST-ARITHMETIC")
+	RustOutOfBounds         = Type("RUST-OUT-OF-BOUNDS")
+	RustPanic               = Type("RUST-PANIC")
 	UBSAN                   = Type("UBSAN")
 	Warning                 = Type("WARNING")
 	// keep-sorted end
@@ -106,6 +109,11 @@ func (t Type) IsDoS() bool {
 	return t == Bug || t == DoS
 }
 
+// IsRust returns true for panics of Rust kernel code.
+func (t Type) IsRust() bool {
+	return t == RustArithmetic || t == RustOutOfBounds || t == RustPanic
+}
+
 func (t Type) IsHang() bool {
 	return t == Hang
 }
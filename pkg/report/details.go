@@ -0,0 +1,383 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Details is a structured representation of a crash report.
+type Details struct {
+	// Stacks in the order they appear in the report.
+	Stacks []*Stack
+	// Registers of the first register dump in the order they are printed.
+	Registers []Register
+	// The task/CPU the crash happened on.
+	Task *TaskInfo
+	// Bad memory access details reported by a sanitizer (KASAN).
+	Access *AccessInfo
+	// Locks held by tasks (e.g. in lockdep reports and hung task reports).
+	HeldLocks []*HeldLock
+}
+
+type StackKind string
+
+const (
+	// The stack of the crash itself (the first one in the report).
+	StackCrash StackKind = "crash"
+	// Where the accessed object was allocated.
+	StackAllocated StackKind = "allocated"
+	// Where the accessed object was freed.
+	StackFreed StackKind = "freed"
+	// Auxiliary stacks saved by KASAN (e.g. where related work was queued).
+	StackAux StackKind = "aux"
+	// Where a lock of a lockdep dependency chain was acquired.
+	StackLock StackKind = "lock"
+	// Other stacks (e.g. other CPUs or tasks).
+	StackOther StackKind = "other"
+)
+
+type Stack struct {
+	Kind StackKind
+	// The line that starts the stack, e.g. "Freed by task 12:" or "-> #1 (fs_reclaim){+.+.}-{0:0}:".
+	Header string
+	// The task that allocated/freed the object (for allocated/freed stacks).
+	PID    int
+	Frames []*Frame
+}
+
+type Frame struct {
+	Function string
+	Offset   uint64
+	Size     uint64
+	Module   string
+	// File/Line are set only for symbolized frames.
+	File   string
+	Line   int
+	Inline bool
+	// The instruction pointer frame (e.g. RIP: or pc : lines).
+	IP bool
+	// The frame is marked as questionable by the unwinder (? prefix).
+	Unreliable bool
+}
+
+type Register struct {
+	Name  string
+	Value string
+}
+
+type TaskInfo struct {
+	CPU  int
+	PID  int
+	Comm string
+	// Taint flags, empty if the kernel is not tainted.
+	Tainted  string
+	Kernel   string
+	Hardware string
+}
+
+type AccessInfo struct {
+	Sanitizer string // e.g. "KASAN"
+	Kind      string // e.g. "use-after-free" or "slab-out-of-bounds"
+	Write     bool
+	Address   uint64
+	Size      int
+	// The object the address belongs to (if reported).
+	ObjectAddress uint64
+	ObjectSize    int
+	Cache         string
+	// Offset of the address relative to the object,
+	// negative if the address is to the left of the object.
+	Offset int
+	// Whether the address is inside of the object.
+	Inside bool
+}
+
+func (s *Stack) String() string {
+	buf := new(strings.Builder)
+	buf.WriteString(string(s.Kind))
+	if s.Header != "" {
+		buf.WriteString(" (" + s.Header + ")")
+	}
+	buf.WriteString(":\n")
+	for _, frame := range s.Frames {
+		buf.WriteString(" " + frame.String() + "\n")
+	}
+	return buf.String()
+}
+
+func (f *Frame) String() string {
+	buf := new(strings.Builder)
+	if f.Unreliable {
+		buf.WriteString("? ")
+	}
+	buf.WriteString(f.Function)
+	if f.Size != 0 {
+		buf.WriteString("+0x" + strconv.FormatUint(f.Offset, 16) + "/0x" + strconv.FormatUint(f.Size, 16))
+	}
+	if f.File != "" {
+		buf.WriteString(" " + f.File + ":" + strconv.Itoa(f.Line))
+	}
+	if f.Inline {
+		buf.WriteString(" [inline]")
+	}
+	if f.Module != "" {
+		buf.WriteString(" [" + f.Module + "]")
+	}
+	return buf.String()
+}
+
+type HeldLock struct {
+	// The task holding the lock in the comm/pid format.
+	Task string
+	Lock string
+	// Where the lock was acquired.
+	Frame *Frame
+}
+
+// Register names are upper case on x86 (except for knlGS), x0-x30/sp on arm64,
+// values may be prefixed with a segment and followed with a selector, e.g. "GS:ffff88802c600000(0000)".
+const linuxRegister = `([A-Z][A-Z0-9_]*|knlGS|x[0-9]+|sp) ?: *((?:[0-9a-f]{4}:)?(?:0x)?[0-9a-f]+)(?:\([0-9a-f]+\))?`
+
+var (
+	linuxLinePrefixRe  = regexp.MustCompile(`^(?:\[ *[0-9]+\.[0-9]+\])?(?:\[ *[TC][0-9]+\])?`)
+	linuxInlineFrameRe = regexp.MustCompile(`^(?:.*\] |(R?IP: (?:[0-9]+:)?|pc : ))?[ \t]*` +
+		`([a-zA-Z0-9_.]+) ([^ ]+):([0-9]+) \[inline\]`)
+	// Symbolized frames have file:line inserted before the module name.
+	linuxFrameFileRe     = regexp.MustCompile(`^ ([^ \[]+):([0-9]+)(?: \[([a-zA-Z0-9_.]+)\])?`)
+	linuxRegisterRe      = regexp.MustCompile(linuxRegister)
+	linuxRegistersLineRe = regexp.MustCompile(`^\s*(?:` + linuxRegister + `\s*)+$`)
+	linuxTaskRe          = regexp.MustCompile(`CPU: ([0-9]+) (?:UID: [0-9]+ )?PID: ([0-9]+) Comm: (.+?) ` +
+		`(Not tainted|Tainted: +[A-Z ]*[A-Z]) +([0-9][^ ]*)`)
+	linuxAccessRe     = regexp.MustCompile(`BUG: (KASAN): ([a-z-]+)`)
+	linuxAccessSizeRe = regexp.MustCompile(`(Read|Write) of size ([0-9]+) at addr ([0-9a-f]+)`)
+	linuxObjectRe     = regexp.MustCompile(`The buggy address belongs to the object at ([0-9a-f]+)`)
+	linuxCacheRe      = regexp.MustCompile(`which belongs to the cache ([^ ]+) of size ([0-9]+)`)
+	linuxLocationRe   = regexp.MustCompile(`The buggy address is located ([0-9]+) bytes ` +
+		`(inside of|to the right of|to the left of)`)
+	linuxStackStartRe = []struct {
+		re   *regexp.Regexp
+		kind StackKind
+	}{
+		{regexp.MustCompile(`^(?:Allocated|Allocation) by task ([0-9]+)`), StackAllocated},
+		{regexp.MustCompile(`^Freed by task ([0-9]+)`), StackFreed},
+		{regexp.MustCompile(`^(?:Last|Second to last) potentially related work creation:`), StackAux},
+		{regexp.MustCompile(`^-> #[0-9]+ \(`), StackLock},
+	}
+	linuxCallTraceRe   = regexp.MustCompile(`^(?:Call (?:T|t)race|Backtrace|stack backtrace):`)
+	linuxStackMarkerRe = regexp.MustCompile(`^\s*</?(?:TASK|IRQ|NMI|SOFTIRQ|EOI)>\s*$`)
+	linuxLocksHeldRe   = regexp.MustCompile(`^[0-9]+ locks? held by (.+):$`)
+	linuxHeldLockRe    = regexp.MustCompile(`^\s*#[0-9]+: (?:[0-9a-f]+ )?\((.+?)\)\{[^}]*\}(?:-\{[^}]*\})?, ` +
+		`at: (.*)$`)
+	linuxUnreliableFrame = regexp.MustCompile(`^\s*(?:\[<[0-9a-f]+>\] )?\? `)
+)
+
+// parseLinuxDetails extracts structured information from a (possibly symbolized) linux report.
+func parseLinuxDetails(report []byte) *Details {
+	details := &Details{}
+	var cur, crashStack *Stack
+	locksTask := ""
+	registersDone := false
+	for _, line := range bytes.Split(report, []byte{'\n'}) {
+		line = bytes.TrimRight(linuxLinePrefixRe.ReplaceAll(line, nil), " \r")
+		str := string(line)
+		trimmed := strings.TrimSpace(str)
+		// Only the first register dump is interesting, the rest are usually user-space registers.
+		registersDone = registersDone || len(details.Registers) != 0 &&
+			!linuxRegistersLineRe.MatchString(str) && !strings.HasPrefix(trimmed, "Code: ")
+		if trimmed == "" {
+			cur = nil
+			continue
+		}
+		if frame := parseLinuxFrame(line); frame != nil {
+			switch {
+			case cur != nil:
+				// Includes IP frames of interrupts/exceptions in the middle of a stack.
+				cur.Frames = append(cur.Frames, frame)
+				continue
+			case frame.IP:
+				// The faulting IP is the first frame of the crash stack (unless we are already
+				// past the crash stack, then it's user-space or another CPU).
+				if crashStack == nil {
+					crashStack = &Stack{Kind: StackCrash}
+					details.Stacks = append(details.Stacks, crashStack)
+				}
+				if len(crashStack.Frames) == 0 || crashStack.Frames[len(crashStack.Frames)-1].IP {
+					crashStack.Frames = append(crashStack.Frames, frame)
+				}
+				continue
+			}
+		}
+		if match := linuxHeldLockRe.FindStringSubmatch(str); match != nil {
+			details.HeldLocks = append(details.HeldLocks, &HeldLock{
+				Task:  locksTask,
+				Lock:  match[1],
+				Frame: parseLinuxFrame([]byte(" " + match[2])),
+			})
+			continue
+		}
+		if match := linuxLocksHeldRe.FindStringSubmatch(trimmed); match != nil {
+			locksTask = match[1]
+			continue
+		}
+		if linuxStackMarkerRe.MatchString(str) && cur != nil {
+			continue
+		}
+		if linuxCallTraceRe.MatchString(trimmed) {
+			switch {
+			case crashStack == nil:
+				crashStack = &Stack{Kind: StackCrash}
+				details.Stacks = append(details.Stacks, crashStack)
+				cur = crashStack
+			case cur == nil && onlyIPFrames(crashStack):
+				cur = crashStack
+			default:
+				cur = &Stack{Kind: StackOther}
+				details.Stacks = append(details.Stacks, cur)
+			}
+			continue
+		}
+		if stack := parseLinuxStackStart(trimmed); stack != nil {
+			cur = stack
+			details.Stacks = append(details.Stacks, cur)
+			continue
+		}
+		if linuxRegistersLineRe.MatchString(str) {
+			if !registersDone {
+				for _, match := range linuxRegisterRe.FindAllStringSubmatch(str, -1) {
+					details.Registers = append(details.Registers, Register{Name: match[1], Value: match[2]})
+				}
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "Code: ") {
+			// Register dumps and code may be printed in the middle of a stack.
+			continue
+		}
+		cur = nil
+		parseLinuxDetailsLine(details, trimmed)
+	}
+	if len(details.Stacks) == 0 && len(details.Registers) == 0 && details.Task == nil &&
+		details.Access == nil && len(details.HeldLocks) == 0 {
+		return nil
+	}
+	return details
+}
+
+func parseLinuxStackStart(line string) *Stack {
+	for _, start := range linuxStackStartRe {
+		match := start.re.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		stack := &Stack{
+			Kind:   start.kind,
+			Header: line,
+		}
+		if len(match) > 1 {
+			stack.PID, _ = strconv.Atoi(match[1])
+		}
+		return stack
+	}
+	return nil
+}
+
+func parseLinuxDetailsLine(details *Details, line string) {
+	if match := linuxTaskRe.FindStringSubmatch(line); match != nil && details.Task == nil {
+		details.Task = &TaskInfo{
+			Comm:   match[3],
+			Kernel: match[5],
+		}
+		details.Task.CPU, _ = strconv.Atoi(match[1])
+		details.Task.PID, _ = strconv.Atoi(match[2])
+		if match[4] != "Not tainted" {
+			details.Task.Tainted = strings.TrimSpace(strings.TrimPrefix(match[4], "Tainted:"))
+		}
+		return
+	}
+	if details.Task != nil && details.Task.Hardware == "" {
+		if hw, ok := strings.CutPrefix(line, "Hardware name: "); ok {
+			details.Task.Hardware = hw
+			return
+		}
+	}
+	if match := linuxAccessRe.FindStringSubmatch(line); match != nil && details.Access == nil {
+		details.Access = &AccessInfo{
+			Sanitizer: match[1],
+			Kind:      match[2],
+		}
+		return
+	}
+	access := details.Access
+	if access == nil {
+		return
+	}
+	if match := linuxAccessSizeRe.FindStringSubmatch(line); match != nil {
+		access.Write = match[1] == "Write"
+		access.Size, _ = strconv.Atoi(match[2])
+		access.Address, _ = strconv.ParseUint(match[3], 16, 64)
+	} else if match := linuxObjectRe.FindStringSubmatch(line); match != nil {
+		access.ObjectAddress, _ = strconv.ParseUint(match[1], 16, 64)
+	} else if match := linuxCacheRe.FindStringSubmatch(line); match != nil {
+		access.Cache = match[1]
+		access.ObjectSize, _ = strconv.Atoi(match[2])
+	} else if match := linuxLocationRe.FindStringSubmatch(line); match != nil {
+		offset, _ := strconv.Atoi(match[1])
+		switch match[2] {
+		case "inside of":
+			access.Inside = true
+		case "to the right of":
+			// The offset is counted from the end of the object.
+			offset += access.ObjectSize
+		case "to the left of":
+			offset = -offset
+		}
+		access.Offset = offset
+	}
+}
+
+func parseLinuxFrame(line []byte) *Frame {
+	if match := linuxInlineFrameRe.FindSubmatch(line); match != nil {
+		frame := &Frame{
+			Function: string(match[2]),
+			File:     string(match[3]),
+			Inline:   true,
+			IP:       len(match[1]) != 0,
+		}
+		frame.Line, _ = strconv.Atoi(string(match[4]))
+		return frame
+	}
+	parsed, ok := parseLinuxBacktraceLine(line)
+	if !ok || parsed.Size == 0 {
+		return nil
+	}
+	frame := &Frame{
+		Function:   parsed.Name,
+		Offset:     parsed.Offset,
+		Size:       parsed.Size,
+		Module:     parsed.ModName,
+		IP:         parsed.IsRipFrame,
+		Unreliable: linuxUnreliableFrame.Match(line),
+	}
+	if match := linuxFrameFileRe.FindSubmatch(line[parsed.indices[7]:]); match != nil {
+		frame.File = string(match[1])
+		frame.Line, _ = strconv.Atoi(string(match[2]))
+		if len(match[3]) != 0 {
+			frame.Module = string(match[3])
+		}
+	}
+	return frame
+}
+
+func onlyIPFrames(stack *Stack) bool {
+	for _, frame := range stack.Frames {
+		if !frame.IP {
+			return false
+		}
+	}
+	return true
+}
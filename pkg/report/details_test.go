@@ -0,0 +1,208 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package report
+
+import (
+	"os"
+	"testing"
+
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinuxDetailsKASAN(t *testing.T) {
+	reporter, _ := prepareLinuxReporter(t, targets.AMD64)
+	output, err := os.ReadFile("testdata/linux/report/416")
+	require.NoError(t, err)
+	rep := reporter.Parse(output)
+	require.NotNil(t, rep)
+	details := rep.Details
+	require.NotNil(t, details)
+
+	assert.Equal(t, &TaskInfo{
+		CPU:      1,
+		PID:      23697,
+		Comm:     "v4l_id",
+		Kernel:   "5.3.0-rc7+",
+		Hardware: "Google Google Compute Engine/Google Compute Engine, BIOS Google 01/01/2011",
+	}, details.Task)
+	assert.Equal(t, &AccessInfo{
+		Sanitizer:     "KASAN",
+		Kind:          "use-after-free",
+		Write:         true,
+		Address:       0xffff8881c84f8b14,
+		Size:          1,
+		ObjectAddress: 0xffff8881c84f8000,
+		ObjectSize:    8192,
+		Cache:         "kmalloc-8k",
+		Offset:        2836,
+		Inside:        true,
+	}, details.Access)
+
+	require.Len(t, details.Stacks, 3)
+	crash, alloc, free := details.Stacks[0], details.Stacks[1], details.Stacks[2]
+	assert.Equal(t, StackCrash, crash.Kind)
+	// Questionable frames are dropped from reports with the printk context.
+	require.Len(t, crash.Frames, 17)
+	assert.Equal(t, &Frame{Function: "dump_stack", Offset: 0xca, Size: 0x13e}, crash.Frames[0])
+	assert.Equal(t, &Frame{Function: "kobject_del", Offset: 0x12e, Size: 0x170}, crash.Frames[4])
+	assert.Equal(t, "entry_SYSCALL_64_after_hwframe", crash.Frames[16].Function)
+
+	assert.Equal(t, StackAllocated, alloc.Kind)
+	assert.Equal(t, "Allocated by task 2775:", alloc.Header)
+	assert.Equal(t, 2775, alloc.PID)
+	require.Len(t, alloc.Frames, 27)
+	assert.Equal(t, "usbvision_probe.cold", alloc.Frames[2].Function)
+
+	assert.Equal(t, StackFreed, free.Kind)
+	assert.Equal(t, 12, free.PID)
+	require.Len(t, free.Frames, 16)
+	assert.Equal(t, "kfree", free.Frames[2].Function)
+
+	// These are user-space registers.
+	require.Len(t, details.Registers, 19)
+	assert.Equal(t, Register{Name: "RIP", Value: "0033:0x7f88374e32b0"}, details.Registers[0])
+	assert.Equal(t, Register{Name: "RSP", Value: "002b:00007ffce3f3e218"}, details.Registers[1])
+	assert.Equal(t, Register{Name: "R15", Value: "0000000000000000"}, details.Registers[18])
+}
+
+func TestLinuxDetailsOops(t *testing.T) {
+	report := `general protection fault, probably for non-canonical address 0xdffffc0000000000: 0000 [#1] PREEMPT SMP KASAN
+CPU: 0 UID: 0 PID: 5823 Comm: syz.0.17 Tainted: G        W          6.12.0-syzkaller #0
+Hardware name: Google Google Compute Engine/Google Compute Engine, BIOS Google 09/13/2024
+RIP: 0010:foo_inline drivers/foo/foo.c:10 [inline]
+RIP: 0010:foo_ioctl+0x1b/0x80 drivers/foo/foo.c:20 [foo]
+Code: 48 89 fb e8 4d 2c 5f fc 48 89 d8 48 c1 e8 03 <80> 3c 28 00 74 08 48 89 df e8 47 9c c4 fc 48 8b 1b
+RSP: 0018:ffffc90003b7fb40 EFLAGS: 00010246
+RAX: 0000000000000000 RBX: 0000000000000000 RCX: ffff88802a0c1e00
+FS:  00007f5e1ec4e6c0(0000) GS:ffff8880b8600000(0000) knlGS:0000000000000000
+CS:  0010 DS: 0000 ES: 0000 CR0: 0000000080050033
+Call Trace:
+ <TASK>
+ ? __se_sys_ioctl+0x10/0x170
+ vfs_ioctl fs/ioctl.c:51 [inline]
+ __do_sys_ioctl fs/ioctl.c:907 [inline]
+ __se_sys_ioctl+0xf9/0x170 fs/ioctl.c:893
+ do_syscall_64+0xf3/0x230 arch/x86/entry/common.c:83
+ entry_SYSCALL_64_after_hwframe+0x77/0x7f
+RIP: 0033:0x7f5e1df7e819
+RSP: 002b:00007f5e1ec4e038 EFLAGS: 00000246 ORIG_RAX: 0000000000000010
+ </TASK>
+`
+	details := parseLinuxDetails([]byte(report))
+	require.NotNil(t, details)
+	assert.Equal(t, &TaskInfo{
+		CPU:      0,
+		PID:      5823,
+		Comm:     "syz.0.17",
+		Tainted:  "G        W",
+		Kernel:   "6.12.0-syzkaller",
+		Hardware: "Google Google Compute Engine/Google Compute Engine, BIOS Google 09/13/2024",
+	}, details.Task)
+	assert.Nil(t, details.Access)
+	assert.Equal(t, []*Stack{
+		{
+			Kind: StackCrash,
+			Frames: []*Frame{
+				{Function: "foo_inline", File: "drivers/foo/foo.c", Line: 10, Inline: true, IP: true},
+				{Function: "foo_ioctl", Offset: 0x1b, Size: 0x80, Module: "foo",
+					File: "drivers/foo/foo.c", Line: 20, IP: true},
+				{Function: "__se_sys_ioctl", Offset: 0x10, Size: 0x170, Unreliable: true},
+				{Function: "vfs_ioctl", File: "fs/ioctl.c", Line: 51, Inline: true},
+				{Function: "__do_sys_ioctl", File: "fs/ioctl.c", Line: 907, Inline: true},
+				{Function: "__se_sys_ioctl", Offset: 0xf9, Size: 0x170, File: "fs/ioctl.c", Line: 893},
+				{Function: "do_syscall_64", Offset: 0xf3, Size: 0x230, File: "arch/x86/entry/common.c", Line: 83},
+				{Function: "entry_SYSCALL_64_after_hwframe", Offset: 0x77, Size: 0x7f},
+			},
+		},
+	}, details.Stacks)
+	assert.Equal(t, []Register{
+		{"RSP", "0018:ffffc90003b7fb40"},
+		{"EFLAGS", "00010246"},
+		{"RAX", "0000000000000000"},
+		{"RBX", "0000000000000000"},
+		{"RCX", "ffff88802a0c1e00"},
+		{"FS", "00007f5e1ec4e6c0"},
+		{"GS", "ffff8880b8600000"},
+		{"knlGS", "0000000000000000"},
+		{"CS", "0010"},
+		{"DS", "0000"},
+		{"ES", "0000"},
+		{"CR0", "0000000080050033"},
+	}, details.Registers)
+}
+
+func TestLinuxDetailsLockdep(t *testing.T) {
+	report := `WARNING: possible circular locking dependency detected
+6.15.0-rc7 #2 Not tainted
+------------------------------------------------------
+syz.5.7376/24950 is trying to acquire lock:
+ffff888106a71958 (&q->elevator_lock){+.+.}-{4:4}, at: elevator_change+0x49a/0x1a10 block/elevator.c:100
+
+the existing dependency chain (in reverse order) is:
+
+-> #1 (fs_reclaim){+.+.}-{0:0}:
+       lock_acquire+0x120/0x360
+       fs_reclaim_acquire+0x72/0x100
+
+-> #0 (&q->elevator_lock){+.+.}-{4:4}:
+       __mutex_lock+0x182/0xe80
+       elevator_change+0x49a/0x1a10 block/elevator.c:100
+
+other info that might help us debug this:
+
+2 locks held by syz.5.7376/24950:
+ #0: ffffffff8fa53210 (cb_lock){++++}-{4:4}, at: genl_rcv+0x19/0x40 net/netlink/genetlink.c:1218
+ #1: ffffffff8fa530c8 (genl_mutex){+.+.}-{4:4}, at: genl_lock net/netlink/genetlink.c:35 [inline]
+
+stack backtrace:
+CPU: 1 UID: 0 PID: 24950 Comm: syz.5.7376 Not tainted 6.15.0-rc7 #2 PREEMPT(full)
+Call Trace:
+ <TASK>
+ dump_stack_lvl+0x189/0x250
+ </TASK>
+`
+	details := parseLinuxDetails([]byte(report))
+	require.NotNil(t, details)
+	assert.Equal(t, []*Stack{
+		{
+			Kind:   StackLock,
+			Header: "-> #1 (fs_reclaim){+.+.}-{0:0}:",
+			Frames: []*Frame{
+				{Function: "lock_acquire", Offset: 0x120, Size: 0x360},
+				{Function: "fs_reclaim_acquire", Offset: 0x72, Size: 0x100},
+			},
+		},
+		{
+			Kind:   StackLock,
+			Header: "-> #0 (&q->elevator_lock){+.+.}-{4:4}:",
+			Frames: []*Frame{
+				{Function: "__mutex_lock", Offset: 0x182, Size: 0xe80},
+				{Function: "elevator_change", Offset: 0x49a, Size: 0x1a10, File: "block/elevator.c", Line: 100},
+			},
+		},
+		{
+			Kind: StackCrash,
+			Frames: []*Frame{
+				{Function: "dump_stack_lvl", Offset: 0x189, Size: 0x250},
+			},
+		},
+	}, details.Stacks)
+	assert.Equal(t, []*HeldLock{
+		{
+			Task: "syz.5.7376/24950",
+			Lock: "cb_lock",
+			Frame: &Frame{Function: "genl_rcv", Offset: 0x19, Size: 0x40,
+				File: "net/netlink/genetlink.c", Line: 1218},
+		},
+		{
+			Task:  "syz.5.7376/24950",
+			Lock:  "genl_mutex",
+			Frame: &Frame{Function: "genl_lock", File: "net/netlink/genetlink.c", Line: 35, Inline: true},
+		},
+	}, details.HeldLocks)
+	assert.Equal(t, 24950, details.Task.PID)
+	assert.Empty(t, details.Task.Tainted)
+}
@@ -133,7 +133,7 @@ func (ctx *fuchsia) shortenReport(report []byte) []byte {
 }
 
 func (ctx *fuchsia) symbolize(output []byte) []byte {
-	symb := symbolizer.Make(ctx.config.target)
+	symb := symbolizer.Make(ctx.config.target, ctx.config.symbolizer)
 	defer symb.Close()
 	out := new(bytes.Buffer)
 
@@ -39,6 +39,9 @@ var impactOrder = []crash.Type{
 	crash.LockdepBug,  // indicates potential deadlocks and hangs
 	// Lower-Medium Priority (Denial of Service and General Bugs)
 	crash.MemoryLeak, // a form of DoS
+	crash.RustOutOfBounds,
+	crash.RustArithmetic,
+	crash.RustPanic,
 	crash.DoS,
 	crash.Hang,
 	// Unknown types shouldn't be mentioned here. If bug goes to Unknown it means we need better parsing/processing.
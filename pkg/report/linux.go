@@ -125,6 +125,8 @@ func ctorLinux(cfg *config) (reporterImpl, []string, error) {
 		// But of course it can come from another CPU as well.
 		compile(`PANIC: double fault`),
 		compile(`Internal error:`),
+		// Rust panic handler calls BUG() after printing the panic message.
+		compile(`kernel BUG at rust/helpers(?:/bug)?\.c`),
 	}
 	// These pattern math kernel reports which are not bugs in itself but contain stack traces.
 	// If we see them in the middle of another report, we know that the report is potentially corrupted.
@@ -191,6 +193,7 @@ func (ctx *linux) Parse(output []byte) *Report {
 		rep.reportPrefixLen = len(rep.Report)
 		rep.Report = append(rep.Report, report...)
 		rep.Type = TitleToCrashType(rep.Title)
+		rep.Details = parseLinuxDetails(rep.Report)
 		setExecutorInfo(rep)
 		if !rep.Corrupted {
 			rep.Corrupted, rep.CorruptedReason = isCorrupted(title, report, format)
@@ -385,7 +388,7 @@ func (ctx *linux) extractContext(line []byte) string {
 func (ctx *linux) Symbolize(rep *Report) error {
 	var symbFunc symbFuncCb
 	if ctx.vmlinux != "" {
-		symb := symbolizer.Make(ctx.config.target)
+		symb := symbolizer.Make(ctx.config.target, ctx.config.symbolizer)
 		defer symb.Close()
 		symbFunc = func(bin string, pc uint64) ([]symbolizer.Frame, error) {
 			return ctx.symbolizerCache.Symbolize(symb.Symbolize, bin, pc)
@@ -394,6 +397,7 @@ func (ctx *linux) Symbolize(rep *Report) error {
 	if err := ctx.symbolize(rep, symbFunc); err != nil {
 		return err
 	}
+	rep.Details = parseLinuxDetails(rep.Report)
 	rep.Report = ctx.decompileOpcodes(rep.Report, rep)
 
 	// Skip getting maintainers for Android fuzzing since the kernel source
@@ -1127,7 +1131,7 @@ var linuxStallAnchorFrames = []*regexp.Regexp{
 
 // nolint: lll
 var (
-	linuxSymbolizeRe     = regexp.MustCompile(`(?:\[\<(?:(?:0x)?[0-9a-f]+)\>\])?[ \t]+\(?(?:[0-9]+:)?([a-zA-Z0-9_.]+)\+0x([0-9a-f]+)/0x([0-9a-f]+)( ?\[([a-zA-Z0-9_.]+)( .*)?\])?\)?`)
+	linuxSymbolizeRe     = regexp.MustCompile(`(?:\[\<(?:(?:0x)?[0-9a-f]+)\>\])?[ \t]+\(?(?:[0-9]+:)?([a-zA-Z0-9_.$]+)\+0x([0-9a-f]+)/0x([0-9a-f]+)( ?\[([a-zA-Z0-9_.]+)( .*)?\])?\)?`)
 	linuxRipFrame        = compile(`(?:IP|NIP|pc |PC is at):? (?:(?:[0-9]+:)?(?:{{PC}} +){0,2}{{FUNC}}|(?:[0-9]+:)?0x[0-9a-f]+|(?:[0-9]+:)?{{PC}} +\[< *\(null\)>\] +\(null\)|[0-9]+: +\(null\))`)
 	linuxCallTrace       = compile(`(?:Call (?:T|t)race:)|(?:Backtrace:)`)
 	linuxCodeRe          = regexp.MustCompile(`(?m)^\s*Code\:\s+((?:[A-Fa-f0-9\(\)\<\>]{2,8}\s*)*)\s*$`)
@@ -1135,6 +1139,25 @@ var (
 	linuxUserSegmentRe   = regexp.MustCompile(`^RIP:\s+0033:`)
 )
 
+// rustPanicMessage matches the beginning of a Rust panic message.
+// Since Rust 1.73 it's printed on the line after the panic location.
+const rustPanicMessage = "rust_kernel: panicked at (?:'|[^\\n]*\\n)"
+
+var rustPanicStack = &stackFmt{
+	parts: []*regexp.Regexp{
+		linuxCallTrace,
+		parseStackTrace,
+	},
+	skip: []string{
+		// Frames are demangled before matching, core::panicking frames are skipped as "panic".
+		"rust_begin_unwind",
+		"rust_helper_BUG",
+		"^core::option::(?:unwrap|expect)_failed",
+		"^core::result::unwrap_failed",
+		"^core::slice::index::",
+	},
+}
+
 var linuxCorruptedTitles = []*regexp.Regexp{
 	// Sometimes timestamps get merged into the middle of report description.
 	regexp.MustCompile(`\[ *[0-9]+\.[0-9]+\]`),
@@ -2275,19 +2298,35 @@ var linuxOopses = append([]*oops{
 	{
 		[]byte("rust_kernel: panicked"),
 		[]oopsFormat{
+			{
+				// Index and slice messages contain the index/length, which are not useful in titles.
+				title: compile(rustPanicMessage + "index out of bounds"),
+				fmt:   "index out of bounds in %[1]v",
+				stack: rustPanicStack,
+			},
+			{
+				title: compile(rustPanicMessage + "(?:range (?:start|end) index|slice index starts at)"),
+				fmt:   "slice index out of range in %[1]v",
+				stack: rustPanicStack,
+			},
+			{
+				// Result::unwrap also prints the error value.
+				title: compile(rustPanicMessage + "(called `(?:Option|Result)::unwrap\\(\\)` on an? `(?:None|Err)` value)"),
+				fmt:   "%[1]v in %[2]v",
+				stack: rustPanicStack,
+			},
+			{
+				// Before Rust 1.73 the message was printed on the same line: panicked at 'msg', file:line:col
+				title:  compile("rust_kernel: panicked at '"),
+				report: compile("rust_kernel: panicked at '(.+?)', "),
+				fmt:    "%[1]v in %[2]v",
+				stack:  rustPanicStack,
+			},
 			{
 				title:  compile("rust_kernel: panicked"),
 				report: compile("rust_kernel: panicked at [^\n]*?\n(.+?)\n"),
 				fmt:    "%[1]v in %[2]v",
-				stack: &stackFmt{
-					parts: []*regexp.Regexp{
-						linuxCallTrace,
-						parseStackTrace,
-					},
-					skip: []string{
-						regexp.QuoteMeta(`__rustc::rust_begin_unwind`),
-					},
-				},
+				stack:  rustPanicStack,
 			},
 		},
 		[]*regexp.Regexp{},
@@ -14,6 +14,7 @@ import (
 	"github.com/google/syzkaller/pkg/cover/backend"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/pkg/symbolizer"
 	"github.com/google/syzkaller/pkg/vcs"
 	"github.com/google/syzkaller/pkg/vminfo"
 	"github.com/google/syzkaller/sys/targets"
@@ -72,6 +73,9 @@ type Report struct {
 	MachineInfo []byte
 	// If the crash happened in the context of the syz-executor process, Executor will hold more info.
 	Executor *ExecutorInfo
+	// Structured representation of the report (stacks, registers, etc), may be nil.
+	// Currently only filled in for Linux, frames get file:line info after Symbolize.
+	Details *Details
 	// reportPrefixLen is length of additional prefix lines that we added before actual crash report.
 	reportPrefixLen int
 	// symbolized is set if the report is symbolized. It prevents double symbolization.
@@ -120,6 +124,7 @@ func NewReporter(cfg *mgrconfig.Config) (*Reporter, error) {
 		kernelDirs:    *cfg.KernelDirs(),
 		ignores:       ignores,
 		kernelModules: localModules,
+		symbolizer:    cfg.SymbolizerConfig(),
 	}
 	rep, suppressions, err := ctor(config)
 	if err != nil {
@@ -170,6 +175,7 @@ type config struct {
 	kernelDirs    mgrconfig.KernelDirs
 	ignores       []*regexp.Regexp
 	kernelModules []*vminfo.KernelModule
+	symbolizer    *symbolizer.Config
 }
 
 type fn func(cfg *config) (reporterImpl, []string, error)
@@ -440,7 +446,8 @@ var parseStackTrace *regexp.Regexp
 func compile(re string) *regexp.Regexp {
 	re = strings.ReplaceAll(re, "{{ADDR}}", "0x[0-9a-f]+")
 	re = strings.ReplaceAll(re, "{{PC}}", "\\[\\<?(?:0x)?[0-9a-f]+\\>?\\]")
-	re = strings.ReplaceAll(re, "{{FUNC}}", "([a-zA-Z0-9_]+)(?:\\.|\\+)")
+	// Legacy Rust mangling uses '$' and '.' in symbol names: _ZN4core3fmt5write17h0123456789abcdefE.
+	re = strings.ReplaceAll(re, "{{FUNC}}", "(_ZN[a-zA-Z0-9_.$]+E|[a-zA-Z0-9_]+)(?:\\.|\\+)")
 	re = strings.ReplaceAll(re, "{{SRC}}", "([a-zA-Z0-9-_/.]+\\.[a-z]+:[0-9]+)")
 	return regexp.MustCompile(re)
 }
@@ -859,7 +866,7 @@ func Truncate(log []byte, begin, end int) []byte {
 
 var (
 	filenameRe    = regexp.MustCompile(`([a-zA-Z0-9_\-\./]*[a-zA-Z0-9_\-]+\.(c|h)):[0-9]+`)
-	reportFrameRe = regexp.MustCompile(`.* in ((?:<[a-zA-Z0-9_: ]+>)?[a-zA-Z0-9_:]+)`)
+	reportFrameRe = regexp.MustCompile(`.* in ((?:[a-zA-Z0-9_:]*<[a-zA-Z0-9_: ]+>)?[a-zA-Z0-9_:]+)`)
 	// Matches a slash followed by at least one directory nesting before .c/.h file.
 	deeperPathRe = regexp.MustCompile(`^/[a-zA-Z0-9_\-\./]+/[a-zA-Z0-9_\-]+\.(c|h)$`)
 )
@@ -226,6 +226,44 @@ var titleToType = []struct {
 		includePrefixes: []string{"WARNING in"},
 		crashType:       crash.Warning,
 	},
+	{
+		includePrefixes: []string{
+			// keep-sorted start
+			"attempt to add with overflow",
+			"attempt to calculate the remainder with",
+			"attempt to divide by zero",
+			"attempt to divide with overflow",
+			"attempt to multiply with overflow",
+			"attempt to negate with overflow",
+			"attempt to shift left with overflow",
+			"attempt to shift right with overflow",
+			"attempt to subtract with overflow",
+			// keep-sorted end
+		},
+		crashType: crash.RustArithmetic,
+	},
+	{
+		includePrefixes: []string{
+			"index out of bounds in",
+			"slice index out of range in",
+		},
+		crashType: crash.RustOutOfBounds,
+	},
+	{
+		includePrefixes: []string{
+			// keep-sorted start
+			"assertion `left",
+			"assertion failed: ",
+			"called `Option::unwrap()` on a `None` value",
+			"called `Result::unwrap()` on an `Err` value",
+			"explicit panic",
+			"internal error: entered unreachable code",
+			"not implemented",
+			"not yet implemented",
+			// keep-sorted end
+		},
+		crashType: crash.RustPanic,
+	},
 	{
 		includePrefixes: []string{
 			// keep-sorted start
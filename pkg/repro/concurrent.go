@@ -0,0 +1,149 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/prog"
+)
+
+// Races between programs executed by different procs (e.g. KCSAN reports or use-after-frees
+// caused by racing syscalls) can't be reproduced by a single program or by a concatenation
+// of programs, so we also try pairs of programs executed concurrently (see csource.Options.Concurrent).
+
+const (
+	// Number of the last programs of each proc considered for pairs.
+	concurrentProgsPerProc = 2
+	// Max number of pairs to test.
+	concurrentMaxPairs = 8
+)
+
+// concurrentCrashTypes are the crash types that are likely caused by races.
+var concurrentCrashTypes = map[crash.Type]bool{
+	crash.KCSANAssert:             true,
+	crash.KCSANDataRace:           true,
+	crash.KCSANUnknown:            true,
+	crash.KASANInvalidFree:        true,
+	crash.KASANUseAfterFreeRead:   true,
+	crash.KASANUseAfterFreeWrite:  true,
+	crash.KFENCEInvalidFree:       true,
+	crash.KFENCEUseAfterFreeRead:  true,
+	crash.KFENCEUseAfterFreeWrite: true,
+}
+
+func (ctx *reproContext) extractProgConcurrent(entries []*prog.LogEntry) (*Result, error) {
+	// The pair is executed in a loop, so a race that's reproducible at all should trigger
+	// well before the no output timeout used for hangs.
+	duration := max(100*time.Second, 20*ctx.timeouts.Program)
+	pairs := concurrentPairs(entries, ctx.crashExecutor)
+	ctx.reproLogf(3, "concurrent: executing %d pairs of programs with timeout %s", len(pairs), duration)
+	for _, pair := range pairs {
+		if len(pair[0].P.Calls)+len(pair[1].P.Calls) > prog.MaxCalls {
+			continue
+		}
+		p := prog.Concat(pair[0].P, pair[1].P)
+		opts := concurrentOpts(ctx.startOpts, len(pair[0].P.Calls))
+		ret, err := ctx.testProg(p, duration, opts, false)
+		if err != nil {
+			return nil, err
+		}
+		if ret.Crashed {
+			ctx.reproLogf(3, "concurrent: successfully extracted reproducer")
+			return &Result{
+				Prog:     p,
+				Duration: max(duration, ret.Duration*3/2),
+				Opts:     opts,
+			}, nil
+		}
+	}
+	ctx.reproLogf(3, "concurrent: failed to extract reproducer")
+	return nil, nil
+}
+
+func concurrentOpts(opts csource.Options, split int) csource.Options {
+	opts.Threaded = true
+	opts.Collide = false
+	opts.Concurrent = split
+	return opts
+}
+
+// concurrentPairs returns pairs of programs executed by different procs shortly before the crash.
+// Pairs with the program mentioned in the crash report go first, then pairs of more recent programs.
+func concurrentPairs(entries []*prog.LogEntry, executor *report.ExecutorInfo) [][2]*prog.LogEntry {
+	isCrashed := func(ent *prog.LogEntry) bool {
+		return executor != nil && ent.ID == executor.ExecID
+	}
+	// Candidates are ordered from the most recent one.
+	var candidates []*prog.LogEntry
+	perProc := make(map[int]int)
+	for i := len(entries) - 1; i >= 0; i-- {
+		ent := entries[i]
+		if perProc[ent.Proc] >= concurrentProgsPerProc && !isCrashed(ent) {
+			continue
+		}
+		perProc[ent.Proc]++
+		candidates = append(candidates, ent)
+	}
+	type pair struct {
+		entries [2]*prog.LogEntry
+		score   int
+	}
+	var pairs []pair
+	for i := 0; i < len(candidates); i++ {
+		for j := i + 1; j < len(candidates); j++ {
+			if candidates[i].Proc == candidates[j].Proc {
+				continue
+			}
+			score := i + j
+			if isCrashed(candidates[i]) || isCrashed(candidates[j]) {
+				score -= 2 * len(candidates)
+			}
+			// Keep the log order inside the pair.
+			pairs = append(pairs, pair{[2]*prog.LogEntry{candidates[j], candidates[i]}, score})
+		}
+	}
+	sort.SliceStable(pairs, func(i, j int) bool {
+		return pairs[i].score < pairs[j].score
+	})
+	var res [][2]*prog.LogEntry
+	for i := 0; i < len(pairs) && i < concurrentMaxPairs; i++ {
+		res = append(res, pairs[i].entries)
+	}
+	return res
+}
+
+// minimizeConcurrent minimizes both parts of a concurrent reproducer separately,
+// so that the split point between the parts is preserved.
+func (ctx *reproContext) minimizeConcurrent(res *Result, mode prog.MinimizeMode) {
+	var parts [2]*prog.Prog
+	parts[0], parts[1] = res.Prog.Split(res.Opts.Concurrent)
+	for i := range parts {
+		var testErr error
+		parts[i], _ = prog.Minimize(parts[i], -1, mode, func(p1 *prog.Prog, _ int) bool {
+			if testErr != nil || len(p1.Calls) == 0 {
+				return false
+			}
+			candidate := parts
+			candidate[i] = p1
+			ret, err := ctx.testProg(prog.Concat(candidate[0], candidate[1]), res.Duration,
+				concurrentOpts(res.Opts, len(candidate[0].Calls)), false)
+			if err != nil {
+				ctx.reproLogf(2, "minimization failed with %v", err)
+				testErr = err
+				return false
+			}
+			return ret.Crashed
+		})
+		if testErr != nil {
+			break
+		}
+	}
+	res.Prog = prog.Concat(parts[0], parts[1])
+	res.Opts.Concurrent = len(parts[0].Calls)
+}
@@ -0,0 +1,91 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/syzkaller/pkg/csource"
+)
+
+// MinimizeFeatures is an optional step after Run that disables the executor features
+// (net injection, devlink, USB emulation, etc.) that are not needed to trigger the bug.
+// Run already does this for C reproducers as part of the C options simplification,
+// but syz reproducers keep all features that were enabled during fuzzing.
+func MinimizeFeatures(ctx context.Context, res *Result, env Environment) (*Result, *Stats, error) {
+	return minimizeFeatures(ctx, res, env, &poolWrapper{
+		cfg:      env.Config,
+		reporter: env.Reporter,
+		pool:     env.Pool,
+	})
+}
+
+func minimizeFeatures(ctx context.Context, res *Result, env Environment, exec execInterface) (
+	*Result, *Stats, error) {
+	cfg := env.Config
+	reproCtx := &reproContext{
+		ctx:            ctx,
+		exec:           exec,
+		target:         cfg.SysTarget,
+		crashTitle:     res.Report.Title,
+		crashType:      res.Report.Type,
+		crashReport:    res.Report,
+		verify:         verifyLevels[cfg.ReproVerify],
+		stats:          new(Stats),
+		timeouts:       cfg.Timeouts,
+		observedTitles: map[string]bool{res.Report.Title: true},
+		logf:           env.logf,
+	}
+	start := time.Now()
+	ret := *res
+	// The minimized features must also be disabled in the further syz-execprog runs.
+	ret.Opts.ExplicitFeatures = true
+	for _, name := range res.Opts.EnabledFeatures() {
+		if !res.CRepro && !execprogFeatures()[name] {
+			// Disabling the feature would not change anything in syz-execprog runs.
+			continue
+		}
+		if name == "close_fds" && ret.Opts.Repeat {
+			// See the corresponding C simplification.
+			continue
+		}
+		opts := ret.Opts
+		opts.DisableFeature(name)
+		if opts.Check(cfg.TargetOS) != nil {
+			continue
+		}
+		reproCtx.reproLogf(2, "checking whether the %v feature is needed", name)
+		var crashed verdict
+		var err error
+		if res.CRepro {
+			crashed, err = reproCtx.testCProg(ret.Prog, ret.Duration, opts, true)
+		} else {
+			crashed, err = reproCtx.testProg(ret.Prog, ret.Duration, opts, true)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if crashed.Crashed {
+			reproCtx.reproLogf(2, "the %v feature is not needed", name)
+			ret.Opts = opts
+		}
+	}
+	reproCtx.reproLogf(1, "needed features: %v", ret.Opts.EnabledFeatures())
+	reproCtx.stats.TotalTime = time.Since(start)
+	return &ret, reproCtx.stats, nil
+}
+
+// execprogFeatures returns the features that can be disabled in syz-execprog.
+func execprogFeatures() map[string]bool {
+	ret := map[string]bool{
+		"net_reset": true,
+		"cgroups":   true,
+		"close_fds": true,
+	}
+	for _, name := range csource.FlatRPCFeaturesToCSource {
+		ret[name] = true
+	}
+	return ret
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"fmt"
+
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm"
+)
+
+type FtraceResult struct {
+	Report *report.Report
+	Trace  []byte
+	Error  error
+}
+
+// RunFtrace runs the reproducer with the kernel tracing configured in cfg.Ftrace
+// and returns the collected trace buffer.
+func RunFtrace(result *Result, cfg *mgrconfig.Config, reporter *report.Reporter, pool *vm.Dispatcher) *FtraceResult {
+	if cfg.Ftrace == nil {
+		return &FtraceResult{Error: fmt.Errorf("ftrace is not configured")}
+	}
+	runRes, err := runInstrumented(result, cfg, reporter, pool, "ftrace", &instance.OptionalConfig{
+		Ftrace: cfg.Ftrace,
+	})
+	if err != nil {
+		return &FtraceResult{Error: err}
+	}
+	res := &FtraceResult{
+		Report: runRes.Report,
+		Trace:  runRes.Trace,
+		Error:  runRes.TraceError,
+	}
+	if res.Error == nil && len(res.Trace) == 0 {
+		res.Error = fmt.Errorf("no trace collected")
+	}
+	return res
+}
+
+func (ftrace *FtraceResult) IsSameBug(repro *Result) bool {
+	if ftrace == nil || ftrace.Report == nil || repro.Report == nil {
+		return false
+	}
+	return ftrace.Report.Title == repro.Report.Title
+}
@@ -0,0 +1,97 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/google/syzkaller/pkg/debugtracer"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/kconfig"
+)
+
+// ConfigMinimization describes how the candidate kernel configs are built and tested.
+type ConfigMinimization struct {
+	// Env builds kernels with the candidate configs and tests the reproducer on them.
+	Env instance.Env
+	// Kernel build parameters, KernelConfig is replaced with the candidate configs.
+	Build   instance.BuildKernelConfig
+	KConfig *kconfig.KConfig
+	// The config of the kernel the bug was reproduced on.
+	Config []byte
+	// Only the configs that are absent in the baseline config are minimized.
+	Baseline []byte
+	// The number of VMs that test each candidate config.
+	TestVMs int
+	// The max number of tested candidate configs (0 means no limit).
+	MaxSteps int
+	Tracer   debugtracer.DebugTracer
+}
+
+var ErrConfigNotReproducing = errors.New("the reproducer does not trigger the bug with the original config")
+
+// MinimizeConfig is an optional step after Run that finds the smallest kernel config
+// (between the baseline and the original configs) on which the reproducer still triggers the bug.
+// Each candidate config is built and tested, so this is a very slow process.
+func MinimizeConfig(res *Result, params *ConfigMinimization) ([]byte, error) {
+	dt := params.Tracer
+	if dt == nil {
+		dt = &debugtracer.NullTracer{}
+	}
+	full, err := kconfig.ParseConfigData(params.Config, "original")
+	if err != nil {
+		return nil, err
+	}
+	base, err := kconfig.ParseConfigData(params.Baseline, "baseline")
+	if err != nil {
+		return nil, err
+	}
+	reproSyz := res.Prog.Serialize()
+	reproOpts := res.Opts.Serialize()
+	var reproC []byte
+	if res.CRepro {
+		if reproC, err = res.CProgram(); err != nil {
+			return nil, fmt.Errorf("failed to generate C repro: %w", err)
+		}
+	}
+	step := 0
+	pred := func(candidate *kconfig.ConfigFile) (bool, error) {
+		step++
+		buildCfg := params.Build
+		buildCfg.KernelConfig = candidate.Serialize()
+		if _, _, err := params.Env.BuildKernel(&buildCfg); err != nil {
+			// Some configs may be broken, they do not reproduce the bug.
+			dt.Log("step %v: kernel build failed: %v", step, err)
+			return false, nil
+		}
+		results, err := params.Env.Test(params.TestVMs, reproSyz, reproOpts, reproC)
+		if err != nil {
+			return false, err
+		}
+		for _, testRes := range results {
+			var crashErr *instance.CrashError
+			if errors.As(testRes.Error, &crashErr) {
+				dt.Log("step %v: crashed with %q", step, crashErr.Report.Title)
+				if crashErr.Report.Title == res.Report.Title {
+					return true, nil
+				}
+			} else if testRes.Error != nil {
+				dt.Log("step %v: %v", step, testRes.Error)
+			}
+		}
+		dt.Log("step %v: the bug is not reproduced", step)
+		return false, nil
+	}
+	if ok, err := pred(full); err != nil {
+		return nil, err
+	} else if !ok {
+		return nil, ErrConfigNotReproducing
+	}
+	minConfig, err := params.KConfig.Minimize(base, full, pred, params.MaxSteps, dt)
+	if err != nil {
+		return nil, err
+	}
+	return minConfig.Serialize(), nil
+}
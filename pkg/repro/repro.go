@@ -57,6 +57,8 @@ type reproContext struct {
 	crashType      crash.Type
 	crashStart     int
 	crashExecutor  *report.ExecutorInfo
+	crashReport    *report.Report
+	verify         int
 	entries        []*prog.LogEntry
 	testTimeouts   []time.Duration
 	startOpts      csource.Options
@@ -104,11 +106,12 @@ func runInner(ctx context.Context, crashLog []byte, env Environment, exec execIn
 	crashStart := len(crashLog)
 	crashTitle, crashType := "", crash.UnknownType
 	var crashExecutor *report.ExecutorInfo
-	if rep := env.Reporter.Parse(crashLog); rep != nil {
-		crashStart = rep.StartPos
-		crashTitle = rep.Title
-		crashType = rep.Type
-		crashExecutor = rep.Executor
+	crashReport := env.Reporter.Parse(crashLog)
+	if crashReport != nil {
+		crashStart = crashReport.StartPos
+		crashTitle = crashReport.Title
+		crashType = crashReport.Type
+		crashExecutor = crashReport.Executor
 	}
 	testTimeouts := []time.Duration{
 		max(30*time.Second, 3*cfg.Timeouts.Program), // to catch simpler crashes (i.e. no races and no hangs)
@@ -140,6 +143,8 @@ func runInner(ctx context.Context, crashLog []byte, env Environment, exec execIn
 		crashType:     crashType,
 		crashStart:    crashStart,
 		crashExecutor: crashExecutor,
+		crashReport:   crashReport,
+		verify:        verifyLevels[cfg.ReproVerify],
 
 		entries:        entries,
 		testTimeouts:   testTimeouts,
@@ -367,6 +372,19 @@ func (ctx *reproContext) extractProg(entries []*prog.LogEntry) (*Result, error)
 		}
 	}
 
+	// Races between programs executed by different procs need the programs to run concurrently.
+	// This is an expensive strategy, so it goes last and is used only for race-like crashes.
+	if !ctx.fast && concurrentCrashTypes[ctx.crashType] && len(entries) > 1 {
+		res, err := ctx.extractProgConcurrent(entries)
+		if err != nil {
+			return nil, err
+		}
+		if res != nil {
+			ctx.reproLogf(3, "found concurrent reproducer with %d syscalls", len(res.Prog.Calls))
+			return res, nil
+		}
+	}
+
 	ctx.reproLogf(2, "failed to extract reproducer")
 	return nil, nil
 }
@@ -534,6 +552,10 @@ func (ctx *reproContext) minimizeProg(res *Result) (*Result, error) {
 	if ctx.fast {
 		mode = prog.MinimizeCallsOnly
 	}
+	if res.Opts.Concurrent != 0 {
+		ctx.minimizeConcurrent(res, mode)
+		return res, nil
+	}
 	var testErr error
 	res.Prog, _ = prog.Minimize(res.Prog, -1, mode, func(p1 *prog.Prog, callIndex int) bool {
 		if testErr != nil {
@@ -702,6 +724,10 @@ func (ctx *reproContext) getVerdict(callback func() (rep *instance.RunResult, er
 		ctx.reproLogf(2, "not a leak crash: %v", rep.Title)
 		return verdict{false, result.Duration}, nil
 	}
+	if ok, reason := sameBug(ctx.crashReport, rep, ctx.verify); !ok {
+		ctx.reproLogf(2, "not the same bug: %v: %v", rep.Title, reason)
+		return verdict{false, result.Duration}, nil
+	}
 	if strict && len(ctx.observedTitles) > 0 {
 		if !ctx.observedTitles[rep.Title] {
 			ctx.reproLogf(2, "a never seen crash title: %v, ignore", rep.Title)
@@ -858,7 +884,15 @@ type Simplify func(opts *csource.Options) bool
 
 var progSimplifies = []Simplify{
 	func(opts *csource.Options) bool {
-		if opts.Collide || !opts.Threaded {
+		// The parts of the program may trigger the bug even if executed sequentially.
+		if opts.Concurrent == 0 {
+			return false
+		}
+		opts.Concurrent = 0
+		return true
+	},
+	func(opts *csource.Options) bool {
+		if opts.Collide || !opts.Threaded || opts.Concurrent != 0 {
 			return false
 		}
 		opts.Threaded = false
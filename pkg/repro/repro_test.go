@@ -9,15 +9,19 @@ import (
 	"math/rand"
 	"regexp"
 	"sort"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/syzkaller/pkg/build"
 	"github.com/google/syzkaller/pkg/csource"
 	"github.com/google/syzkaller/pkg/flatrpc"
 	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/kconfig"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/report/crash"
 	"github.com/google/syzkaller/pkg/testutil"
 	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/sys/targets"
@@ -94,6 +98,7 @@ func TestSimplifies(t *testing.T) {
 		Cgroups:      true,
 		UseTmpDir:    true,
 		HandleSegv:   true,
+		Concurrent:   1,
 	}
 	var check func(opts csource.Options, i int)
 	check = func(opts csource.Options, i int) {
@@ -314,6 +319,240 @@ func TestFlakyCrashes(t *testing.T) {
 	assert.Greater(t, success, iters/3*2, "must succeed >2/3 of cases")
 }
 
+// The crash happens only if pause() and alarm(0xa) are executed concurrently.
+const concurrentReproLog = `
+2015/12/21 12:18:05 executing program 1:
+pause()
+getpid()
+2015/12/21 12:18:10 executing program 2:
+alarm(0xa)
+getuid()
+`
+
+const kcsanReport = `
+[   44.377931][    C4] ==================================================================
+[   44.379001][    C4] BUG: KCSAN: data-race in find_next_bit / rcu_report_exp_cpu_mult
+[   44.379966][    C4]
+[   44.380268][    C4] read to 0xffffffff85a7f140 of 8 bytes by task 1082 on cpu 7:
+[   44.381409][    C4]  find_next_bit+0x57/0xe0
+[   44.386391][    C4]
+[   44.386691][    C4] write to 0xffffffff85a7f140 of 8 bytes by interrupt on cpu 4:
+[   44.387656][    C4]  rcu_report_exp_cpu_mult+0x4f/0xa0
+[   44.396484][    C4]
+[   44.396800][    C4] Reported by Kernel Concurrency Sanitizer on:
+[   44.397634][    C4] CPU: 4 PID: 6252 Comm: syz-executor Not tainted 5.3.0+ #3
+[   44.399836][    C4] ==================================================================
+`
+
+func TestConcurrentRepro(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	require.NoError(t, err)
+	result, _, err := runTestRepro(t, concurrentReproLog+kcsanReport,
+		&concurrentExecInterface{t: t, target: target})
+	require.NoError(t, err)
+	require.NotNil(t, result)
+	assert.Equal(t, "pause()\nalarm(0xa)\n", string(result.Prog.Serialize()))
+	assert.Equal(t, 1, result.Opts.Concurrent)
+	assert.True(t, result.CRepro)
+	// The test config does not have timeouts set up.
+	opts := result.Opts
+	opts.Slowdown = 1
+	src, err := csource.Write(result.Prog, opts)
+	require.NoError(t, err)
+	assert.Contains(t, string(src), "concurrent_thr")
+}
+
+func TestConcurrentReproNotRace(t *testing.T) {
+	// The concurrent strategy is expensive, so it's not used for crashes that don't look like races.
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	require.NoError(t, err)
+	cei := &concurrentExecInterface{t: t, target: target}
+	result, _, err := runTestRepro(t, concurrentReproLog, cei)
+	require.NoError(t, err)
+	assert.Nil(t, result)
+	assert.False(t, cei.concurrent)
+}
+
+type concurrentExecInterface struct {
+	t          *testing.T
+	target     *prog.Target
+	concurrent bool
+}
+
+func (cei *concurrentExecInterface) Run(_ context.Context, params instance.ExecParams,
+	_ instance.ExecutorLogger) (*instance.RunResult, error) {
+	if params.Opts.Concurrent == 0 {
+		return fakeCrashResult(""), nil
+	}
+	cei.concurrent = true
+	p := params.CProg
+	if p == nil {
+		entries := cei.target.ParseLog(params.SyzProg, prog.NonStrict)
+		require.Len(cei.t, entries, 1)
+		p = entries[0].P
+	}
+	first, second := p.Split(params.Opts.Concurrent)
+	if strings.Contains(string(first.Serialize()), "pause()") &&
+		strings.Contains(string(second.Serialize()), "alarm(0xa)") {
+		return &instance.RunResult{
+			Report: &report.Report{
+				Title: "KCSAN: data-race in find_next_bit / rcu_report_exp_cpu_mult",
+				Type:  crash.KCSANDataRace,
+			},
+		}, nil
+	}
+	return fakeCrashResult(""), nil
+}
+
+func TestMinimizeFeatures(t *testing.T) {
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	require.NoError(t, err)
+	p, err := target.Deserialize([]byte("pause()\n"), prog.NonStrict)
+	require.NoError(t, err)
+	env := Environment{
+		Config: &mgrconfig.Config{
+			Derived: mgrconfig.Derived{
+				TargetOS:     targets.Linux,
+				TargetVMArch: targets.AMD64,
+			},
+		},
+		logf: t.Logf,
+	}
+	for _, cRepro := range []bool{false, true} {
+		res := &Result{
+			Prog:     p,
+			Duration: time.Minute,
+			CRepro:   cRepro,
+			Report:   &report.Report{Title: "crashed"},
+			Opts: csource.Options{
+				Repeat:       true,
+				Procs:        1,
+				Sandbox:      "none",
+				UseTmpDir:    true,
+				NetInjection: true,
+				NetDevices:   true,
+				NetReset:     true,
+				Cgroups:      true,
+				CloseFDs:     true,
+				Sysctl:       true,
+			},
+		}
+		// The bug needs net injection, but crashes with a different title without net devices.
+		runExec := func(_ context.Context, params instance.ExecParams, _ instance.ExecutorLogger) (
+			*instance.RunResult, error) {
+			assert.Equal(t, cRepro, params.CProg != nil)
+			if !params.Opts.NetDevices {
+				return fakeCrashResult("another crash"), nil
+			}
+			if params.Opts.NetInjection {
+				return fakeCrashResult("crashed"), nil
+			}
+			return fakeCrashResult(""), nil
+		}
+		minRes, stats, err := minimizeFeatures(context.Background(), res, env, execFunc(runExec))
+		require.NoError(t, err)
+		require.NotNil(t, stats)
+		// Sysctl can only be disabled in C reproducers, and close_fds is not disabled with Repeat.
+		want := []string{"close_fds", "net_dev", "sysctl", "tun"}
+		if cRepro {
+			want = []string{"close_fds", "net_dev", "tun"}
+		}
+		assert.Equal(t, want, minRes.Opts.EnabledFeatures())
+		assert.True(t, minRes.Opts.ExplicitFeatures)
+		assert.Equal(t, "crashed", minRes.Report.Title)
+	}
+}
+
+type execFunc func(context.Context, instance.ExecParams, instance.ExecutorLogger) (*instance.RunResult, error)
+
+func (f execFunc) Run(ctx context.Context, params instance.ExecParams, logf instance.ExecutorLogger) (
+	*instance.RunResult, error) {
+	return f(ctx, params, logf)
+}
+
+func TestMinimizeConfig(t *testing.T) {
+	const kconfigData = `
+mainmenu "test"
+config A
+	bool "A"
+config B
+	bool "B"
+config C
+	bool "C"
+config D
+	bool "D"
+`
+	kconf, err := kconfig.ParseData(targets.Get(targets.Linux, targets.AMD64), []byte(kconfigData), "Kconfig")
+	require.NoError(t, err)
+	target, err := prog.GetTarget(targets.Linux, targets.AMD64)
+	require.NoError(t, err)
+	p, err := target.Deserialize([]byte("pause()\n"), prog.NonStrict)
+	require.NoError(t, err)
+	res := &Result{
+		Prog:   p,
+		Report: &report.Report{Title: "crashed"},
+	}
+	for _, test := range []struct {
+		needs string
+		err   error
+	}{
+		{needs: "CONFIG_C=y"},
+		{needs: "CONFIG_E=y", err: ErrConfigNotReproducing},
+	} {
+		env := &configTestEnv{needs: test.needs}
+		minConfig, err := MinimizeConfig(res, &ConfigMinimization{
+			Env:      env,
+			KConfig:  kconf,
+			Config:   []byte("CONFIG_A=y\nCONFIG_B=y\nCONFIG_C=y\nCONFIG_D=y\n"),
+			Baseline: []byte("CONFIG_A=y\n"),
+			TestVMs:  2,
+		})
+		if test.err != nil {
+			assert.ErrorIs(t, err, test.err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Contains(t, string(minConfig), "CONFIG_A=y")
+		assert.Contains(t, string(minConfig), "CONFIG_C=y")
+		assert.NotContains(t, string(minConfig), "CONFIG_B=y")
+		assert.NotContains(t, string(minConfig), "CONFIG_D=y")
+		assert.Greater(t, env.builds, 1)
+	}
+}
+
+// configTestEnv reproduces the bug on the kernels built with the needed config.
+type configTestEnv struct {
+	needs  string
+	config []byte
+	builds int
+}
+
+func (env *configTestEnv) BuildSyzkaller(string, string) (string, error) {
+	panic("unreachable")
+}
+
+func (env *configTestEnv) CleanKernel(*instance.BuildKernelConfig) error {
+	panic("unreachable")
+}
+
+func (env *configTestEnv) BuildKernel(buildCfg *instance.BuildKernelConfig) (string, build.ImageDetails, error) {
+	env.builds++
+	env.config = buildCfg.KernelConfig
+	return "", build.ImageDetails{}, nil
+}
+
+func (env *configTestEnv) Test(numVMs int, reproSyz, reproOpts, reproC []byte) ([]instance.EnvTestResult, error) {
+	var ret []instance.EnvTestResult
+	for i := 0; i < numVMs; i++ {
+		var res instance.EnvTestResult
+		if strings.Contains(string(env.config), env.needs) {
+			res.Error = &instance.CrashError{Report: &report.Report{Title: "crashed"}}
+		}
+		ret = append(ret, res)
+	}
+	return ret, nil
+}
+
 func BenchmarkCalculateReliability(b *testing.B) {
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
 
@@ -29,17 +29,29 @@ func RunStrace(result *Result, cfg *mgrconfig.Config, reporter *report.Reporter,
 	if cfg.StraceBin == "" {
 		return straceFailed(fmt.Errorf("strace binary is not set in the config"))
 	}
+	runRes, err := runInstrumented(result, cfg, reporter, pool, "strace", &instance.OptionalConfig{
+		StraceBin:        cfg.StraceBin,
+		BeforeContextLen: straceOutputLogSize,
+	})
+	if err != nil {
+		return straceFailed(err)
+	}
+	return &StraceResult{
+		Report: runRes.Report,
+		Output: runRes.Output,
+	}
+}
+
+// runInstrumented runs the reproducer once more on an instance set up with the opts.
+func runInstrumented(result *Result, cfg *mgrconfig.Config, reporter *report.Reporter, pool *vm.Dispatcher,
+	tool string, opts *instance.OptionalConfig) (*instance.RunResult, error) {
 	var runRes *instance.RunResult
 	var err error
 	runErr := pool.Run(context.Background(), func(ctx context.Context, inst *vm.Instance, updInfo dispatcher.UpdateInfo) {
 		updInfo(func(info *dispatcher.Info) {
-			info.Status = "running strace"
+			info.Status = "running " + tool
 		})
-		ret, setupErr := instance.SetupExecProg(inst, cfg, reporter,
-			&instance.OptionalConfig{
-				StraceBin:        cfg.StraceBin,
-				BeforeContextLen: straceOutputLogSize,
-			})
+		ret, setupErr := instance.SetupExecProg(inst, cfg, reporter, opts)
 		if setupErr != nil {
 			err = fmt.Errorf("failed to set up instance: %w", setupErr)
 			return
@@ -49,24 +61,19 @@ func RunStrace(result *Result, cfg *mgrconfig.Config, reporter *report.Reporter,
 			Duration: result.Duration,
 		}
 		if result.CRepro {
-			log.Logf(1, "running C repro under strace")
+			log.Logf(1, "running C repro under %v", tool)
 			params.CProg = result.Prog
 			runRes, err = ret.RunCProg(params)
 		} else {
-			log.Logf(1, "running syz repro under strace")
+			log.Logf(1, "running syz repro under %v", tool)
 			params.SyzProg = result.Prog.Serialize()
 			runRes, err = ret.RunSyzProg(params)
 		}
 	})
 	if runErr != nil {
-		return straceFailed(runErr)
-	} else if err != nil {
-		return straceFailed(err)
-	}
-	return &StraceResult{
-		Report: runRes.Report,
-		Output: runRes.Output,
+		return nil, runErr
 	}
+	return runRes, err
 }
 
 func straceFailed(err error) *StraceResult {
@@ -0,0 +1,110 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/google/syzkaller/pkg/report"
+)
+
+// The levels of checking that the reproducer triggers the original bug
+// (the repro_verify manager config parameter). Each level includes the previous ones.
+const (
+	// The crash title is the same (the default).
+	verifyTitle = iota
+	// The crash type is the same as well.
+	verifyType
+	// For memory-safety bugs, the sanitizer, the kind of the bad access,
+	// its direction and the object cache are the same.
+	verifyAccess
+	// For memory-safety bugs, the allocation/free stacks have common functions.
+	verifyStacks
+)
+
+var verifyLevels = map[string]int{
+	"":       verifyTitle,
+	"title":  verifyTitle,
+	"type":   verifyType,
+	"access": verifyAccess,
+	"stacks": verifyStacks,
+}
+
+// sameBug checks whether the report describes the same bug as the original one.
+// If not, it returns the reason of the mismatch.
+func sameBug(orig, rep *report.Report, level int) (bool, string) {
+	if orig == nil || level < verifyType {
+		return true, ""
+	}
+	if rep.Type != orig.Type {
+		return false, fmt.Sprintf("crash type %v instead of %v", rep.Type, orig.Type)
+	}
+	if level < verifyAccess || orig.Corrupted || orig.Details == nil || orig.Details.Access == nil {
+		return true, ""
+	}
+	want := orig.Details.Access
+	if rep.Details == nil || rep.Details.Access == nil {
+		return false, "no bad access details"
+	}
+	got := rep.Details.Access
+	if got.Sanitizer != want.Sanitizer || got.Kind != want.Kind || got.Write != want.Write {
+		return false, fmt.Sprintf("%v instead of %v", accessString(got), accessString(want))
+	}
+	if got.Cache != "" && want.Cache != "" && got.Cache != want.Cache {
+		return false, fmt.Sprintf("object cache %v instead of %v", got.Cache, want.Cache)
+	}
+	if level < verifyStacks {
+		return true, ""
+	}
+	for _, kind := range []report.StackKind{report.StackAllocated, report.StackFreed} {
+		wantFuncs := stackFunctions(orig.Details, kind)
+		gotFuncs := stackFunctions(rep.Details, kind)
+		if len(wantFuncs) == 0 || len(gotFuncs) == 0 {
+			// Stacks may be missing e.g. in truncated reports, this is not a strong signal.
+			continue
+		}
+		overlap := false
+		for fn := range gotFuncs {
+			if wantFuncs[fn] {
+				overlap = true
+				break
+			}
+		}
+		if !overlap {
+			return false, fmt.Sprintf("%v stacks do not overlap", kind)
+		}
+	}
+	return true, ""
+}
+
+func accessString(access *report.AccessInfo) string {
+	dir := "read"
+	if access.Write {
+		dir = "write"
+	}
+	return fmt.Sprintf("%v %v %v", access.Sanitizer, access.Kind, dir)
+}
+
+// stackFunctions returns the functions of the stacks of the kind except for
+// the sanitizer, allocator and syscall entry functions that are present in all stacks.
+func stackFunctions(details *report.Details, kind report.StackKind) map[string]bool {
+	funcs := make(map[string]bool)
+	for _, stack := range details.Stacks {
+		if stack.Kind != kind {
+			continue
+		}
+		for _, frame := range stack.Frames {
+			if !frame.Unreliable && !genericStackFrameRe.MatchString(frame.Function) {
+				funcs[frame.Function] = true
+			}
+		}
+	}
+	return funcs
+}
+
+var genericStackFrameRe = regexp.MustCompile(`^(?:_*(?:kasan|kmsan|stack_trace|stack_depot)_.*|` +
+	`_*(?:kmalloc|kmem_cache|kvmalloc|kfree|kvfree|slab|slub|krealloc|kzalloc|kmemdup|rcu|alloc_pages|` +
+	`free_pages|do_kmalloc).*|` +
+	`do_syscall_.*|entry_SYSCALL.*|x64_sys_call|invoke_syscall|el0_.*|ret_from_fork.*|kthread)$`)
@@ -0,0 +1,151 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package repro
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSameBug(t *testing.T) {
+	orig := testUAFReport("kmalloc-64", false, "sock_alloc", "sock_release")
+	tests := []struct {
+		name  string
+		rep   *report.Report
+		level int
+		same  bool
+	}{
+		{
+			name:  "identical",
+			rep:   testUAFReport("kmalloc-64", false, "sock_alloc", "sock_release"),
+			level: verifyStacks,
+			same:  true,
+		},
+		{
+			name:  "other-type-by-title",
+			rep:   &report.Report{Type: crash.KASANRead},
+			level: verifyTitle,
+			same:  true,
+		},
+		{
+			name:  "other-type",
+			rep:   &report.Report{Type: crash.KASANRead},
+			level: verifyType,
+			same:  false,
+		},
+		{
+			name:  "other-cache-by-type",
+			rep:   testUAFReport("kmalloc-128", false, "sock_alloc", "sock_release"),
+			level: verifyType,
+			same:  true,
+		},
+		{
+			name:  "other-cache",
+			rep:   testUAFReport("kmalloc-128", false, "sock_alloc", "sock_release"),
+			level: verifyAccess,
+			same:  false,
+		},
+		{
+			name:  "other-direction",
+			rep:   testUAFReport("kmalloc-64", true, "sock_alloc", "sock_release"),
+			level: verifyAccess,
+			same:  false,
+		},
+		{
+			name:  "no-access",
+			rep:   &report.Report{Type: crash.KASANUseAfterFreeRead},
+			level: verifyAccess,
+			same:  false,
+		},
+		{
+			name:  "other-stacks-by-access",
+			rep:   testUAFReport("kmalloc-64", false, "pipe_alloc", "pipe_release"),
+			level: verifyAccess,
+			same:  true,
+		},
+		{
+			name:  "other-stacks",
+			rep:   testUAFReport("kmalloc-64", false, "sock_alloc", "pipe_release"),
+			level: verifyStacks,
+			same:  false,
+		},
+		{
+			name:  "no-stacks",
+			rep:   testUAFReport("kmalloc-64", false, "", ""),
+			level: verifyStacks,
+			same:  true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			same, reason := sameBug(orig, test.rep, test.level)
+			assert.Equal(t, test.same, same, reason)
+		})
+	}
+	// Non memory-safety bugs are only checked for the type.
+	warning := &report.Report{Type: crash.Warning}
+	same, _ := sameBug(warning, &report.Report{Type: crash.Warning}, verifyStacks)
+	assert.True(t, same)
+}
+
+func TestVerifyRepro(t *testing.T) {
+	orig := testUAFReport("kmalloc-64", false, "sock_alloc", "sock_release")
+	reproCtx := &reproContext{
+		ctx:            context.Background(),
+		crashTitle:     orig.Title,
+		crashType:      orig.Type,
+		crashReport:    orig,
+		verify:         verifyAccess,
+		observedTitles: map[string]bool{},
+		stats:          new(Stats),
+		logf:           t.Logf,
+	}
+	run := func(rep *report.Report) bool {
+		res, err := reproCtx.getVerdict(func() (*instance.RunResult, error) {
+			return &instance.RunResult{Report: rep}, nil
+		}, false)
+		assert.NoError(t, err)
+		return res.Crashed
+	}
+	// The same title, but a different bug.
+	assert.False(t, run(testUAFReport("kmalloc-128", false, "sock_alloc", "sock_release")))
+	assert.True(t, run(testUAFReport("kmalloc-64", false, "pipe_alloc", "pipe_release")))
+}
+
+func testUAFReport(cache string, write bool, allocFunc, freeFunc string) *report.Report {
+	stack := func(kind report.StackKind, fn string) *report.Stack {
+		stack := &report.Stack{Kind: kind}
+		for _, name := range []string{"kasan_save_stack", "__kmalloc", fn, "do_syscall_64"} {
+			if name != "" {
+				stack.Frames = append(stack.Frames, &report.Frame{Function: name})
+			}
+		}
+		return stack
+	}
+	typ := crash.KASANUseAfterFreeRead
+	if write {
+		typ = crash.KASANUseAfterFreeWrite
+	}
+	return &report.Report{
+		Title: "KASAN: use-after-free Read in sock_poll",
+		Type:  typ,
+		Details: &report.Details{
+			Access: &report.AccessInfo{
+				Sanitizer: "KASAN",
+				Kind:      "use-after-free",
+				Write:     write,
+				Cache:     cache,
+			},
+			Stacks: []*report.Stack{
+				stack(report.StackAllocated, allocFunc),
+				stack(report.StackFreed, freeFunc),
+			},
+		},
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package symbolizer
+
+import (
+	"debug/elf"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+)
+
+// ReadBuildID returns the hex-encoded GNU build ID of the ELF binary bin.
+func ReadBuildID(bin string) (string, error) {
+	file, err := elf.Open(bin)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	for _, sec := range file.Sections {
+		if sec.Type != elf.SHT_NOTE {
+			continue
+		}
+		data, err := sec.Data()
+		if err != nil {
+			return "", fmt.Errorf("failed to read section %v: %w", sec.Name, err)
+		}
+		if id := parseBuildIDNote(file.ByteOrder, data); id != "" {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("%v does not have a build ID", bin)
+}
+
+func parseBuildIDNote(order binary.ByteOrder, data []byte) string {
+	const ntGNUBuildID = 3
+	for len(data) >= 12 {
+		nameSize := int(order.Uint32(data[0:]))
+		descSize := int(order.Uint32(data[4:]))
+		typ := order.Uint32(data[8:])
+		data = data[12:]
+		nameEnd := align4(nameSize)
+		descEnd := nameEnd + align4(descSize)
+		if nameEnd > len(data) || nameEnd+descSize > len(data) {
+			return ""
+		}
+		if typ == ntGNUBuildID && string(data[:nameSize]) == "GNU\x00" {
+			return hex.EncodeToString(data[nameEnd : nameEnd+descSize])
+		}
+		if descEnd > len(data) {
+			return ""
+		}
+		data = data[descEnd:]
+	}
+	return ""
+}
+
+func align4(v int) int {
+	return (v + 3) &^ 3
+}
@@ -0,0 +1,34 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package symbolizer
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBuildIDNote(t *testing.T) {
+	note := func(typ uint32, name string, desc []byte) []byte {
+		var buf []byte
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(name)))
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(desc)))
+		buf = binary.LittleEndian.AppendUint32(buf, typ)
+		buf = append(buf, name...)
+		for len(buf)%4 != 0 {
+			buf = append(buf, 0)
+		}
+		buf = append(buf, desc...)
+		for len(buf)%4 != 0 {
+			buf = append(buf, 0)
+		}
+		return buf
+	}
+	id := []byte{0xde, 0xad, 0xbe, 0xef, 0x01}
+	data := append(note(1, "Xen\x00", []byte{1, 2, 3}), note(3, "GNU\x00", id)...)
+	assert.Equal(t, "deadbeef01", parseBuildIDNote(binary.LittleEndian, data))
+	assert.Equal(t, "", parseBuildIDNote(binary.LittleEndian, data[:len(data)-8]))
+	assert.Equal(t, "", parseBuildIDNote(binary.LittleEndian, note(3, "Go\x00\x00", id)))
+}
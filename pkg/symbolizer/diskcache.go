@@ -0,0 +1,303 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package symbolizer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// cachingSymbolizer consults the persistent cache before invoking the inner symbolizer
+// and saves new results to the cache.
+// Binaries without a build ID are symbolized without caching.
+type cachingSymbolizer struct {
+	inner Symbolizer
+	dir   string
+	key   string
+	files map[string]*cacheFile
+}
+
+func (s *cachingSymbolizer) Symbolize(bin string, pcs ...uint64) ([]Frame, error) {
+	file, ok := s.files[bin]
+	if !ok {
+		file = openCacheFile(s.dir, s.key, bin)
+		s.files[bin] = file
+	}
+	if file == nil {
+		return s.inner.Symbolize(bin, pcs...)
+	}
+	return file.symbolize(pcs, func(pcs []uint64) ([]Frame, error) {
+		return s.inner.Symbolize(bin, pcs...)
+	})
+}
+
+func (s *cachingSymbolizer) Close() {
+	s.inner.Close()
+	for _, file := range s.files {
+		if file != nil {
+			file.release()
+		}
+	}
+}
+
+func cacheKeyName(backend string) string {
+	if backend == "" {
+		backend = BackendAddr2Line
+	}
+	return backend
+}
+
+// cacheFile holds symbolization results for a single binary.
+// On disk the results are stored as a sequence of records, each record holds the results
+// of one symbolization request and is appended with a single write, so several processes
+// can share the file. In memory, the results are loaded lazily and shared by all symbolizers
+// in the process while the file is open. Only up to maxCachedPCs are kept in memory,
+// the rest is still saved on disk but needs to be symbolized again in this process
+// (the results are not appended to the file again).
+type cacheFile struct {
+	path     string
+	mu       sync.Mutex
+	refs     int
+	loaded   bool
+	frames   map[uint64][]Frame
+	stored   map[uint64]struct{} // PCs that are already saved on disk
+	interner Interner
+}
+
+type cacheRecord struct {
+	PC     uint64
+	Frames []Frame
+}
+
+const (
+	cacheRecordMagic = 0x63727973 // "syrc"
+	// Records are prefixed with the magic and the size of the gob-encoded data.
+	cacheRecordHeader = 8
+	maxCacheRecord    = 64 << 20
+)
+
+// maxCachedPCs bounds the size of the in-memory cache of each file.
+var maxCachedPCs = 1 << 20
+
+var (
+	cacheFilesMu sync.Mutex
+	cacheFiles   = make(map[string]*cacheFile)
+)
+
+// openCacheFile returns the cache for the binary, or nil if the binary can't be cached.
+// The file must be released after use.
+func openCacheFile(dir, key, bin string) *cacheFile {
+	buildID, err := ReadBuildID(bin)
+	if err != nil {
+		return nil
+	}
+	path := filepath.Join(dir, buildID+"."+key)
+	cacheFilesMu.Lock()
+	defer cacheFilesMu.Unlock()
+	file := cacheFiles[path]
+	if file == nil {
+		file = &cacheFile{path: path}
+		cacheFiles[path] = file
+	}
+	file.refs++
+	return file
+}
+
+// release drops the in-memory results once the file is not used by anybody.
+func (file *cacheFile) release() {
+	cacheFilesMu.Lock()
+	defer cacheFilesMu.Unlock()
+	file.refs--
+	if file.refs == 0 && cacheFiles[file.path] == file {
+		delete(cacheFiles, file.path)
+	}
+}
+
+// symbolize returns the cached results and symbolizes the rest with inner.
+// Failures to save new results to disk are not fatal, they are just not cached.
+func (file *cacheFile) symbolize(pcs []uint64, inner func([]uint64) ([]Frame, error)) ([]Frame, error) {
+	file.mu.Lock()
+	file.load()
+	var missing []uint64
+	seen := make(map[uint64]bool)
+	for _, pc := range pcs {
+		if _, ok := file.frames[pc]; !ok && !seen[pc] {
+			seen[pc] = true
+			missing = append(missing, pc)
+		}
+	}
+	file.mu.Unlock()
+	res := make(map[uint64][]Frame, len(missing))
+	if len(missing) != 0 {
+		frames, err := inner(missing)
+		if err != nil {
+			return nil, err
+		}
+		// PCs without frames are cached as well, so that we don't symbolize them again.
+		for _, pc := range missing {
+			res[pc] = nil
+		}
+		for _, frame := range frames {
+			res[frame.PC] = append(res[frame.PC], frame)
+		}
+	}
+	file.mu.Lock()
+	defer file.mu.Unlock()
+	var unstored []uint64
+	for _, pc := range missing {
+		if _, ok := file.stored[pc]; !ok {
+			unstored = append(unstored, pc)
+		}
+	}
+	if len(unstored) != 0 && file.append(unstored, res) == nil {
+		for _, pc := range unstored {
+			file.stored[pc] = struct{}{}
+		}
+	}
+	var frames []Frame
+	for _, pc := range pcs {
+		if frames1, ok := file.frames[pc]; ok {
+			frames = append(frames, frames1...)
+		} else {
+			frames = append(frames, res[pc]...)
+		}
+	}
+	for _, pc := range missing {
+		file.add(pc, res[pc])
+	}
+	return frames, nil
+}
+
+func (file *cacheFile) add(pc uint64, frames []Frame) {
+	if _, ok := file.frames[pc]; ok || len(file.frames) < maxCachedPCs {
+		file.frames[pc] = frames
+	}
+}
+
+func (file *cacheFile) load() {
+	if file.loaded {
+		return
+	}
+	file.loaded = true
+	file.frames = make(map[uint64][]Frame)
+	file.stored = make(map[uint64]struct{})
+	records, err := file.read()
+	for _, rec := range records {
+		file.stored[rec.PC] = struct{}{}
+		for i := range rec.Frames {
+			rec.Frames[i].Func = file.interner.Do(rec.Frames[i].Func)
+			rec.Frames[i].File = file.interner.Do(rec.Frames[i].File)
+		}
+		file.add(rec.PC, rec.Frames)
+	}
+	if err != nil {
+		// Records appended after a corrupted one (e.g. a process crashed in the middle
+		// of a write) can't be read, so start a new file with the readable records.
+		file.rewrite(records)
+	}
+}
+
+// read returns the records stored on disk. A missing file is not an error,
+// on a corrupted file read returns the records before the corruption and an error.
+func (file *cacheFile) read() ([]cacheRecord, error) {
+	f, err := os.Open(file.path)
+	if err != nil {
+		return nil, nil
+	}
+	defer f.Close()
+	var records []cacheRecord
+	r := bufio.NewReader(f)
+	for {
+		var hdr [cacheRecordHeader]byte
+		if _, err := io.ReadFull(r, hdr[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return records, nil
+			}
+			return records, err
+		}
+		size := binary.LittleEndian.Uint32(hdr[4:])
+		if binary.LittleEndian.Uint32(hdr[:]) != cacheRecordMagic || size > maxCacheRecord {
+			return records, fmt.Errorf("bad record header")
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return records, err
+		}
+		var records1 []cacheRecord
+		if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&records1); err != nil {
+			return records, err
+		}
+		records = append(records, records1...)
+	}
+}
+
+func encodeCacheRecords(buf *bytes.Buffer, records []cacheRecord) error {
+	pos := buf.Len()
+	buf.Write(make([]byte, cacheRecordHeader))
+	if err := gob.NewEncoder(buf).Encode(records); err != nil {
+		return err
+	}
+	hdr := buf.Bytes()[pos:]
+	binary.LittleEndian.PutUint32(hdr, cacheRecordMagic)
+	binary.LittleEndian.PutUint32(hdr[4:], uint32(buf.Len()-pos-cacheRecordHeader))
+	return nil
+}
+
+// append saves new results to disk as a single record.
+func (file *cacheFile) append(pcs []uint64, res map[uint64][]Frame) error {
+	records := make([]cacheRecord, 0, len(pcs))
+	for _, pc := range pcs {
+		records = append(records, cacheRecord{PC: pc, Frames: res[pc]})
+	}
+	buf := new(bytes.Buffer)
+	if err := encodeCacheRecords(buf, records); err != nil {
+		return err
+	}
+	if err := osutil.MkdirAll(filepath.Dir(file.path)); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(file.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, osutil.DefaultFilePerm)
+	if err != nil {
+		return err
+	}
+	// O_APPEND makes the write atomic with respect to writes of other processes.
+	_, err = f.Write(buf.Bytes())
+	if err1 := f.Close(); err == nil {
+		err = err1
+	}
+	return err
+}
+
+func (file *cacheFile) rewrite(records []cacheRecord) error {
+	buf := new(bytes.Buffer)
+	if len(records) != 0 {
+		if err := encodeCacheRecords(buf, records); err != nil {
+			return err
+		}
+	}
+	// Several processes may rewrite the same file concurrently, so we can't use a fixed temp file name.
+	tmp, err := os.CreateTemp(filepath.Dir(file.path), filepath.Base(file.path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), file.path)
+}
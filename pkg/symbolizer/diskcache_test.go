@@ -0,0 +1,86 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package symbolizer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "buildid.addr2line")
+	var called []uint64
+	inner := func(pcs []uint64) ([]Frame, error) {
+		called = append(called, pcs...)
+		var frames []Frame
+		for _, pc := range pcs {
+			// PCs >= 100 are not symbolizable.
+			if pc < 100 {
+				frames = append(frames,
+					Frame{PC: pc, Func: "inlined", File: "foo.c", Line: int(pc), Inline: true},
+					Frame{PC: pc, Func: "foo", File: "foo.c", Line: 1})
+			}
+		}
+		return frames, nil
+	}
+	check := func(file *cacheFile, pcs, wantCalled []uint64) {
+		t.Helper()
+		called = nil
+		frames, err := file.symbolize(pcs, inner)
+		require.NoError(t, err)
+		assert.Equal(t, wantCalled, called)
+		want, _ := inner(pcs)
+		assert.Equal(t, want, frames)
+	}
+
+	file1 := &cacheFile{path: path}
+	check(file1, []uint64{1, 2, 100, 1}, []uint64{1, 2, 100})
+	check(file1, []uint64{2, 3, 100}, []uint64{3})
+
+	// A concurrent process adds more results.
+	file2 := &cacheFile{path: path}
+	check(file2, []uint64{1, 2, 3, 100}, nil)
+	check(file2, []uint64{4}, []uint64{4})
+	check(file1, []uint64{5}, []uint64{5})
+
+	file3 := &cacheFile{path: path}
+	check(file3, []uint64{5, 4, 3, 2, 1, 100}, nil)
+
+	// A process crashed in the middle of a write, the readable records are preserved.
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	require.NoError(t, err)
+	_, err = f.Write([]byte{0x73, 0x79, 0x72, 0x63, 0xff})
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	file4 := &cacheFile{path: path}
+	check(file4, []uint64{1, 2, 3, 4, 5, 100}, nil)
+	check(file4, []uint64{6}, []uint64{6})
+	file5 := &cacheFile{path: path}
+	check(file5, []uint64{6, 5, 4, 3, 2, 1, 100}, nil)
+
+	// Only maxCachedPCs are kept in memory, the rest is still saved on disk.
+	defer func(old int) { maxCachedPCs = old }(maxCachedPCs)
+	maxCachedPCs = 8
+	file6 := &cacheFile{path: path}
+	check(file6, []uint64{7, 8, 9}, []uint64{7, 8, 9})
+	assert.Len(t, file6.frames, 8)
+	check(file6, []uint64{9}, []uint64{9})
+	// The PCs that are already on disk are not saved again.
+	size := fileSize(t, path)
+	check(file6, []uint64{9, 8}, []uint64{9, 8})
+	assert.Equal(t, size, fileSize(t, path))
+	maxCachedPCs = 100
+	file7 := &cacheFile{path: path}
+	check(file7, []uint64{9, 8, 7, 6, 5, 4, 3, 2, 1, 100}, nil)
+}
+
+func fileSize(t *testing.T, path string) int64 {
+	st, err := os.Stat(path)
+	require.NoError(t, err)
+	return st.Size()
+}
@@ -0,0 +1,101 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package symbolizer
+
+import (
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLLVM(t *testing.T) {
+	tests := []struct {
+		output string
+		frames []Frame
+		err    string
+	}{
+		{
+			output: `{"Address":"0x1040","ModuleName":"vmlinux","Symbol":[` +
+				`{"Column":76,"Discriminator":0,"FileName":"mm/kasan/report.c","FunctionName":"kasan_report",` +
+				`"Line":301,"StartAddress":"0x1040","StartFileName":"mm/kasan/report.c","StartLine":290},` +
+				`{"Column":25,"Discriminator":0,"FileName":"mm/kasan/report.c",` +
+				`"FunctionName":"__asan_report_load2_noabort","Line":0}]}`,
+			frames: []Frame{
+				{PC: 0x1040, Func: "kasan_report", File: "mm/kasan/report.c", Line: 301, Inline: true},
+				{PC: 0x1040, Func: "__asan_report_load2_noabort", File: "mm/kasan/report.c", Line: -1},
+			},
+		},
+		{
+			output: `{"Address":"0x1040","ModuleName":"vmlinux","Symbol":[{"Column":0,"Discriminator":0,` +
+				`"FileName":"","FunctionName":"","Line":0,"StartAddress":"","StartFileName":"","StartLine":0}]}`,
+		},
+		{
+			output: `{"Address":"0x1040","Error":{"Message":"No such file or directory"},"ModuleName":"vmlinux"}`,
+			err:    "llvm-symbolizer failed: No such file or directory",
+		},
+		{
+			// Output for a different address means that we are out of sync with llvm-symbolizer.
+			output: `{"Address":"0x5","ModuleName":"vmlinux","Symbol":[]}`,
+			err:    "llvm-symbolizer output for address \"0x5\", want \"0x1040\"",
+		},
+		{
+			output: `0x1040`,
+			err:    "failed to parse llvm-symbolizer output",
+		},
+	}
+	for i, test := range tests {
+		var interner Interner
+		frames, err := parseLLVM(&interner, 0x1040, []byte(test.output))
+		if test.err != "" {
+			assert.ErrorContains(t, err, test.err, "test #%v", i)
+			continue
+		}
+		assert.NoError(t, err, "test #%v", i)
+		assert.Equal(t, test.frames, frames, "test #%v", i)
+	}
+}
+
+func TestLLVMSymbolizer(t *testing.T) {
+	if _, err := exec.LookPath(BackendLLVM); err != nil {
+		t.Skipf("%v is not available: %v", BackendLLVM, err)
+	}
+	dir := t.TempDir()
+	src := filepath.Join(dir, "test.c")
+	require.NoError(t, osutil.WriteFile(src, []byte("int foo(int x) { return x * 2; }\nint main() { return foo(1); }\n")))
+	bin := filepath.Join(dir, "test")
+	if out, err := osutil.RunCmd(time.Minute, dir, "cc", "-g", "-O0", "-o", bin, src); err != nil {
+		t.Skipf("failed to build the test binary: %v\n%s", err, out)
+	}
+	syms, err := ReadTextSymbols(bin)
+	require.NoError(t, err)
+	require.Len(t, syms["foo"], 1)
+	pc := syms["foo"][0].Addr
+	missing := filepath.Join(dir, "missing")
+	symb := &llvmSymbolizer{}
+	defer symb.Close()
+	for i := 0; i < 3; i++ {
+		// The first address to a missing binary produces an error line, and the rest
+		// produce empty results, all of them must be consumed.
+		_, err = symb.Symbolize(missing, 0x10, 0x20, 0x30)
+		if i == 0 {
+			assert.ErrorContains(t, err, "llvm-symbolizer failed")
+		}
+		frames, err := symb.Symbolize(missing, 0x40)
+		require.NoError(t, err)
+		assert.Empty(t, frames)
+
+		frames, err = symb.Symbolize(bin, pc, pc)
+		require.NoError(t, err)
+		assert.Equal(t, []Frame{
+			{PC: pc, Func: "foo", File: src, Line: 1},
+			{PC: pc, Func: "foo", File: src, Line: 1},
+		}, frames)
+	}
+}
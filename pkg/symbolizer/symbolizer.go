@@ -3,7 +3,11 @@
 
 package symbolizer
 
-import "github.com/google/syzkaller/sys/targets"
+import (
+	"fmt"
+
+	"github.com/google/syzkaller/sys/targets"
+)
 
 type Frame struct {
 	PC     uint64
@@ -18,6 +22,110 @@ type Symbolizer interface {
 	Close()
 }
 
-func Make(target *targets.Target) Symbolizer {
-	return &addr2Line{target: target}
+const (
+	BackendAddr2Line = "addr2line"
+	BackendLLVM      = "llvm-symbolizer"
+)
+
+var Backends = []string{BackendAddr2Line, BackendLLVM}
+
+// Config selects the symbolizer implementation returned by Make.
+// A nil config means addr2line without the persistent cache.
+type Config struct {
+	// Backend is one of Backends (BackendAddr2Line if empty).
+	Backend string
+	// CacheDir enables the persistent on-disk cache of symbolization results.
+	// The cache is keyed by the binary build ID and shared by all processes using the same dir.
+	CacheDir string
+}
+
+func Make(target *targets.Target, cfg *Config) Symbolizer {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	symb := makeBackend(target, cfg.Backend)
+	if cfg.CacheDir != "" {
+		symb = &cachingSymbolizer{
+			inner: symb,
+			dir:   cfg.CacheDir,
+			key:   cacheKeyName(cfg.Backend),
+			files: make(map[string]*cacheFile),
+		}
+	}
+	return symb
+}
+
+func makeBackend(target *targets.Target, backend string) Symbolizer {
+	switch backend {
+	case "", BackendAddr2Line:
+		return &addr2Line{target: target}
+	case BackendLLVM:
+		return &llvmSymbolizer{}
+	default:
+		panic(fmt.Sprintf("unknown symbolizer backend %q", backend))
+	}
+}
+
+// SymbolizeBatch symbolizes a large number of pcs in bin using up to procs backend processes in parallel.
+// The frames are returned in the order of pcs. With the persistent cache enabled,
+// only pcs missing in the cache are symbolized.
+func SymbolizeBatch(target *targets.Target, cfg *Config, bin string, pcs []uint64, procs int) ([]Frame, error) {
+	if cfg == nil {
+		cfg = &Config{}
+	}
+	symbolize := func(pcs []uint64) ([]Frame, error) {
+		return symbolizeParallel(func() Symbolizer {
+			return makeBackend(target, cfg.Backend)
+		}, bin, pcs, procs)
+	}
+	if cfg.CacheDir == "" {
+		return symbolize(pcs)
+	}
+	file := openCacheFile(cfg.CacheDir, cacheKeyName(cfg.Backend), bin)
+	if file == nil {
+		return symbolize(pcs)
+	}
+	defer file.release()
+	return file.symbolize(pcs, symbolize)
+}
+
+func symbolizeParallel(makeSymb func() Symbolizer, bin string, pcs []uint64, procs int) ([]Frame, error) {
+	const chunkSize = 100
+	results := make([][]Frame, (len(pcs)+chunkSize-1)/chunkSize)
+	procs = max(min(procs, len(results)), 1)
+	chunks := make(chan int)
+	errs := make(chan error, procs)
+	for p := 0; p < procs; p++ {
+		go func() {
+			symb := makeSymb()
+			defer symb.Close()
+			var err error
+			for i := range chunks {
+				frames, err1 := symb.Symbolize(bin, pcs[i*chunkSize:min((i+1)*chunkSize, len(pcs))]...)
+				if err1 != nil {
+					err = err1
+				}
+				results[i] = frames
+			}
+			errs <- err
+		}()
+	}
+	for i := range results {
+		chunks <- i
+	}
+	close(chunks)
+	var err error
+	for p := 0; p < procs; p++ {
+		if err1 := <-errs; err1 != nil {
+			err = err1
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	var frames []Frame
+	for _, res := range results {
+		frames = append(frames, res...)
+	}
+	return frames, nil
 }
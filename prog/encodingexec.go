@@ -66,6 +66,14 @@ const (
 // Returns number of bytes written to the buffer.
 // If the provided buffer is too small for the program an error is returned.
 func (p *Prog) SerializeForExec() ([]byte, error) {
+	return p.SerializeForExecKeepResults(0)
+}
+
+// SerializeForExecKeepResults is like SerializeForExec, but resources produced by the first
+// keep calls are copied out even if p does not use them. Two programs that start with the same
+// calls then get the same copyout indexes for their results, so a program can refer to
+// the results of calls that were executed before by another program (snapshot warm-up).
+func (p *Prog) SerializeForExecKeepResults(keep int) ([]byte, error) {
 	p.debugValidate()
 	w := &execContext{
 		target: p.Target,
@@ -73,7 +81,8 @@ func (p *Prog) SerializeForExec() ([]byte, error) {
 		args:   make(map[Arg]argInfo),
 	}
 	w.write(uint64(len(p.Calls)))
-	for _, c := range p.Calls {
+	for i, c := range p.Calls {
+		w.keepResults = i < keep
 		w.csumMap, w.csumUses = calcChecksumsCall(c)
 		// TODO: if we propagate this error, something breaks and no coverage
 		// is displayed to the dashboard or the logs.
@@ -109,7 +118,7 @@ func (w *execContext) serializeCall(c *Call) error {
 	}
 	// Generate the call itself.
 	w.write(uint64(c.Meta.ID))
-	if c.Ret != nil && len(c.Ret.uses) != 0 {
+	if c.Ret != nil && w.isUsed(c.Ret) {
 		if _, ok := w.args[c.Ret]; ok {
 			panic("argInfo is already created for return value")
 		}
@@ -196,8 +205,9 @@ type execContext struct {
 	args       map[Arg]argInfo
 	copyoutSeq uint64
 	// Per-call state cached here to not pass it through all functions.
-	csumMap  map[Arg]CsumInfo
-	csumUses map[Arg]struct{}
+	keepResults bool
+	csumMap     map[Arg]CsumInfo
+	csumUses    map[Arg]struct{}
 }
 
 type argInfo struct {
@@ -248,8 +258,13 @@ func (w *execContext) writeCopyin(c *Call) {
 	})
 }
 
+// isUsed says if the value of the resource needs to be copied out.
+func (w *execContext) isUsed(res *ResultArg) bool {
+	return len(res.uses) != 0 || w.keepResults && res.Dir() != DirIn
+}
+
 func (w *execContext) willBeUsed(arg Arg) bool {
-	if res, ok := arg.(*ResultArg); ok && len(res.uses) != 0 {
+	if res, ok := arg.(*ResultArg); ok && w.isUsed(res) {
 		return true
 	}
 	_, ok1 := w.csumMap[arg]
@@ -304,7 +319,7 @@ func (w *execContext) writeChecksums() {
 
 func (w *execContext) writeCopyout(c *Call) {
 	ForeachArg(c, func(arg Arg, _ *ArgCtx) {
-		if res, ok := arg.(*ResultArg); ok && len(res.uses) != 0 {
+		if res, ok := arg.(*ResultArg); ok && w.isUsed(res) {
 			// Create a separate copyout instruction that has own Idx.
 			info := w.args[arg]
 			if info.Ret {
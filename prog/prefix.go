@@ -0,0 +1,60 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import "bytes"
+
+// WithPrefix returns a new program that consists of the prefix calls followed by the calls of p.
+// Resource arguments of p that don't refer to any other call are bound to the last
+// compatible resource produced by the prefix, so that p operates on the state set up by the prefix.
+// The binding is deterministic: the same p and prefix always result in the same program.
+func (p *Prog) WithPrefix(prefix *Prog) *Prog {
+	if prefix.Target != p.Target {
+		panic("prefix has a different target")
+	}
+	res := prefix.Clone()
+	var produced []*ResultArg
+	for _, c := range res.Calls {
+		ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+			if a, ok := arg.(*ResultArg); ok && a.Dir() != DirIn {
+				produced = append(produced, a)
+			}
+		})
+	}
+	body := p.Clone()
+	for _, c := range body.Calls {
+		ForeachArg(c, func(arg Arg, _ *ArgCtx) {
+			a, ok := arg.(*ResultArg)
+			if !ok || a.Res != nil || a.Dir() == DirOut {
+				return
+			}
+			dst := a.Type().(*ResourceType)
+			for i := len(produced) - 1; i >= 0; i-- {
+				src := produced[i].Type().(*ResourceType)
+				if !isCompatibleResourceImpl(dst.Desc.Kind, src.Desc.Kind, true) {
+					continue
+				}
+				a.Res = produced[i]
+				if a.Res.uses == nil {
+					a.Res.uses = make(map[*ResultArg]bool)
+				}
+				a.Res.uses[a] = true
+				break
+			}
+		})
+	}
+	res.Calls = append(res.Calls, body.Calls...)
+	res.debugValidate()
+	return res
+}
+
+// HasPrefix says if p starts with the calls of prefix, regardless of whether
+// the rest of p refers to the resources produced by them.
+func (p *Prog) HasPrefix(prefix *Prog) bool {
+	if prefix.Target != p.Target || len(p.Calls) < len(prefix.Calls) {
+		return false
+	}
+	first, _ := p.Split(len(prefix.Calls))
+	return bytes.Equal(first.Serialize(), prefix.Serialize())
+}
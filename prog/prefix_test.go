@@ -0,0 +1,105 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithPrefix(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	tests := []struct {
+		prefix string
+		prog   string
+		result string
+	}{
+		{
+			prefix: "test$res0()\n",
+			prog:   "test$res1(0xffff)\n",
+			result: "r0 = test$res0()\ntest$res1(r0)\n",
+		},
+		{
+			prefix: "test$res0()\ntest$res3(&(0x7f0000000000)=0x0)\n",
+			prog:   "test$res1(0xffff)\ntest$res1(0x1)\n",
+			result: "test$res0()\ntest$res3(&(0x7f0000000000)=<r0=>0x0)\ntest$res1(r0)\ntest$res1(r0)\n",
+		},
+		{
+			// Resources created by the program itself are left as is.
+			prefix: "test$res0()\n",
+			prog:   "r0 = test$res0()\ntest$res1(r0)\n",
+			result: "test$res0()\nr0 = test$res0()\ntest$res1(r0)\n",
+		},
+		{
+			// Incompatible resources are not bound.
+			prefix: "test$res2()\n",
+			prog:   "test$res1(0xffff)\n",
+			result: "test$res2()\ntest$res1(0xffff)\n",
+		},
+	}
+	for i, test := range tests {
+		prefix, err := target.Deserialize([]byte(test.prefix), Strict)
+		require.NoError(t, err)
+		p, err := target.Deserialize([]byte(test.prog), Strict)
+		require.NoError(t, err)
+		res := p.WithPrefix(prefix)
+		assert.Equal(t, test.result, string(res.Serialize()), "test #%v", i)
+		// The inputs must not be modified.
+		assert.Equal(t, test.prefix, string(prefix.Serialize()), "test #%v", i)
+		assert.Equal(t, test.prog, string(p.Serialize()), "test #%v", i)
+	}
+}
+
+func TestHasPrefix(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	prefix, err := target.Deserialize([]byte("test$res0()\n"), Strict)
+	require.NoError(t, err)
+	for text, want := range map[string]bool{
+		"test$res0()\ntest$res1(0xffff)\n":  true,
+		"r0 = test$res0()\ntest$res1(r0)\n": true,
+		"test$res0()\n":                     true,
+		"test$res1(0xffff)\ntest$res0()\n":  false,
+		"test$res2()\ntest$res0()\n":        false,
+		"test$res1(0xffff)\n":               false,
+	} {
+		p, err := target.Deserialize([]byte(text), Strict)
+		require.NoError(t, err)
+		assert.Equal(t, want, p.HasPrefix(prefix), "%q", text)
+	}
+}
+
+func TestSerializeForExecKeepResults(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	prefix, err := target.Deserialize([]byte("test$res0()\ntest$res3(&(0x7f0000000000))\n"), Strict)
+	require.NoError(t, err)
+	p, err := target.Deserialize([]byte("test$res1(0xffff)\n"), Strict)
+	require.NoError(t, err)
+	p = p.WithPrefix(prefix)
+
+	// Results of the prefix are not copied out if nothing uses them.
+	data, err := prefix.SerializeForExec()
+	require.NoError(t, err)
+	unused, err := target.DeserializeExec(data, nil)
+	require.NoError(t, err)
+	assert.Equal(t, ExecNoCopyout, unused.Calls[0].Index)
+	assert.Empty(t, unused.Calls[1].Copyout)
+
+	// With kept results, the prefix calls are serialized the same way in both programs,
+	// so the program can refer to the results of the prefix executed on its own.
+	kept, err := prefix.SerializeForExecKeepResults(len(prefix.Calls))
+	require.NoError(t, err)
+	data, err = p.SerializeForExecKeepResults(len(prefix.Calls))
+	require.NoError(t, err)
+	// Skip the number of calls in the beginning and the EOF instruction in the end.
+	assert.True(t, bytes.HasPrefix(data[1:], kept[1:len(kept)-1]))
+	full, err := target.DeserializeExec(data, nil)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(0), full.Calls[0].Index)
+	assert.Len(t, full.Calls[1].Copyout, 1)
+	// The last call refers to the resource produced by the prefix.
+	assert.Equal(t, full.Calls[1].Copyout[0].Index, full.Calls[2].Args[0].(ExecArgResult).Index)
+}
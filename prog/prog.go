@@ -489,6 +489,36 @@ func (p *Prog) RemoveCall(idx int) {
 	p.Calls = p.Calls[:len(p.Calls)-1]
 }
 
+// Split returns two new programs: the first n calls of p and the rest of the calls.
+// Uses of resources produced by the first part in the second part are replaced with default values.
+func (p *Prog) Split(n int) (*Prog, *Prog) {
+	if n < 0 || n > len(p.Calls) {
+		panic(fmt.Sprintf("bad split %v for a program with %v calls", n, len(p.Calls)))
+	}
+	first, second := p.Clone(), p.Clone()
+	for len(first.Calls) > n {
+		first.RemoveCall(len(first.Calls) - 1)
+	}
+	for i := 0; i < n; i++ {
+		second.RemoveCall(0)
+	}
+	return first, second
+}
+
+// Concat returns a new program that consists of the calls of all progs.
+// Unlike WithPrefix, resources are not bound across the programs.
+func Concat(progs ...*Prog) *Prog {
+	res := &Prog{Target: progs[0].Target}
+	for _, p := range progs {
+		if p.Target != res.Target {
+			panic("concatenated programs have different targets")
+		}
+		res.Calls = append(res.Calls, p.Clone().Calls...)
+	}
+	res.debugValidate()
+	return res
+}
+
 func (p *Prog) sanitizeFix() {
 	if err := p.sanitize(true); err != nil {
 		panic(err)
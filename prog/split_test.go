@@ -0,0 +1,34 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package prog
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitConcat(t *testing.T) {
+	target := initTargetTest(t, "test", "64")
+	const text = "r0 = test$res0()\ntest$res1(r0)\nr1 = test$res0()\ntest$res1(r1)\n"
+	p, err := target.Deserialize([]byte(text), Strict)
+	require.NoError(t, err)
+
+	first, second := p.Split(2)
+	assert.Equal(t, "r0 = test$res0()\ntest$res1(r0)\n", string(first.Serialize()))
+	assert.Equal(t, "r0 = test$res0()\ntest$res1(r0)\n", string(second.Serialize()))
+	assert.Equal(t, text, string(Concat(first, second).Serialize()))
+
+	// Uses of resources produced by the first part are dropped.
+	first, second = p.Split(1)
+	assert.Equal(t, "test$res0()\n", string(first.Serialize()))
+	assert.Equal(t, "test$res1(0xffff)\nr0 = test$res0()\ntest$res1(r0)\n", string(second.Serialize()))
+
+	first, second = p.Split(0)
+	assert.Empty(t, first.Calls)
+	assert.Equal(t, text, string(second.Serialize()))
+	// The input must not be modified.
+	assert.Equal(t, text, string(p.Serialize()))
+}
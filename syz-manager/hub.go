@@ -57,7 +57,7 @@ func (mgr *Manager) hubSyncLoop(keyGet keyGetter, enabledSyscalls map[*prog.Sysc
 		statRecvRepro:     stat.New("hub recv repro", "", stat.Graph("hub repros")),
 		statRecvReproDrop: stat.New("hub recv repro drop", "", stat.NoGraph),
 	}
-	if mgr.cfg.Reproduce && mgr.dash != nil {
+	if mgr.runtimeCfg().Reproduce && mgr.dash != nil {
 		// Request reproducers from hub only if there is nothing else to reproduce.
 		hc.needMoreRepros = mgr.reproLoop.Empty
 	}
@@ -60,13 +60,18 @@ var (
 )
 
 type Manager struct {
+	// The config does not change after startup, the fields that are reloaded at runtime
+	// must be accessed via runtimeCfg.
 	cfg             *mgrconfig.Config
+	liveCfg         atomic.Pointer[mgrconfig.Config]
+	reloadMu        sync.Mutex
+	dashReproTasks  atomic.Bool
 	mode            *Mode
 	vmPool          *vm.Pool
 	pool            *vm.Dispatcher
 	target          *prog.Target
 	sysTarget       *targets.Target
-	reporter        *report.Reporter
+	reporter        atomic.Pointer[report.Reporter]
 	crashStore      *manager.CrashStore
 	serv            rpcserver.Server
 	http            *manager.HTTPServer
@@ -77,6 +82,7 @@ type Manager struct {
 	corpusPreload   chan []fuzzer.Candidate
 	firstConnect    atomic.Int64 // unix time, or 0 if not connected
 	crashTypes      map[string]bool
+	memoryDumps     map[string]bool // crash titles with memory dumps taken in this run
 	enabledFeatures flatrpc.Feature
 	checkDone       atomic.Bool
 	reportGenerator *manager.ReportGeneratorWrapper
@@ -91,7 +97,11 @@ type Manager struct {
 	mu             sync.Mutex
 	fuzzer         atomic.Pointer[fuzzer.Fuzzer]
 	snapshotSource *queue.Distributor
-	phase          int
+	// Warm-up programs of the focus areas in snapshot mode (nil if there are none).
+	snapshotWarmups *manager.SnapshotWarmups
+	// Sources of the VMs that run warm-up programs, keyed by the focus area index.
+	snapshotAreaSources map[int]*queue.Distributor
+	phase               int
 
 	disabledHashes   map[string]struct{}
 	newRepros        [][]byte
@@ -279,9 +289,9 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 		corpusPreload:      make(chan []fuzzer.Candidate),
 		target:             cfg.Target,
 		sysTarget:          cfg.SysTarget,
-		reporter:           reporter,
 		crashStore:         manager.NewCrashStore(cfg),
 		crashTypes:         make(map[string]bool),
+		memoryDumps:        make(map[string]bool),
 		disabledHashes:     make(map[string]struct{}),
 		memoryLeakFrames:   make(map[string]bool),
 		dataRaceFrames:     make(map[string]bool),
@@ -291,14 +301,19 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 		saturatedCalls:     make(map[string]bool),
 		reportGenerator:    manager.ReportGeneratorCache(cfg),
 	}
+	mgr.reporter.Store(reporter)
+	// Reloads are compared against the config as it was loaded, not the adjusted one below.
+	loadedCfg := *cfg
+	mgr.liveCfg.Store(&loadedCfg)
 	if *flagDebug {
 		mgr.cfg.Procs = 1
 	}
 	mgr.http = &manager.HTTPServer{
 		// Note that if cfg.HTTP == "", we don't start the server.
-		Cfg:        cfg,
-		StartTime:  time.Now(),
-		CrashStore: mgr.crashStore,
+		Cfg:          cfg,
+		StartTime:    time.Now(),
+		CrashStore:   mgr.crashStore,
+		ReloadConfig: mgr.reloadConfig,
 	}
 
 	mgr.initStats()
@@ -361,6 +376,11 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 	go mgr.heartbeatLoop()
 	if mgr.mode != ModeSmokeTest {
 		osutil.HandleInterrupts(vm.Shutdown)
+		osutil.HandleHangups(func() {
+			if _, err := mgr.reloadConfig(); err != nil {
+				log.Errorf("failed to reload config: %v", err)
+			}
+		})
 	}
 	if mgr.vmPool == nil {
 		log.Logf(0, "no VMs started (type=none)")
@@ -370,11 +390,29 @@ func RunManager(mode *Mode, cfg *mgrconfig.Config) {
 		return
 	}
 	mgr.pool = vm.NewDispatcher(mgr.vmPool, mgr.fuzzerInstance)
+	mgr.pool.SetQuarantine(mgr.cfg.VMQuarantine)
 	mgr.http.Pool = mgr.pool
+	mgr.http.Console = mgr.vmPool.Console()
+	if as := mgr.cfg.Autoscale; as != nil {
+		maxVMs := as.MaxVMs
+		if maxVMs == 0 {
+			maxVMs = mgr.vmPool.Count()
+		}
+		mgr.pool.SetScaling(&dispatcher.LoadPolicy{
+			MaxCPUPressure:     as.MaxCPUPressure,
+			MaxMemPressure:     as.MaxMemPressure,
+			MinCPUPressure:     as.MinCPUPressure,
+			MaxBootFailureRate: as.MaxBootFailureRate,
+		}, as.MinVMs, maxVMs)
+		go mgr.pool.ScaleLoop(ctx, time.Duration(as.Interval)*time.Second)
+	}
 	reproVMs := max(0, mgr.vmPool.Count()-mgr.cfg.FuzzingVMs)
 	mgr.reproLoop = manager.NewReproLoop(mgr, reproVMs, mgr.cfg.DashboardOnlyRepro)
 	mgr.http.ReproLoop = mgr.reproLoop
 	mgr.http.TogglePause = mgr.pool.TogglePause
+	if !mgr.cfg.DashboardOnlyRepro {
+		go mgr.reproRecheckLoop(ctx)
+	}
 
 	if mgr.cfg.HTTP != "" {
 		go func() {
@@ -415,6 +453,44 @@ func (mgr *Manager) heartbeatLoop() {
 	}
 }
 
+// reloadConfig applies the config fields that may change at runtime
+// and reports the ones that require a restart.
+func (mgr *Manager) reloadConfig() (*mgrconfig.ReloadResult, error) {
+	data, err := os.ReadFile(*flagConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	mgr.reloadMu.Lock()
+	defer mgr.reloadMu.Unlock()
+	// Validate on a copy first, so that a bad suppression regexp does not leave
+	// the config half-updated. The current config is never modified since it's read without locks.
+	newCfg := *mgr.runtimeCfg()
+	res, err := newCfg.Reload(data)
+	if err != nil {
+		return nil, err
+	}
+	if res.Changed("suppressions") || res.Changed("ignores") || res.Changed("interests") {
+		reporter, err := report.NewReporter(&newCfg)
+		if err != nil {
+			return nil, err
+		}
+		mgr.reporter.Store(reporter)
+	}
+	mgr.liveCfg.Store(&newCfg)
+	mgr.http.ReloadedCfg.Store(&newCfg)
+	if newCfg.Reproduce && mgr.fuzzer.Load() != nil {
+		mgr.startDashboardReproTasks()
+	}
+	log.Logf(0, "reloaded config: applied %q, require restart %q", res.Applied, res.Restart)
+	return res, nil
+}
+
+// runtimeCfg returns the config with the latest values of the fields that may be reloaded at runtime.
+// The returned config must not be modified.
+func (mgr *Manager) runtimeCfg() *mgrconfig.Config {
+	return mgr.liveCfg.Load()
+}
+
 func (mgr *Manager) initBench() {
 	f, err := os.OpenFile(*flagBench, os.O_WRONLY|os.O_CREATE|os.O_EXCL, osutil.DefaultFilePerm)
 	if err != nil {
@@ -454,7 +530,7 @@ func (mgr *Manager) processFuzzingResults(ctx context.Context) {
 			return
 		case crash := <-mgr.crashes:
 			needRepro := mgr.saveCrash(crash)
-			if mgr.cfg.Reproduce && needRepro {
+			if mgr.runtimeCfg().Reproduce && needRepro {
 				mgr.reproLoop.Enqueue(crash)
 			}
 		case err := <-mgr.pool.BootErrors:
@@ -474,10 +550,11 @@ func (mgr *Manager) convertBootError(err error) *manager.Crash {
 	var bootErr vm.BootErrorer
 	if errors.As(err, &bootErr) {
 		title, output := bootErr.BootError()
-		rep := mgr.reporter.Parse(output)
+		reporter := mgr.reporter.Load()
+		rep := reporter.Parse(output)
 		if rep != nil && rep.Type == crash_pkg.UnexpectedReboot {
 			// Avoid detecting any boot crash as "unexpected kernel reboot".
-			rep = mgr.reporter.ParseFrom(output, rep.SkipPos)
+			rep = reporter.ParseFrom(output, rep.SkipPos)
 		}
 		if rep == nil {
 			rep = &report.Report{
@@ -515,10 +592,13 @@ func reportReproError(err error) {
 }
 
 func (mgr *Manager) RunRepro(ctx context.Context, crash *manager.Crash) *manager.ReproResult {
+	if crash.Recheck {
+		return mgr.recheckRepro(ctx, crash)
+	}
 	res, stats, err := repro.Run(ctx, crash.Output, repro.Environment{
 		Config:   mgr.cfg,
 		Features: mgr.enabledFeatures,
-		Reporter: mgr.reporter,
+		Reporter: mgr.reporter.Load(),
 		Pool:     mgr.pool,
 	})
 	ret := &manager.ReproResult{
@@ -530,7 +610,7 @@ func (mgr *Manager) RunRepro(ctx context.Context, crash *manager.Crash) *manager
 	if err == nil && res != nil && mgr.cfg.StraceBin != "" {
 		const straceAttempts = 2
 		for i := 1; i <= straceAttempts; i++ {
-			strace := repro.RunStrace(res, mgr.cfg, mgr.reporter, mgr.pool)
+			strace := repro.RunStrace(res, mgr.cfg, mgr.reporter.Load(), mgr.pool)
 			sameBug := strace.IsSameBug(res)
 			log.Logf(0, "strace run attempt %d/%d for '%s': same bug %v, error %v",
 				i, straceAttempts, res.Report.Title, sameBug, strace.Error)
@@ -542,6 +622,20 @@ func (mgr *Manager) RunRepro(ctx context.Context, crash *manager.Crash) *manager
 			}
 		}
 	}
+	if err == nil && res != nil && mgr.cfg.Ftrace != nil {
+		const ftraceAttempts = 2
+		for i := 1; i <= ftraceAttempts; i++ {
+			ftrace := repro.RunFtrace(res, mgr.cfg, mgr.reporter.Load(), mgr.pool)
+			sameBug := ftrace.IsSameBug(res)
+			log.Logf(0, "ftrace run attempt %d/%d for '%s': same bug %v, error %v",
+				i, ftraceAttempts, res.Report.Title, sameBug, ftrace.Error)
+			// Similarly to strace, a trace of a different bug is not useful.
+			if sameBug {
+				ret.Ftrace = ftrace
+				break
+			}
+		}
+	}
 
 	mgr.processRepro(ret)
 
@@ -632,11 +726,18 @@ func (mgr *Manager) fuzzerInstance(ctx context.Context, inst *vm.Instance, updIn
 		}
 		rep.MachineInfo = machineInfo
 	}
+	var infraErr vm.InfraErrorer
+	if err != nil && ctx.Err() == nil && errors.As(err, &infraErr) {
+		mgr.pool.ReportFailure(inst.Index(), dispatcher.InfraFailure)
+	} else if rep != nil && rep.Type == crash_pkg.LostConnection {
+		mgr.pool.ReportFailure(inst.Index(), dispatcher.LostConnection)
+	}
 	if err == nil && rep != nil {
 		mgr.crashes <- &manager.Crash{
 			InstanceIndex: inst.Index(),
 			Report:        rep,
 			TailReports:   reps[1:],
+			MemoryDump:    mgr.dumpMemory(inst, rep),
 		}
 	}
 	if err != nil {
@@ -644,6 +745,41 @@ func (mgr *Manager) fuzzerInstance(ctx context.Context, inst *vm.Instance, updIn
 	}
 }
 
+// dumpMemory saves the guest memory of the crashed VM if the VM type supports it.
+// Dumps are large and slow to take, so we do it only for the first crash with the title.
+func (mgr *Manager) dumpMemory(inst *vm.Instance, rep *report.Report) string {
+	if rep.Suppressed || rep.Corrupted || rep.Type == crash_pkg.LostConnection {
+		return ""
+	}
+	if mgr.dash != nil && (mgr.assetStorage == nil || rep.Type == crash_pkg.MemoryLeak) {
+		// The dump could not be stored anywhere (see saveCrash).
+		return ""
+	}
+	// The title is recorded only once the dump is saved, so that failed dumps are retried.
+	mgr.mu.Lock()
+	dumped := mgr.memoryDumps[rep.Title]
+	mgr.mu.Unlock()
+	if dumped || mgr.dash == nil && mgr.crashStore.HasMemoryDump(rep.Title) {
+		return ""
+	}
+	// The dump is consumed asynchronously, so each crash needs its own file.
+	f, err := os.CreateTemp(mgr.cfg.Workdir, "memory-dump-")
+	if err != nil {
+		log.Errorf("failed to create memory dump file: %v", err)
+		return ""
+	}
+	f.Close()
+	ok, err := inst.DumpMemory(f.Name())
+	if err != nil || !ok {
+		if err != nil {
+			log.Logf(0, "VM %v: failed to dump memory: %v", inst.Index(), err)
+		}
+		os.Remove(f.Name())
+		return ""
+	}
+	return f.Name()
+}
+
 func (mgr *Manager) runInstanceInner(ctx context.Context, inst *vm.Instance, opts ...func(*vm.RunOptions),
 ) ([]*report.Report, []byte, error) {
 	fwdAddr, err := inst.Forward(mgr.serv.Port())
@@ -671,7 +807,7 @@ func (mgr *Manager) runInstanceInner(ctx context.Context, inst *vm.Instance, opt
 	cmd := fmt.Sprintf("%v runner %v %v %v", executorBin, inst.Index(), host, port)
 	ctxTimeout, cancel := context.WithTimeout(ctx, mgr.cfg.Timeouts.VMRunningTime)
 	defer cancel()
-	_, reps, err := inst.Run(ctxTimeout, mgr.reporter, cmd, opts...)
+	_, reps, err := inst.Run(ctxTimeout, mgr.reporter.Load(), cmd, opts...)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to run fuzzer: %w", err)
 	}
@@ -688,12 +824,13 @@ func (mgr *Manager) runInstanceInner(ctx context.Context, inst *vm.Instance, opt
 }
 
 func (mgr *Manager) emailCrash(crash *manager.Crash) {
-	if len(mgr.cfg.EmailAddrs) == 0 {
+	emailAddrs := mgr.runtimeCfg().EmailAddrs
+	if len(emailAddrs) == 0 {
 		return
 	}
 	args := []string{"-s", "syzkaller: " + crash.Title}
-	args = append(args, mgr.cfg.EmailAddrs...)
-	log.Logf(0, "sending email to %v", mgr.cfg.EmailAddrs)
+	args = append(args, emailAddrs...)
+	log.Logf(0, "sending email to %v", emailAddrs)
 
 	cmd := exec.Command("mailx", args...)
 	cmd.Stdin = bytes.NewReader(crash.Report.Report)
@@ -703,7 +840,11 @@ func (mgr *Manager) emailCrash(crash *manager.Crash) {
 }
 
 func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
-	if err := mgr.reporter.Symbolize(crash.Report); err != nil {
+	if crash.MemoryDump != "" {
+		// The dump is moved to the crash dir if it's stored locally.
+		defer os.Remove(crash.MemoryDump)
+	}
+	if err := mgr.reporter.Load().Symbolize(crash.Report); err != nil {
 		log.Errorf("failed to symbolize report: %v", err)
 	}
 	if crash.Type == crash_pkg.MemoryLeak {
@@ -767,27 +908,42 @@ func (mgr *Manager) saveCrash(crash *manager.Crash) bool {
 			MachineInfo: crash.MachineInfo,
 		}
 		setGuiltyFiles(dc, crash.Report)
+		if crash.MemoryDump != "" {
+			dc.Assets = mgr.uploadMemoryDump(crash.MemoryDump)
+			if len(dc.Assets) != 0 {
+				mgr.memoryDumpSaved(crash.Title)
+			}
+		}
 		resp, err := mgr.dash.ReportCrash(dc)
 		if err != nil {
 			log.Logf(0, "failed to report crash to dashboard: %v", err)
 		}
 		// Don't store the crash locally even if we failed to upload it.
 		// There is 0 chance that one will ever look in the crashes/ folder of those instances.
-		return mgr.cfg.Reproduce && resp.NeedRepro
+		return mgr.runtimeCfg().Reproduce && resp.NeedRepro
 	}
 	first, err := mgr.crashStore.SaveCrash(crash)
 	if err != nil {
 		log.Logf(0, "failed to save the cash: %v", err)
 		return false
 	}
+	if crash.MemoryDump != "" && mgr.crashStore.HasMemoryDump(crash.Title) {
+		mgr.memoryDumpSaved(crash.Title)
+	}
 	if first {
 		go mgr.emailCrash(crash)
 	}
 	return mgr.NeedRepro(crash)
 }
 
+func (mgr *Manager) memoryDumpSaved(title string) {
+	mgr.mu.Lock()
+	defer mgr.mu.Unlock()
+	mgr.memoryDumps[title] = true
+}
+
 func (mgr *Manager) needLocalRepro(crash *manager.Crash) bool {
-	if !mgr.cfg.Reproduce || crash.Corrupted || crash.Suppressed {
+	if !mgr.runtimeCfg().Reproduce || crash.Corrupted || crash.Suppressed {
 		return false
 	}
 	if mgr.crashStore.HasRepro(crash.Title) {
@@ -797,7 +953,7 @@ func (mgr *Manager) needLocalRepro(crash *manager.Crash) bool {
 }
 
 func (mgr *Manager) NeedRepro(crash *manager.Crash) bool {
-	if !mgr.cfg.Reproduce {
+	if !mgr.runtimeCfg().Reproduce {
 		return false
 	}
 	if crash.FromHub || crash.FromDashboard {
@@ -811,6 +967,9 @@ func (mgr *Manager) NeedRepro(crash *manager.Crash) bool {
 		// Leak checking is very slow, don't bother reproducing other crashes on leak instance.
 		return false
 	}
+	if crash.Recheck {
+		return true
+	}
 	if mgr.dashRepro == nil {
 		return mgr.needLocalRepro(crash)
 	}
@@ -929,6 +1088,10 @@ func (mgr *Manager) saveRepro(res *manager.ReproResult) {
 		} else {
 			// Don't store the crash locally, if we've successfully
 			// uploaded it to the dashboard. These will just eat disk space.
+			// But keep the reproducer itself, so that it can be rechecked later.
+			if err := mgr.crashStore.SaveReproForRecheck(repro, append([]byte(opts), progText...)); err != nil {
+				log.Logf(0, "%s", err)
+			}
 			return
 		}
 	}
@@ -942,6 +1105,24 @@ func (mgr *Manager) ResizeReproPool(size int) {
 	mgr.pool.ReserveForRun(size)
 }
 
+func (mgr *Manager) uploadMemoryDump(file string) []dashapi.NewAsset {
+	if mgr.assetStorage == nil {
+		return nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		log.Errorf("failed to open memory dump: %v", err)
+		return nil
+	}
+	defer f.Close()
+	asset, err := mgr.assetStorage.UploadCrashAsset(f, "memory.dump", dashapi.MemoryDump, nil)
+	if err != nil {
+		log.Logf(0, "failed to upload memory dump: %v", err)
+		return nil
+	}
+	return []dashapi.NewAsset{asset}
+}
+
 func (mgr *Manager) uploadReproAssets(repro *repro.Result) []dashapi.NewAsset {
 	if mgr.assetStorage == nil {
 		return nil
@@ -1197,14 +1378,25 @@ func (mgr *Manager) MachineChecked(features flatrpc.Feature,
 		go mgr.fuzzerLoop(fuzzerObj)
 		if mgr.dash != nil {
 			go mgr.dashboardReporter()
-			if mgr.cfg.Reproduce {
-				go mgr.dashboardReproTasks()
+			if mgr.runtimeCfg().Reproduce {
+				mgr.startDashboardReproTasks()
 			}
 		}
 		source := queue.DefaultOpts(fuzzerObj, opts)
 		if mgr.cfg.Snapshot {
+			warmups, err := manager.LoadSnapshotWarmups(mgr.cfg)
+			if err != nil {
+				return nil, err
+			}
+			mgr.snapshotWarmups = warmups
 			log.Logf(0, "restarting VMs for snapshot mode")
 			mgr.snapshotSource = queue.Distribute(source)
+			mgr.snapshotAreaSources = make(map[int]*queue.Distributor)
+			for _, warmup := range warmups.List() {
+				// VMs with a warm-up program fuzz only the programs of the corresponding focus area.
+				mgr.snapshotAreaSources[warmup.Index] = queue.Distribute(
+					queue.DefaultOpts(fuzzerObj.FocusAreaSource(warmup.Index, warmup.Prog), opts))
+			}
 			mgr.pool.SetDefault(mgr.snapshotInstance)
 			mgr.serv.Close()
 			mgr.serv = nil
@@ -1455,9 +1647,17 @@ func (mgr *Manager) dashboardReporter() {
 	}
 }
 
+// startDashboardReproTasks starts polling the dashboard for logs to reproduce,
+// it may be called again when reproduction is enabled by a config reload.
+func (mgr *Manager) startDashboardReproTasks() {
+	if mgr.dash != nil && mgr.dashReproTasks.CompareAndSwap(false, true) {
+		go mgr.dashboardReproTasks()
+	}
+}
+
 func (mgr *Manager) dashboardReproTasks() {
 	for range time.NewTicker(20 * time.Minute).C {
-		if !mgr.reproLoop.CanReproMore() {
+		if !mgr.runtimeCfg().Reproduce || !mgr.reproLoop.CanReproMore() {
 			// We don't need reproducers at the moment.
 			continue
 		}
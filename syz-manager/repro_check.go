@@ -0,0 +1,112 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/syzkaller/dashboard/dashapi"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm"
+	"github.com/google/syzkaller/vm/dispatcher"
+)
+
+const (
+	// How often we consider scheduling a reproducer recheck.
+	reproRecheckInterval = 10 * time.Minute
+	// How often each reproducer is rechecked on the same kernel.
+	reproRecheckPeriod = 24 * time.Hour
+)
+
+// reproRecheckLoop periodically re-runs stored reproducers on idle repro VMs,
+// so that we notice reproducers that are flaky or that stopped working after a kernel update.
+func (mgr *Manager) reproRecheckLoop(ctx context.Context) {
+	ticker := time.NewTicker(reproRecheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		if !mgr.runtimeCfg().Reproduce || !mgr.reproLoop.Empty() {
+			continue
+		}
+		title, ok := mgr.crashStore.ReproToRecheck(mgr.cfg.Tag, reproRecheckPeriod)
+		if !ok {
+			continue
+		}
+		mgr.reproLoop.Enqueue(&manager.Crash{
+			Recheck: true,
+			Report:  &report.Report{Title: title},
+		})
+	}
+}
+
+func (mgr *Manager) recheckRepro(ctx context.Context, crash *manager.Crash) *manager.ReproResult {
+	ret := &manager.ReproResult{Crash: crash}
+	progData, opts, err := mgr.crashStore.ReproProg(crash.Title)
+	if err != nil {
+		ret.Err = err
+		log.Errorf("failed to load the reproducer for '%v': %v", crash.Title, err)
+		return ret
+	}
+	var res *instance.RunResult
+	runErr := mgr.pool.Run(ctx, func(ctx context.Context, inst *vm.Instance, updInfo dispatcher.UpdateInfo) {
+		updInfo(func(info *dispatcher.Info) {
+			info.Status = fmt.Sprintf("rechecking repro of %v", crash.Title)
+		})
+		var execProg *instance.ExecProgInstance
+		execProg, err = instance.SetupExecProg(inst, mgr.cfg, mgr.reporter.Load(), nil)
+		if err != nil {
+			return
+		}
+		res, err = execProg.RunSyzProg(instance.ExecParams{
+			SyzProg:  progData,
+			Opts:     opts,
+			Duration: mgr.cfg.Timeouts.NoOutputRunningTime,
+		})
+	})
+	if runErr != nil {
+		err = runErr
+	}
+	if err != nil {
+		// Infrastructure problems say nothing about the reproducer.
+		ret.Err = err
+		log.Logf(0, "failed to recheck the reproducer for '%v': %v", crash.Title, err)
+		return ret
+	}
+	check := manager.ReproCheck{
+		Time: time.Now(),
+		Tag:  mgr.cfg.Tag,
+	}
+	if res.Report != nil {
+		check.Title = res.Report.Title
+		check.Reproduced = res.Report.Title == crash.Title
+	}
+	status, err := mgr.crashStore.SaveReproCheck(crash.Title, check)
+	if err != nil {
+		log.Errorf("failed to save the repro check: %v", err)
+		return ret
+	}
+	log.Logf(0, "rechecked the reproducer for '%v': reproduced=%v, got '%v', status %v",
+		crash.Title, check.Reproduced, check.Title, status)
+	if mgr.dash != nil {
+		err := mgr.dash.ReportReproCheck(&dashapi.ReproCheck{
+			BuildID:    mgr.cfg.Tag,
+			Title:      crash.Title,
+			Reproduced: check.Reproduced,
+			Status:     string(status),
+		})
+		if err != nil {
+			log.Logf(0, "failed to report the repro check: %v", err)
+		}
+	}
+	return ret
+}
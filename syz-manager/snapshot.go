@@ -15,6 +15,7 @@ import (
 	"github.com/google/syzkaller/pkg/fuzzer/queue"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/prog"
 	"github.com/google/syzkaller/vm"
 	"github.com/google/syzkaller/vm/dispatcher"
 )
@@ -43,18 +44,24 @@ func (mgr *Manager) snapshotLoop(ctx context.Context, inst *vm.Instance) error {
 	cmd := fmt.Sprintf("nohup %v exec snapshot 1>/dev/null 2>/dev/kmsg </dev/null &", executor)
 	ctxTimeout, cancel := context.WithTimeout(ctx, time.Hour)
 	defer cancel()
-	if _, _, err := inst.Run(ctxTimeout, mgr.reporter, cmd); err != nil {
+	if _, _, err := inst.Run(ctxTimeout, mgr.reporter.Load(), cmd); err != nil {
 		return err
 	}
 
+	source := mgr.snapshotSource
+	warmup := mgr.snapshotWarmups.Pick(inst.Index(), mgr.vmPool.Count())
+	if warmup != nil {
+		log.Logf(1, "VM %v: using snapshot warm-up program of focus area %v", inst.Index(), warmup.Area)
+		source = mgr.snapshotAreaSources[warmup.Index]
+	}
 	builder := flatbuffers.NewBuilder(0)
 	var envFlags flatrpc.ExecEnv
 	for first := true; ctx.Err() == nil; first = false {
 		mgr.servStats.StatExecs.Add(1)
-		req := mgr.snapshotSource.Next(inst.Index())
+		req := source.Next(inst.Index())
 		if first {
 			envFlags = req.ExecOpts.EnvFlags
-			if err := mgr.snapshotSetup(inst, builder, envFlags); err != nil {
+			if err := mgr.snapshotSetup(inst, builder, envFlags, warmup); err != nil {
 				req.Done(&queue.Result{Status: queue.Crashed})
 				return err
 			}
@@ -64,17 +71,24 @@ func (mgr *Manager) snapshotLoop(ctx context.Context, inst *vm.Instance) error {
 				envFlags, req.ExecOpts.EnvFlags))
 		}
 
-		res, output, err := mgr.snapshotRun(inst, builder, req)
+		p := req.Prog
+		if warmup != nil {
+			if p, err = warmup.WithWarmup(req.Prog); err != nil {
+				req.Done(&queue.Result{Status: queue.ExecFailure, Err: err})
+				continue
+			}
+		}
+		res, output, err := mgr.snapshotRun(inst, builder, req, p, warmup)
 		if err != nil {
 			req.Done(&queue.Result{Status: queue.Crashed})
 			return err
 		}
 
-		if mgr.reporter.ContainsCrash(output) {
+		if reporter := mgr.reporter.Load(); reporter.ContainsCrash(output) {
 			res.Status = queue.Crashed
-			rep := mgr.reporter.Parse(output)
+			rep := reporter.Parse(output)
 			buf := new(bytes.Buffer)
-			fmt.Fprintf(buf, "program:\n%s\n", req.Prog.Serialize())
+			fmt.Fprintf(buf, "program:\n%s\n", p.Serialize())
 			buf.Write(rep.Output)
 			rep.Output = buf.Bytes()
 			mgr.crashes <- &manager.Crash{Report: rep}
@@ -85,7 +99,8 @@ func (mgr *Manager) snapshotLoop(ctx context.Context, inst *vm.Instance) error {
 	return nil
 }
 
-func (mgr *Manager) snapshotSetup(inst *vm.Instance, builder *flatbuffers.Builder, env flatrpc.ExecEnv) error {
+func (mgr *Manager) snapshotSetup(inst *vm.Instance, builder *flatbuffers.Builder, env flatrpc.ExecEnv,
+	warmup *manager.SnapshotWarmup) error {
 	msg := flatrpc.SnapshotHandshakeT{
 		CoverEdges:       mgr.cfg.Experimental.CoverEdges,
 		Kernel64Bit:      mgr.cfg.SysTarget.PtrSize == 8,
@@ -96,14 +111,30 @@ func (mgr *Manager) snapshotSetup(inst *vm.Instance, builder *flatbuffers.Builde
 		EnvFlags:         env,
 		SandboxArg:       mgr.cfg.SandboxArg,
 	}
+	if warmup != nil {
+		data, err := warmup.Serialize(warmup.Prog)
+		if err != nil {
+			return fmt.Errorf("failed to serialize snapshot warm-up program: %w", err)
+		}
+		msg.Warmup = data
+	}
 	builder.Reset()
 	builder.Finish(msg.Pack(builder))
 	return inst.SetupSnapshot(builder.FinishedBytes())
 }
 
-func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder, req *queue.Request) (
-	*queue.Result, []byte, error) {
-	progData, err := req.Prog.SerializeForExec()
+// snapshotRun executes p, which is either req.Prog or req.Prog with the snapshot warm-up calls prepended.
+// The warm-up calls are not executed again, results are returned only for calls of req.Prog.
+func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder, req *queue.Request,
+	p *prog.Prog, warmup *manager.SnapshotWarmup) (*queue.Result, []byte, error) {
+	skip := len(p.Calls) - len(req.Prog.Calls)
+	var progData []byte
+	var err error
+	if warmup != nil {
+		progData, err = warmup.Serialize(p)
+	} else {
+		progData, err = p.SerializeForExec()
+	}
 	if err != nil {
 		queue.StatExecBufferTooSmall.Add(1)
 		return &queue.Result{
@@ -113,14 +144,14 @@ func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder,
 	}
 	msg := flatrpc.SnapshotRequestT{
 		ExecFlags: req.ExecOpts.ExecFlags,
-		NumCalls:  int32(len(req.Prog.Calls)),
+		NumCalls:  int32(len(p.Calls)),
 		ProgData:  progData,
 	}
 	for _, call := range req.ReturnAllSignal {
 		if call < 0 {
 			msg.AllExtraSignal = true
 		} else {
-			msg.AllCallSignal |= 1 << call
+			msg.AllCallSignal |= 1 << (skip + call)
 		}
 	}
 	builder.Reset()
@@ -136,12 +167,12 @@ func (mgr *Manager) snapshotRun(inst *vm.Instance, builder *flatbuffers.Builder,
 	res := parseExecResult(resData)
 	if res.Info != nil {
 		res.Info.Elapsed = uint64(elapsed)
-		for len(res.Info.Calls) < len(req.Prog.Calls) {
+		for len(res.Info.Calls) < len(p.Calls) {
 			res.Info.Calls = append(res.Info.Calls, &flatrpc.CallInfo{
 				Error: 999,
 			})
 		}
-		res.Info.Calls = res.Info.Calls[:len(req.Prog.Calls)]
+		res.Info.Calls = res.Info.Calls[skip:len(p.Calls)]
 		if len(res.Info.ExtraRaw) != 0 {
 			res.Info.Extra = res.Info.ExtraRaw[0]
 			for _, info := range res.Info.ExtraRaw[1:] {
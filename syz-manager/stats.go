@@ -50,6 +50,17 @@ func (mgr *Manager) initStats() {
 			return fmt.Sprintf("%v sec", v)
 		})
 
+	if mgr.cfg.Autoscale != nil {
+		stat.New("active VMs", "Number of VMs allowed to run by the autoscaling policy",
+			stat.Graph("fuzzing VMs"), stat.Link("/vms"),
+			func() int {
+				if mgr.pool == nil {
+					return 0
+				}
+				return mgr.pool.Active()
+			})
+	}
+
 	stat.New("heap", "Process heap size (bytes)", stat.Graph("memory"),
 		func() int {
 			var ms runtime.MemStats
@@ -0,0 +1,110 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-console replays console output recorded by the manager (see console_records config param)
+// through the crash reporter and prints the detected crashes along with the time
+// when they appeared in the output. Usage:
+//
+//	syz-console -config=manager.cfg [-print] workdir/console/vm-0/123.gz ...
+//
+// With -print the whole recorded output is printed with timestamps.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm"
+)
+
+var (
+	flagConfig = flag.String("config", "", "manager configuration file")
+	flagPrint  = flag.Bool("print", false, "print the recorded output")
+)
+
+func main() {
+	flag.Parse()
+	if *flagConfig == "" || flag.NArg() == 0 {
+		flag.PrintDefaults()
+		log.Fatalf("usage: syz-console -config=manager.cfg record.gz...")
+	}
+	cfg, err := mgrconfig.LoadFile(*flagConfig)
+	if err != nil {
+		log.Fatalf("%v: %v", *flagConfig, err)
+	}
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	for _, file := range flag.Args() {
+		if err := replay(reporter, file); err != nil {
+			log.Fatalf("%v: %v", file, err)
+		}
+	}
+}
+
+func replay(reporter *report.Reporter, file string) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	rec, chunks, err := vm.ReadConsoleRecord(f)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%v: started at %v, %v chunks\n%v\n", file, rec.Start.Format(time.DateTime),
+		len(chunks), rec.Command)
+	if *flagPrint {
+		if err := vm.FormatConsoleRecord(os.Stdout, chunks); err != nil {
+			return err
+		}
+		fmt.Printf("\n")
+	}
+	// Offsets of the chunks in the output to map reports back to the time.
+	var output []byte
+	var offsets []int
+	detected := time.Duration(-1)
+	for _, chunk := range chunks {
+		offsets = append(offsets, len(output))
+		output = append(output, chunk.Data...)
+		// Step back a bit to not miss crash messages split between chunks.
+		if detected < 0 && reporter.ContainsCrash(output[max(offsets[len(offsets)-1]-256, 0):]) {
+			detected = chunk.Time
+		}
+	}
+	timeAt := func(pos int) time.Duration {
+		idx := sort.Search(len(offsets), func(i int) bool {
+			return offsets[i] > pos
+		})
+		return chunks[max(idx-1, 0)].Time
+	}
+	if detected < 0 {
+		fmt.Printf("no crashes detected\n\n")
+		return nil
+	}
+	fmt.Printf("the first crash is detected at %.3fs\n", detected.Seconds())
+	for pos := 0; ; {
+		rep := reporter.ParseFrom(output, pos)
+		if rep == nil {
+			break
+		}
+		pos = rep.SkipPos
+		flags := ""
+		if rep.Corrupted {
+			flags += " [corrupted]"
+		}
+		if rep.Suppressed {
+			flags += " [suppressed]"
+		}
+		fmt.Printf("[%10.3f] %v%v\n", timeAt(rep.StartPos).Seconds(), rep.Title, flags)
+	}
+	fmt.Printf("\n")
+	return nil
+}
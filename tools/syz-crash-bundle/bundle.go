@@ -0,0 +1,142 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-crash-bundle exports a crash from the manager workdir as a self-contained archive
+// and replays bundles produced elsewhere. Usage:
+//
+//	syz-crash-bundle -config=manager.cfg -export=<crash id> [-output=crash.tar.gz]
+//	syz-crash-bundle -config=manager.cfg -import=crash.tar.gz
+//
+// The crash id is the name of the dir in workdir/crashes.
+// On import, the bundle is unpacked next to the archive and its syz reproducer is run
+// with syz-execprog on the VMs described by the manager config.
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/symbolizer"
+	"github.com/google/syzkaller/vm"
+)
+
+var (
+	flagConfig   = flag.String("config", "", "manager configuration file")
+	flagExport   = flag.String("export", "", "id of the crash to export")
+	flagOutput   = flag.String("output", "", "output bundle file (crash-<id>.tar.gz by default)")
+	flagImport   = flag.String("import", "", "bundle to replay")
+	flagDuration = flag.Duration("duration", 0, "how long to run the reproducer (VM running time by default)")
+	flagDebug    = flag.Bool("debug", false, "dump all VM output to console")
+)
+
+func main() {
+	flag.Parse()
+	if *flagConfig == "" || (*flagExport == "") == (*flagImport == "") {
+		flag.PrintDefaults()
+		log.Fatalf("usage: syz-crash-bundle -config=manager.cfg -export=<crash id>|-import=crash.tar.gz")
+	}
+	cfg, err := mgrconfig.LoadFile(*flagConfig)
+	if err != nil {
+		log.Fatalf("%v: %v", *flagConfig, err)
+	}
+	if *flagExport != "" {
+		exportBundle(cfg, *flagExport)
+	} else {
+		importBundle(cfg, *flagImport)
+	}
+}
+
+func exportBundle(cfg *mgrconfig.Config, id string) {
+	output := *flagOutput
+	if output == "" {
+		output = "crash-" + id + ".tar.gz"
+	}
+	f, err := os.Create(output)
+	if err != nil {
+		log.Fatalf("failed to create the bundle: %v", err)
+	}
+	defer f.Close()
+	if err := manager.ReadCrashStore(cfg.Workdir).ExportBundle(id, cfg, f); err != nil {
+		os.Remove(output)
+		log.Fatalf("%v", err)
+	}
+	log.Logf(0, "exported %v", output)
+}
+
+func importBundle(cfg *mgrconfig.Config, file string) {
+	f, err := os.Open(file)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	dir := strings.TrimSuffix(strings.TrimSuffix(file, ".gz"), ".tar")
+	if dir == file {
+		dir += ".extracted"
+	}
+	bundle, err := manager.ExtractBundle(f, dir)
+	f.Close()
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	log.Logf(0, "extracted %q into %v", bundle.Title, dir)
+	if bundle.OS != cfg.TargetOS || bundle.Arch != cfg.TargetArch {
+		log.Fatalf("the bundle is for %v/%v, but the config targets %v/%v",
+			bundle.OS, bundle.Arch, cfg.TargetOS, cfg.TargetArch)
+	}
+	if bundle.VmlinuxBuildID != "" && cfg.KernelObj != "" {
+		buildID, err := symbolizer.ReadBuildID(filepath.Join(cfg.KernelObj, cfg.SysTarget.KernelObject))
+		if err == nil && buildID != bundle.VmlinuxBuildID {
+			log.Logf(0, "warning: the kernel build ID %v does not match the bundle build ID %v",
+				buildID, bundle.VmlinuxBuildID)
+		}
+	}
+	progFile, opts, err := manager.BundleRepro(dir)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	duration := *flagDuration
+	if duration == 0 {
+		duration = cfg.Timeouts.VMRunningTime
+	}
+	vmPool, err := vm.Create(cfg, *flagDebug)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer vmPool.Close()
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	osutil.HandleInterrupts(vm.Shutdown)
+
+	inst, err := instance.CreateExecProgInstance(vmPool, 0, cfg, reporter, nil)
+	if err != nil {
+		log.Fatalf("failed to set up instance: %v", err)
+	}
+	defer inst.VMInstance.Close()
+	res, err := inst.RunSyzProgFile(progFile, duration, opts, instance.SyzExitConditions)
+	if err != nil {
+		log.Fatalf("failed to execute the reproducer: %v", err)
+	}
+	logFile := filepath.Join(dir, "replay.log")
+	if err := osutil.WriteFile(logFile, res.Output); err != nil {
+		log.Fatalf("%v", err)
+	}
+	if res.Report == nil {
+		log.Logf(0, "the reproducer did not crash the kernel in %v, see %v",
+			duration.Round(time.Second), logFile)
+		return
+	}
+	log.Logf(0, "reproduced: %v", res.Report.Title)
+	if res.Report.Title != bundle.Title {
+		log.Logf(0, "note: the bundle was exported for %q", bundle.Title)
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/google/syzkaller/pkg/csource"
 	"github.com/google/syzkaller/pkg/db"
 	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/ftrace"
 	"github.com/google/syzkaller/pkg/fuzzer/queue"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
@@ -54,6 +55,8 @@ var (
 	flagSlowdown   = flag.Int("slowdown", 1, "execution slowdown caused by emulation/instrumentation")
 	flagUnsafe     = flag.Bool("unsafe", false, "use unsafe program deserialization mode")
 	flagGlob       = flag.String("glob", "", "run glob expansion request")
+	flagConcurrent = flag.Int("concurrent", 0, "split programs at this call and execute the parts "+
+		"concurrently on different procs (see csource.Options.Concurrent)")
 
 	// The in the stress mode resembles simple unguided fuzzer.
 	// This mode can be used as an intermediate step when porting syzkaller to a new OS,
@@ -67,6 +70,13 @@ var (
 
 	flagGDB = flag.Bool("gdb", false, "start executor under gdb")
 
+	// Kernel tracing (see docs/ftrace.md), the same as the ftrace config param in syz-manager.
+	flagFtrace = flag.String("ftrace", "", "run programs with kernel tracing enabled "+
+		"and save the trace to the file")
+	flagFtraceEvents    = flag.String("ftrace_events", "", "comma-separated list of trace events for -ftrace")
+	flagFtraceFunctions = flag.String("ftrace_functions", "", "comma-separated list of functions to trace for -ftrace")
+	flagFtraceBufferKB  = flag.Int("ftrace_buffer_kb", 0, "per-CPU trace buffer size in KB for -ftrace")
+
 	// The following flag is only kept to let syzkaller remain compatible with older execprog versions.
 	// In order to test incoming patches or perform bug bisection, syz-ci must use the exact syzkaller
 	// version that detected the bug (as descriptions and syntax could've already been changed), and
@@ -108,11 +118,8 @@ func main() {
 
 	var requestedSyscalls []int
 	if *flagStress {
-		syscallList := strings.Split(*flagSyscalls, ",")
-		if *flagSyscalls == "" {
-			syscallList = nil
-		}
-		requestedSyscalls, err = mgrconfig.ParseEnabledSyscalls(target, syscallList, nil, mgrconfig.AnyDescriptions)
+		requestedSyscalls, err = mgrconfig.ParseEnabledSyscalls(target, splitList(*flagSyscalls), nil,
+			mgrconfig.AnyDescriptions)
 		if err != nil {
 			tool.Failf("failed to parse enabled syscalls: %v", err)
 		}
@@ -143,19 +150,28 @@ func main() {
 		flag.Usage()
 		os.Exit(1)
 	}
+	procs := *flagProcs
+	if *flagConcurrent != 0 {
+		progs = splitConcurrent(progs, *flagConcurrent)
+		procs = max(procs, 2)
+		// Executor starts the parts simultaneously on different procs.
+		exec |= flatrpc.ExecFlagBarrier
+	}
 	rpcCtx, done := context.WithCancel(context.Background())
 	ctx := &Context{
-		target:    target,
-		done:      done,
-		progs:     progs,
-		globs:     strings.Split(*flagGlob, ":"),
-		rs:        rand.NewSource(time.Now().UnixNano()),
-		coverFile: *flagCoverFile,
-		output:    *flagOutput,
-		signal:    *flagSignal,
-		hints:     *flagHints,
-		stress:    *flagStress,
-		repeat:    *flagRepeat,
+		target:     target,
+		done:       done,
+		progs:      progs,
+		globs:      strings.Split(*flagGlob, ":"),
+		rs:         rand.NewSource(time.Now().UnixNano()),
+		coverFile:  *flagCoverFile,
+		output:     *flagOutput,
+		signal:     *flagSignal,
+		hints:      *flagHints,
+		stress:     *flagStress,
+		repeat:     *flagRepeat,
+		concurrent: *flagConcurrent != 0,
+		features:   featureFlags,
 		defaultOpts: flatrpc.ExecOpts{
 			EnvFlags:   env,
 			ExecFlags:  exec,
@@ -175,7 +191,7 @@ func main() {
 				Sandbox:    sandbox,
 				SandboxArg: int64(*flagSandboxArg),
 			},
-			Procs:    *flagProcs,
+			Procs:    procs,
 			Slowdown: *flagSlowdown,
 		},
 		Executor:         *flagExecutor,
@@ -184,9 +200,56 @@ func main() {
 		MachineChecked:   ctx.machineChecked,
 		OutputWriter:     os.Stderr,
 	}
+	ftraceCfg := setupFtrace(target)
 	if err := rpcserver.RunLocal(rpcCtx, cfg); err != nil {
 		tool.Fail(err)
 	}
+	if ftraceCfg != nil {
+		collectFtrace(ftraceCfg, *flagFtrace)
+	}
+}
+
+func setupFtrace(target *prog.Target) *mgrconfig.Ftrace {
+	if *flagFtrace == "" {
+		return nil
+	}
+	if target.OS != targets.Linux {
+		tool.Failf("ftrace is only supported on linux")
+	}
+	cfg := &mgrconfig.Ftrace{
+		Events:       splitList(*flagFtraceEvents),
+		Functions:    splitList(*flagFtraceFunctions),
+		BufferSizeKB: *flagFtraceBufferKB,
+	}
+	if err := mgrconfig.CompleteFtrace(cfg); err != nil {
+		tool.Fail(err)
+	}
+	if _, err := osutil.RunCmd(time.Minute, "", "sh", "-c", ftrace.SetupScript(cfg)); err != nil {
+		tool.Failf("failed to set up ftrace: %v", err)
+	}
+	return cfg
+}
+
+// collectFtrace saves the trace buffer. A failure here does not fail the whole run,
+// the programs have already been executed.
+func collectFtrace(cfg *mgrconfig.Ftrace, fileName string) {
+	trace, err := osutil.RunCmd(time.Minute, "", "sh", "-c", ftrace.CollectScript)
+	if err != nil {
+		log.Logf(0, "failed to collect ftrace: %v", err)
+		return
+	}
+	if err := osutil.WriteFile(fileName, append(ftrace.Header(cfg), trace...)); err != nil {
+		log.Logf(0, "failed to write ftrace output: %v", err)
+		return
+	}
+	log.Logf(0, "ftrace log saved to %v", fileName)
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
 }
 
 type Context struct {
@@ -205,6 +268,9 @@ type Context struct {
 	hints       bool
 	stress      bool
 	repeat      int
+	concurrent  bool
+	features    csource.Features
+	inflight    int
 	pos         int
 	completed   atomic.Uint64
 	resultIndex atomic.Int64
@@ -215,7 +281,7 @@ func (ctx *Context) machineChecked(features flatrpc.Feature, syscalls map[*prog.
 	if ctx.stress {
 		ctx.choiceTable = ctx.target.BuildChoiceTable(ctx.progs, syscalls)
 	}
-	ctx.defaultOpts.EnvFlags |= csource.FeaturesToFlags(features, nil)
+	ctx.defaultOpts.EnvFlags |= csource.FeaturesToFlags(features, ctx.features)
 	return queue.DefaultOpts(ctx, ctx.defaultOpts)
 }
 
@@ -290,6 +356,11 @@ func (ctx *Context) Done(req *queue.Request, res *queue.Result) bool {
 			ctx.dumpCoverage(res.Info)
 		}
 	}
+	if ctx.concurrent {
+		ctx.posMu.Lock()
+		ctx.inflight--
+		ctx.posMu.Unlock()
+	}
 	completed := int(ctx.completed.Add(1))
 	if ctx.repeat > 0 && completed >= len(ctx.progs)*ctx.repeat {
 		ctx.done()
@@ -376,6 +447,15 @@ func (ctx *Context) getProgramIndex() int {
 	if ctx.repeat > 0 && ctx.pos >= len(ctx.progs)*ctx.repeat {
 		return -1
 	}
+	if ctx.concurrent && ctx.pos%2 == 0 && ctx.inflight != 0 {
+		// Both parts of a program are handed out at the same time, and the executor waits
+		// for both of them to start (ExecFlagBarrier), so that they run concurrently.
+		// Don't start the next round until both parts of the previous one finish.
+		return -1
+	}
+	if ctx.concurrent {
+		ctx.inflight++
+	}
 	idx := ctx.pos % len(ctx.progs)
 	if idx == 0 && time.Since(ctx.lastPrint) > 5*time.Second {
 		log.Logf(0, "executed programs: %v", ctx.pos)
@@ -425,3 +505,16 @@ func loadPrograms(target *prog.Target, files []string) []*prog.Prog {
 	log.Logf(0, "parsed %v programs", len(progs))
 	return progs
 }
+
+// splitConcurrent splits each program into two parts at the given call (see csource.Options.Concurrent).
+func splitConcurrent(progs []*prog.Prog, split int) []*prog.Prog {
+	var parts []*prog.Prog
+	for _, p := range progs {
+		if split < 0 || split > len(p.Calls) {
+			log.Fatalf("concurrent split %v is out of range for a program with %v calls", split, len(p.Calls))
+		}
+		first, second := p.Split(split)
+		parts = append(parts, first, second)
+	}
+	return parts
+}
@@ -0,0 +1,207 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-portability checks where else an already found reproducer triggers the bug. Usage:
+//
+//	syz-portability -repro=workdir/crashes/<crash id> -configs=amd64.cfg,arm64.cfg,386.cfg \
+//		[-sandboxes=none,namespace] [-attempts=N]
+//
+// The reproducer is the syz reproducer stored in the crash dir (or in an unpacked crash bundle).
+// Each manager config describes one target configuration to check: another arch, a compat
+// 32-bit target (e.g. linux/amd64/386), another VM type or a kernel build with other sanitizers.
+// The reproducer is run on each config with each of the sandboxes (the original sandbox by default)
+// up to -attempts times, until the kernel crashes. The program is re-parsed for every target,
+// so the reproducers that use syscalls absent on the target are reported as not portable.
+// The results are printed and saved to repro.portability in the crash dir,
+// the syz-manager crash page shows them as well.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/vm"
+)
+
+var (
+	flagRepro     = flag.String("repro", "", "crash dir with the reproducer")
+	flagConfigs   = flag.String("configs", "", "comma-separated list of manager configs to check")
+	flagSandboxes = flag.String("sandboxes", "", "comma-separated list of sandboxes to check (original by default)")
+	flagAttempts  = flag.Int("attempts", 3, "max number of reproducer runs per config and sandbox")
+	flagDuration  = flag.Duration("duration", 0, "how long to run the reproducer (no output timeout by default)")
+	flagDebug     = flag.Bool("debug", false, "dump all VM output to console")
+)
+
+func main() {
+	flag.Parse()
+	if *flagRepro == "" || *flagConfigs == "" {
+		flag.PrintDefaults()
+		log.Fatalf("usage: syz-portability -repro=<crash dir> -configs=a.cfg,b.cfg")
+	}
+	progFile, opts, err := manager.BundleRepro(*flagRepro)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	progData, err := os.ReadFile(progFile)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	desc, err := os.ReadFile(filepath.Join(*flagRepro, "description"))
+	if err != nil {
+		log.Fatalf("failed to read the bug title: %v", err)
+	}
+	title := strings.TrimSpace(string(desc))
+	sandboxes := []string{opts.Sandbox}
+	if *flagSandboxes != "" {
+		sandboxes = strings.Split(*flagSandboxes, ",")
+	}
+	osutil.HandleInterrupts(vm.Shutdown)
+
+	var checks []manager.PortabilityCheck
+	for _, cfgFile := range strings.Split(*flagConfigs, ",") {
+		cfg, err := mgrconfig.LoadFile(cfgFile)
+		if err != nil {
+			log.Fatalf("%v: %v", cfgFile, err)
+		}
+		checks = append(checks, checkConfig(cfg, cfgFile, title, progData, opts, sandboxes)...)
+	}
+	if err := manager.SavePortability(*flagRepro, checks); err != nil {
+		log.Fatalf("failed to save the results: %v", err)
+	}
+	printChecks(checks)
+}
+
+func checkConfig(cfg *mgrconfig.Config, cfgFile, title string, progData []byte, opts csource.Options,
+	sandboxes []string) []manager.PortabilityCheck {
+	name := cfg.Name
+	if name == "" {
+		name = filepath.Base(cfgFile)
+	}
+	var checks []manager.PortabilityCheck
+	var runnable []int
+	p, progErr := cfg.Target.Deserialize(progData, prog.NonStrict)
+	for _, sandbox := range sandboxes {
+		check := manager.PortabilityCheck{
+			Time:    time.Now(),
+			Config:  name,
+			Target:  cfg.RawTarget,
+			VMType:  cfg.Type,
+			Sandbox: sandbox,
+		}
+		if progErr != nil {
+			check.Error = fmt.Sprintf("the program is not valid for the target: %v", progErr)
+		} else if err := sandboxOpts(opts, sandbox).Check(cfg.TargetOS); err != nil {
+			check.Error = fmt.Sprintf("the options are not valid for the target: %v", err)
+		} else {
+			runnable = append(runnable, len(checks))
+		}
+		checks = append(checks, check)
+	}
+	if len(runnable) == 0 {
+		return checks
+	}
+	log.Logf(0, "%v: checking the reproducer", name)
+	// A broken config should not prevent checking the rest of them.
+	failAll := func(err error) []manager.PortabilityCheck {
+		for _, idx := range runnable {
+			check := &checks[idx]
+			check.Error = err.Error()
+			log.Logf(0, "%v: sandbox %q: %v", name, check.Sandbox, checkResult(check))
+		}
+		return checks
+	}
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		return failAll(fmt.Errorf("failed to create reporter: %w", err))
+	}
+	vmPool, err := vm.Create(cfg, *flagDebug)
+	if err != nil {
+		return failAll(fmt.Errorf("failed to create VM pool: %w", err))
+	}
+	defer vmPool.Close()
+	duration := *flagDuration
+	if duration == 0 {
+		duration = cfg.Timeouts.NoOutputRunningTime
+	}
+	params := instance.ExecParams{
+		SyzProg:  p.Serialize(),
+		Duration: duration,
+	}
+	for _, idx := range runnable {
+		check := &checks[idx]
+		params.Opts = sandboxOpts(opts, check.Sandbox)
+		for check.Runs < *flagAttempts {
+			check.Runs++
+			rep, err := runOnce(cfg, vmPool, reporter, check.Runs, params)
+			if err != nil {
+				check.Error = err.Error()
+				break
+			}
+			if rep != nil {
+				check.Title = rep.Title
+				check.Reproduced = rep.Title == title
+				break
+			}
+		}
+		log.Logf(0, "%v: sandbox %q: %v", name, check.Sandbox, checkResult(check))
+	}
+	return checks
+}
+
+func runOnce(cfg *mgrconfig.Config, vmPool *vm.Pool, reporter *report.Reporter, attempt int,
+	params instance.ExecParams) (*report.Report, error) {
+	inst, err := instance.CreateExecProgInstance(vmPool, attempt%vmPool.Count(), cfg, reporter, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up instance: %w", err)
+	}
+	defer inst.VMInstance.Close()
+	res, err := inst.RunSyzProg(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute the reproducer: %w", err)
+	}
+	return res.Report, nil
+}
+
+func sandboxOpts(opts csource.Options, sandbox string) csource.Options {
+	if sandbox != opts.Sandbox {
+		opts.Sandbox = sandbox
+		opts.SandboxArg = 0
+	}
+	return opts
+}
+
+func checkResult(check *manager.PortabilityCheck) string {
+	switch {
+	case check.Error != "":
+		return "error: " + check.Error
+	case check.Reproduced:
+		return fmt.Sprintf("reproduced in %v runs", check.Runs)
+	case check.Title != "":
+		return fmt.Sprintf("crashed with another bug: %v", check.Title)
+	}
+	return fmt.Sprintf("no crash in %v runs", check.Runs)
+}
+
+func printChecks(checks []manager.PortabilityCheck) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "CONFIG\tTARGET\tVM\tSANDBOX\tRESULT\n")
+	for i := range checks {
+		check := &checks[i]
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\t%v\n", check.Config, check.Target, check.VMType,
+			check.Sandbox, checkResult(check))
+	}
+	w.Flush()
+}
@@ -0,0 +1,141 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-replay records reproducer runs with QEMU record/replay and replays the recordings
+// with the gdbstub attached. Usage:
+//
+//	syz-replay -config=manager.cfg -record=<crash id> [-attempts=N]
+//	syz-replay -replay=workdir/crashes/<crash id> [-gdb=1234]
+//
+// The crash id is the name of the dir in workdir/crashes, the crash must have a syz reproducer.
+// Recording runs the reproducer on a single qemu VM with record_replay enabled
+// until it crashes the kernel (up to -attempts times, since races may reproduce rarely).
+// The recording of the crashing run is saved as replay.bin/replay.json in the crash dir.
+// Replaying needs the same kernel and image files that were used for recording.
+// The replayed VM is stopped at start until gdb connects and continues it.
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm"
+	"github.com/google/syzkaller/vm/qemu"
+)
+
+var (
+	flagConfig   = flag.String("config", "", "manager configuration file")
+	flagRecord   = flag.String("record", "", "id of the crash to record")
+	flagAttempts = flag.Int("attempts", 10, "max number of recording attempts")
+	flagDuration = flag.Duration("duration", 0, "how long to run the reproducer (VM running time by default)")
+	flagReplay   = flag.String("replay", "", "dir with the recording to replay")
+	flagGDB      = flag.Int("gdb", 1234, "gdbstub port for replay")
+	flagDebug    = flag.Bool("debug", false, "dump all VM output to console")
+)
+
+func main() {
+	flag.Parse()
+	switch {
+	case *flagRecord != "" && *flagConfig != "" && *flagReplay == "":
+		cfg, err := mgrconfig.LoadFile(*flagConfig)
+		if err != nil {
+			log.Fatalf("%v: %v", *flagConfig, err)
+		}
+		record(cfg, *flagRecord)
+	case *flagReplay != "" && *flagRecord == "":
+		replay(*flagReplay)
+	default:
+		flag.PrintDefaults()
+		log.Fatalf("usage: syz-replay -config=manager.cfg -record=<crash id>|-replay=<dir>")
+	}
+}
+
+func record(cfg *mgrconfig.Config, id string) {
+	if cfg.Type != "qemu" {
+		log.Fatalf("recording is only supported for qemu VMs, the config uses %v", cfg.Type)
+	}
+	dir := filepath.Join(cfg.Workdir, "crashes", id)
+	progFile, opts, err := manager.BundleRepro(dir)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	cfg.Snapshot = false
+	cfg.VM, err = config.PatchJSON(cfg.VM, map[string]interface{}{
+		"count":         1,
+		"record_replay": true,
+	})
+	if err != nil {
+		log.Fatalf("failed to patch the VM config: %v", err)
+	}
+	duration := *flagDuration
+	if duration == 0 {
+		duration = cfg.Timeouts.VMRunningTime
+	}
+	vmPool, err := vm.Create(cfg, *flagDebug)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	defer vmPool.Close()
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	osutil.HandleInterrupts(vm.Shutdown)
+
+	for attempt := 1; attempt <= *flagAttempts; attempt++ {
+		log.Logf(0, "recording attempt %v/%v", attempt, *flagAttempts)
+		if recordOnce(cfg, vmPool, reporter, dir, progFile, opts, duration) {
+			return
+		}
+	}
+	log.Fatalf("the reproducer did not crash the kernel in %v attempts", *flagAttempts)
+}
+
+func recordOnce(cfg *mgrconfig.Config, vmPool *vm.Pool, reporter *report.Reporter, dir, progFile string,
+	opts csource.Options, duration time.Duration) bool {
+	inst, err := instance.CreateExecProgInstance(vmPool, 0, cfg, reporter, nil)
+	if err != nil {
+		log.Fatalf("failed to set up instance: %v", err)
+	}
+	defer inst.VMInstance.Close()
+	res, err := inst.RunSyzProgFile(progFile, duration, opts, instance.SyzExitConditions)
+	if err != nil {
+		log.Fatalf("failed to execute the reproducer: %v", err)
+	}
+	if res.Report == nil {
+		return false
+	}
+	log.Logf(0, "reproduced: %v", res.Report.Title)
+	if err := inst.VMInstance.SaveRecording(dir); err != nil {
+		log.Fatalf("failed to save the recording: %v", err)
+	}
+	if err := osutil.WriteFile(filepath.Join(dir, "replay.log"), res.Output); err != nil {
+		log.Fatalf("%v", err)
+	}
+	log.Logf(0, "saved the recording to %v, replay it with: syz-replay -replay=%v", dir, dir)
+	return true
+}
+
+func replay(dir string) {
+	rep, err := qemu.LoadReplay(dir)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	cmd := rep.Command(dir, *flagGDB)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	log.Logf(0, "replaying %v, attach with: gdb vmlinux -ex 'target remote :%v'", dir, *flagGDB)
+	if err := cmd.Run(); err != nil {
+		log.Fatalf("replay failed: %v", err)
+	}
+}
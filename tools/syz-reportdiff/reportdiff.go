@@ -0,0 +1,243 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-reportdiff checks how changes to the crash report parsing affect a corpus of console logs.
+// A changed title splits the existing dashboard bug, so parser changes need to be validated
+// against historical logs (e.g. workdir/crashes/*/log* files of a manager). Usage:
+//
+//	syz-reportdiff -config=old.cfg -config2=new.cfg logs...
+//	syz-reportdiff -config=manager.cfg -dump=base.json logs...  (on the old revision)
+//	syz-reportdiff -config=manager.cfg -base=base.json logs...  (on the new revision)
+//
+// Logs can be files or directories (traversed recursively). The tool prints logs where
+// the title, crash type, guilty frame or corruption status changed and exits with status 1
+// if there are any changes.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/tool"
+)
+
+var (
+	flagOS        = flag.String("os", "linux", "target os (if no config is given)")
+	flagArch      = flag.String("arch", "amd64", "target arch (if no config is given)")
+	flagConfig    = flag.String("config", "", "configuration file used to parse logs")
+	flagConfig2   = flag.String("config2", "", "second configuration file to compare with")
+	flagDump      = flag.String("dump", "", "save parsing results to this file")
+	flagBase      = flag.String("base", "", "compare with parsing results saved with -dump")
+	flagGlob      = flag.String("glob", "", "only parse files with base names matching the pattern (e.g. 'log*')")
+	flagSymbolize = flag.Bool("symbolize", false, "symbolize reports (needs kernel_obj in the config)")
+	flagParallel  = flag.Int("j", runtime.NumCPU(), "number of parallel threads")
+)
+
+// result is the part of the parsed report that affects bug deduplication on the dashboard.
+type result struct {
+	Title     string `json:"title"`
+	Type      string `json:"type,omitempty"`
+	Frame     string `json:"frame,omitempty"`
+	Corrupted bool   `json:"corrupted,omitempty"`
+}
+
+func main() {
+	flag.Parse()
+	modes := 0
+	for _, set := range []bool{*flagConfig2 != "", *flagDump != "", *flagBase != ""} {
+		if set {
+			modes++
+		}
+	}
+	if modes != 1 || len(flag.Args()) == 0 {
+		fmt.Fprintf(os.Stderr, "usage: syz-reportdiff [-config=manager.cfg] "+
+			"-config2=new.cfg|-dump=file|-base=file logs...\n")
+		flag.PrintDefaults()
+		os.Exit(1)
+	}
+	files, err := collectLogs(flag.Args(), *flagGlob)
+	if err != nil {
+		tool.Fail(err)
+	}
+	results := parseLogs(loadReporter(*flagConfig), files)
+	if *flagDump != "" {
+		if err := osutil.WriteJSON(*flagDump, results); err != nil {
+			tool.Fail(err)
+		}
+		fmt.Printf("saved results for %v logs\n", len(results))
+		return
+	}
+	var base map[string]*result
+	if *flagBase != "" {
+		base, err = osutil.ReadJSON[map[string]*result](*flagBase)
+		if err != nil {
+			tool.Fail(err)
+		}
+	} else {
+		base, results = results, parseLogs(loadReporter(*flagConfig2), files)
+	}
+	diffs := compareResults(base, results)
+	for _, diff := range diffs {
+		fmt.Printf("%v:\n", diff.File)
+		for _, change := range diff.Changes {
+			fmt.Printf("\t%v\n", change)
+		}
+	}
+	fmt.Printf("compared %v logs: %v changed\n", len(results), len(diffs))
+	if len(diffs) != 0 {
+		os.Exit(1)
+	}
+}
+
+func loadReporter(config string) *report.Reporter {
+	var cfg *mgrconfig.Config
+	var err error
+	if config != "" {
+		cfg, err = mgrconfig.LoadPartialFile(config)
+	} else {
+		cfg, err = mgrconfig.LoadPartialData([]byte(`{
+			"target": "` + *flagOS + "/" + *flagArch + `"
+		}`))
+	}
+	if err != nil {
+		tool.Fail(err)
+	}
+	cfg.CompleteKernelDirs()
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		tool.Failf("failed to create reporter: %v", err)
+	}
+	return reporter
+}
+
+func collectLogs(paths []string, glob string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		err := filepath.WalkDir(path, func(file string, entry fs.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			if glob != "" {
+				if match, err := filepath.Match(glob, entry.Name()); err != nil || !match {
+					return err
+				}
+			}
+			files = append(files, file)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+func parseLogs(reporter *report.Reporter, files []string) map[string]*result {
+	results := make(map[string]*result)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	fileC := make(chan string)
+	for i := 0; i < max(*flagParallel, 1); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for file := range fileC {
+				res := parseLog(reporter, file)
+				mu.Lock()
+				results[file] = res
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, file := range files {
+		fileC <- file
+	}
+	close(fileC)
+	wg.Wait()
+	return results
+}
+
+func parseLog(reporter *report.Reporter, file string) *result {
+	output, err := os.ReadFile(file)
+	if err != nil {
+		tool.Fail(err)
+	}
+	rep := reporter.Parse(output)
+	if rep == nil {
+		return &result{}
+	}
+	if *flagSymbolize {
+		if err := reporter.Symbolize(rep); err != nil {
+			fmt.Fprintf(os.Stderr, "%v: failed to symbolize: %v\n", file, err)
+		}
+	}
+	return &result{
+		Title:     rep.Title,
+		Type:      rep.Type.String(),
+		Frame:     rep.Frame,
+		Corrupted: rep.Corrupted,
+	}
+}
+
+type logDiff struct {
+	File    string
+	Changes []string
+}
+
+// compareResults returns the changed logs sorted by file name.
+// Logs that are present only in one of the sets are reported as well.
+func compareResults(base, results map[string]*result) []*logDiff {
+	files := make(map[string]bool)
+	for file := range base {
+		files[file] = true
+	}
+	for file := range results {
+		files[file] = true
+	}
+	var diffs []*logDiff
+	for file := range files {
+		var changes []string
+		prev, cur := base[file], results[file]
+		switch {
+		case prev == nil:
+			changes = append(changes, "missing in the base results")
+		case cur == nil:
+			changes = append(changes, "missing in the new results")
+		default:
+			changes = compareResult(prev, cur)
+		}
+		if len(changes) != 0 {
+			diffs = append(diffs, &logDiff{File: file, Changes: changes})
+		}
+	}
+	sort.Slice(diffs, func(i, j int) bool {
+		return diffs[i].File < diffs[j].File
+	})
+	return diffs
+}
+
+func compareResult(prev, cur *result) []string {
+	var changes []string
+	if prev.Title != cur.Title {
+		changes = append(changes, fmt.Sprintf("title: %q -> %q", prev.Title, cur.Title))
+	}
+	if prev.Type != cur.Type {
+		changes = append(changes, fmt.Sprintf("type: %v -> %v", prev.Type, cur.Type))
+	}
+	if prev.Frame != cur.Frame {
+		changes = append(changes, fmt.Sprintf("frame: %q -> %q", prev.Frame, cur.Frame))
+	}
+	if prev.Corrupted != cur.Corrupted {
+		changes = append(changes, fmt.Sprintf("corrupted: %v -> %v", prev.Corrupted, cur.Corrupted))
+	}
+	return changes
+}
@@ -0,0 +1,53 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareResults(t *testing.T) {
+	base := map[string]*result{
+		"same":    {Title: "KASAN: use-after-free Read in foo", Type: "KASAN-USE-AFTER-FREE-READ", Frame: "foo"},
+		"title":   {Title: "WARNING in foo", Type: "WARNING", Frame: "foo"},
+		"corrupt": {Title: "general protection fault in bar", Frame: "bar"},
+		"removed": {},
+	}
+	results := map[string]*result{
+		"same":    {Title: "KASAN: use-after-free Read in foo", Type: "KASAN-USE-AFTER-FREE-READ", Frame: "foo"},
+		"title":   {Title: "WARNING in baz", Type: "WARNING", Frame: "baz"},
+		"corrupt": {Title: "general protection fault in bar", Frame: "bar", Corrupted: true},
+		"added":   {Title: "attempt to add with overflow in foo", Type: "RUST-ARITHMETIC", Frame: "foo"},
+	}
+	assert.Equal(t, []*logDiff{
+		{File: "added", Changes: []string{"missing in the base results"}},
+		{File: "corrupt", Changes: []string{"corrupted: false -> true"}},
+		{File: "removed", Changes: []string{"missing in the new results"}},
+		{File: "title", Changes: []string{
+			`title: "WARNING in foo" -> "WARNING in baz"`,
+			`frame: "foo" -> "baz"`,
+		}},
+	}, compareResults(base, results))
+}
+
+func TestCollectLogs(t *testing.T) {
+	dir := t.TempDir()
+	for _, file := range []string{"crashes/1/log0", "crashes/1/report0", "crashes/2/log0", "crashes/2/log1"} {
+		file = filepath.Join(dir, filepath.FromSlash(file))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0755))
+		require.NoError(t, os.WriteFile(file, nil, 0644))
+	}
+	files, err := collectLogs([]string{dir}, "log*")
+	require.NoError(t, err)
+	for i, file := range files {
+		files[i], err = filepath.Rel(dir, file)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{"crashes/1/log0", "crashes/2/log0", "crashes/2/log1"}, files)
+}
@@ -0,0 +1,94 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/google/syzkaller/pkg/log"
+)
+
+// maxCrashLogSize limits the size of the accepted crash logs.
+const maxCrashLogSize = 64 << 20
+
+func (s *Service) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /api/repro", s.httpRepro)
+	mux.HandleFunc("GET /api/jobs", s.httpJobs)
+	mux.HandleFunc("GET /api/job", s.httpJob)
+	mux.HandleFunc("GET /api/artifact", s.httpArtifact)
+	return mux
+}
+
+func (s *Service) httpRepro(w http.ResponseWriter, r *http.Request) {
+	crashLog, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxCrashLogSize))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("failed to read the crash log: %v", err), http.StatusBadRequest)
+		return
+	}
+	if len(crashLog) == 0 {
+		http.Error(w, "empty crash log", http.StatusBadRequest)
+		return
+	}
+	build := r.URL.Query().Get("build")
+	if build == "" {
+		http.Error(w, "build must be specified", http.StatusBadRequest)
+		return
+	}
+	job, err := s.Enqueue(build, crashLog)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	writeJSON(w, job)
+}
+
+func (s *Service) httpJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, s.Jobs())
+}
+
+func (s *Service) httpJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		http.Error(w, "bad job id", http.StatusBadRequest)
+		return
+	}
+	job := s.Job(id)
+	if job == nil {
+		http.Error(w, "unknown job", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, job)
+}
+
+func (s *Service) httpArtifact(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.FormValue("id"))
+	if err != nil {
+		http.Error(w, "bad job id", http.StatusBadRequest)
+		return
+	}
+	file := s.ArtifactPath(id, r.FormValue("name"))
+	if file == "" {
+		http.Error(w, "unknown artifact", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	http.ServeFile(w, r, file)
+}
+
+func writeJSON(w http.ResponseWriter, obj any) {
+	data, err := json.MarshalIndent(obj, "", "\t")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err := w.Write(data); err != nil {
+		log.Logf(1, "failed to write the response: %v", err)
+	}
+}
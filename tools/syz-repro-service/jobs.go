@@ -0,0 +1,357 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+type JobStatus string
+
+const (
+	JobQueued  JobStatus = "queued"
+	JobRunning JobStatus = "running"
+	// The reproduction has finished, Repro says whether it was successful.
+	JobDone JobStatus = "done"
+	// The reproduction could not be performed, see Error.
+	JobFailed JobStatus = "failed"
+)
+
+type Job struct {
+	ID       int       `json:"id"`
+	Build    string    `json:"build"`
+	Status   JobStatus `json:"status"`
+	Created  time.Time `json:"created"`
+	Started  time.Time `json:"started,omitzero"`
+	Finished time.Time `json:"finished,omitzero"`
+	Error    string    `json:"error,omitempty"`
+	// The results of a finished reproduction.
+	Repro       bool    `json:"repro"`
+	CRepro      bool    `json:"crepro"`
+	Title       string  `json:"title,omitempty"`
+	Reliability float64 `json:"reliability,omitempty"`
+	// The files that can be downloaded via /api/artifact.
+	Artifacts []string `json:"artifacts"`
+}
+
+const (
+	jobFile       = "job.json"
+	crashLogFile  = "crash.log"
+	reproFile     = "repro.prog"
+	cReproFile    = "repro.cprog"
+	reproLogFile  = "repro.log"
+	reportFile    = "repro.report"
+	reproStatFile = "repro.stats"
+	straceFile    = "strace.log"
+)
+
+type reproFunc func(ctx context.Context, job *Job, crashLog []byte) *manager.ReproResult
+
+// Service queues crash logs and reproduces them on the corresponding builds.
+// The state of all jobs is kept in the workdir, so that it survives restarts.
+type Service struct {
+	dir      string
+	run      reproFunc
+	parallel int
+
+	mu     sync.Mutex
+	jobs   map[int]*Job
+	lastID int
+	queues map[string]*buildQueue
+}
+
+type buildQueue struct {
+	pending []*Job
+	ping    chan struct{}
+}
+
+func newService(workdir string, builds []string, parallel int, run reproFunc) (*Service, error) {
+	s := &Service{
+		dir:      filepath.Join(workdir, "jobs"),
+		run:      run,
+		parallel: parallel,
+		jobs:     map[int]*Job{},
+		queues:   map[string]*buildQueue{},
+	}
+	for _, name := range builds {
+		s.queues[name] = &buildQueue{ping: make(chan struct{}, 1)}
+	}
+	if err := osutil.MkdirAll(s.dir); err != nil {
+		return nil, err
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Service) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return err
+	}
+	var jobs []*Job
+	for _, entry := range entries {
+		if _, err := strconv.Atoi(entry.Name()); err != nil {
+			continue
+		}
+		job, err := osutil.ReadJSON[*Job](filepath.Join(s.dir, entry.Name(), jobFile))
+		if err != nil {
+			return fmt.Errorf("failed to load job %v: %w", entry.Name(), err)
+		}
+		jobs = append(jobs, job)
+	}
+	slices.SortFunc(jobs, func(a, b *Job) int { return a.ID - b.ID })
+	for _, job := range jobs {
+		s.jobs[job.ID] = job
+		s.lastID = max(s.lastID, job.ID)
+		if job.Status != JobQueued && job.Status != JobRunning {
+			continue
+		}
+		// The service was stopped before the job has finished.
+		queue := s.queues[job.Build]
+		if queue == nil {
+			job.Status = JobFailed
+			job.Error = "the build is no longer configured"
+		} else {
+			job.Status = JobQueued
+			job.Started = time.Time{}
+			queue.pending = append(queue.pending, job)
+		}
+		if err := s.saveLocked(job); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Enqueue creates a new job that reproduces crashLog on the build.
+func (s *Service) Enqueue(build string, crashLog []byte) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	queue := s.queues[build]
+	if queue == nil {
+		return nil, fmt.Errorf("unknown build %q", build)
+	}
+	job := &Job{
+		ID:        s.lastID + 1,
+		Build:     build,
+		Status:    JobQueued,
+		Created:   time.Now(),
+		Artifacts: []string{crashLogFile},
+	}
+	dir := s.jobDir(job)
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	if err := osutil.WriteFile(filepath.Join(dir, crashLogFile), crashLog); err != nil {
+		return nil, err
+	}
+	if err := s.saveLocked(job); err != nil {
+		return nil, err
+	}
+	s.lastID = job.ID
+	s.jobs[job.ID] = job
+	queue.pending = append(queue.pending, job)
+	queue.kick()
+	log.Logf(0, "job %v: queued for build %v", job.ID, build)
+	return copyJob(job), nil
+}
+
+// Job returns a snapshot of the job state.
+func (s *Service) Job(id int) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job := s.jobs[id]; job != nil {
+		return copyJob(job)
+	}
+	return nil
+}
+
+// Jobs returns snapshots of all jobs ordered by ID.
+func (s *Service) Jobs() []*Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ret []*Job
+	for _, job := range s.jobs {
+		ret = append(ret, copyJob(job))
+	}
+	slices.SortFunc(ret, func(a, b *Job) int { return a.ID - b.ID })
+	return ret
+}
+
+// ArtifactPath returns the path to the artifact file of the job, or an empty string if it does not exist.
+func (s *Service) ArtifactPath(id int, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	job := s.jobs[id]
+	if job == nil || !slices.Contains(job.Artifacts, name) {
+		return ""
+	}
+	return filepath.Join(s.jobDir(job), name)
+}
+
+// Loop processes the queued jobs until ctx is cancelled.
+// The jobs that are interrupted by the cancellation remain queued.
+func (s *Service) Loop(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, queue := range s.queues {
+		for i := 0; i < s.parallel; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				s.worker(ctx, queue)
+			}()
+		}
+	}
+	wg.Wait()
+}
+
+func (s *Service) worker(ctx context.Context, queue *buildQueue) {
+	for ctx.Err() == nil {
+		job := s.pop(queue)
+		if job == nil {
+			select {
+			case <-queue.ping:
+				continue
+			case <-ctx.Done():
+				return
+			}
+		}
+		s.process(ctx, job)
+	}
+}
+
+func (s *Service) pop(queue *buildQueue) *Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(queue.pending) == 0 {
+		return nil
+	}
+	job := queue.pending[0]
+	queue.pending = queue.pending[1:]
+	if len(queue.pending) != 0 {
+		// Let other workers pick up the rest.
+		queue.kick()
+	}
+	job.Status = JobRunning
+	job.Started = time.Now()
+	s.saveJob(job)
+	return job
+}
+
+func (q *buildQueue) kick() {
+	select {
+	case q.ping <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) process(ctx context.Context, job *Job) {
+	log.Logf(0, "job %v: reproducing on build %v", job.ID, job.Build)
+	dir := s.jobDir(job)
+	crashLog, err := os.ReadFile(filepath.Join(dir, crashLogFile))
+	var res *manager.ReproResult
+	var artifacts []string
+	if err == nil {
+		res = s.run(ctx, job, crashLog)
+		var saveErr error
+		artifacts, saveErr = saveArtifacts(dir, res)
+		err = errors.Join(res.Err, saveErr)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if ctx.Err() != nil {
+		// The service is being stopped, the job will be restarted next time.
+		job.Status = JobQueued
+		job.Started = time.Time{}
+		s.saveJob(job)
+		return
+	}
+	job.Finished = time.Now()
+	job.Status = JobDone
+	if err != nil {
+		job.Status = JobFailed
+		job.Error = err.Error()
+	}
+	if res != nil && res.Repro != nil {
+		job.Repro = true
+		job.CRepro = res.Repro.CRepro
+		job.Title = res.Repro.Report.Title
+		job.Reliability = res.Repro.Reliability
+	}
+	job.Artifacts = append(job.Artifacts, artifacts...)
+	s.saveJob(job)
+	log.Logf(0, "job %v: finished, repro=%v crepro=%v title=%q error=%q",
+		job.ID, job.Repro, job.CRepro, job.Title, job.Error)
+}
+
+// saveArtifacts stores the reproduction results in the job dir and returns the names of the stored files.
+func saveArtifacts(dir string, res *manager.ReproResult) ([]string, error) {
+	files := map[string][]byte{
+		reproStatFile: res.Stats.FullLog(),
+	}
+	if r := res.Repro; r != nil {
+		files[reproFile] = r.Prog.Serialize()
+		files[reproLogFile] = r.Report.Output
+		files[reportFile] = r.Report.Report
+		if r.CRepro {
+			cprog, err := r.CProgram()
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate C repro: %w", err)
+			}
+			files[cReproFile] = cprog
+		}
+	}
+	if res.Strace != nil {
+		files[straceFile] = res.Strace.Output
+	}
+	var names []string
+	var errs []error
+	for name, data := range files {
+		if len(data) == 0 {
+			continue
+		}
+		if err := osutil.WriteFile(filepath.Join(dir, name), data); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names, errors.Join(errs...)
+}
+
+func (s *Service) saveJob(job *Job) {
+	if err := s.saveLocked(job); err != nil {
+		log.Errorf("job %v: failed to save state: %v", job.ID, err)
+	}
+}
+
+func (s *Service) saveLocked(job *Job) error {
+	return osutil.WriteJSON(filepath.Join(s.jobDir(job), jobFile), job)
+}
+
+func (s *Service) jobDir(job *Job) string {
+	return filepath.Join(s.dir, strconv.Itoa(job.ID))
+}
+
+func copyJob(job *Job) *Job {
+	ret := *job
+	ret.Artifacts = slices.Clone(job.Artifacts)
+	return &ret
+}
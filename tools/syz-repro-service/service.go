@@ -0,0 +1,182 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-repro-service is a long-running service that reproduces crash logs obtained elsewhere
+// (e.g. on production devices or from the dashboard) without running a fuzzing manager. Usage:
+//
+//	syz-repro-service -config=service.cfg
+//
+// The config lists the kernel builds that crash logs may refer to. Each build is described
+// by a manager config (kernel image, vmlinux, VM type and count), its VMs are used only
+// for reproduction:
+//
+//	{
+//		"http": "localhost:8080",
+//		"workdir": "/syzkaller/repro-service",
+//		"builds": {
+//			"prod-6.6": "/syzkaller/prod-6.6.cfg",
+//			"next": "/syzkaller/next.cfg"
+//		},
+//		"repros_per_build": 2
+//	}
+//
+// The HTTP API:
+//
+//	POST /api/repro?build=prod-6.6              queue the crash log passed as the request body
+//	GET  /api/jobs                              list all jobs
+//	GET  /api/job?id=1                          get the job status
+//	GET  /api/artifact?id=1&name=repro.cprog    download a job artifact
+//
+// For example:
+//
+//	curl --data-binary @crash.log 'http://localhost:8080/api/repro?build=prod-6.6'
+//
+// The API is not authenticated, so the service should not be exposed to untrusted networks.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/repro"
+	"github.com/google/syzkaller/vm"
+)
+
+var (
+	flagConfig = flag.String("config", "", "service configuration file")
+	flagDebug  = flag.Bool("debug", false, "dump all VM output to console")
+)
+
+type Config struct {
+	HTTP    string `json:"http"`
+	Workdir string `json:"workdir"`
+	// Build name -> manager config file.
+	Builds map[string]string `json:"builds"`
+	// The number of reproductions that may run in parallel for one build.
+	// They share the VMs of the build.
+	ReprosPerBuild int `json:"repros_per_build"`
+}
+
+type build struct {
+	cfg      *mgrconfig.Config
+	reporter *report.Reporter
+	pool     *vm.Dispatcher
+}
+
+func main() {
+	flag.Parse()
+	if *flagConfig == "" {
+		flag.PrintDefaults()
+		log.Fatalf("usage: syz-repro-service -config=service.cfg")
+	}
+	cfg := &Config{
+		ReprosPerBuild: 1,
+	}
+	if err := config.LoadFile(*flagConfig, cfg); err != nil {
+		log.Fatalf("%v: %v", *flagConfig, err)
+	}
+	if cfg.HTTP == "" || cfg.Workdir == "" || len(cfg.Builds) == 0 || cfg.ReprosPerBuild <= 0 {
+		log.Fatalf("http, workdir, builds and a positive repros_per_build must be specified")
+	}
+	cfg.Workdir = osutil.Abs(cfg.Workdir)
+
+	ctx, done := context.WithCancel(context.Background())
+	builds := map[string]*build{}
+	var names []string
+	for name, file := range cfg.Builds {
+		b, err := loadBuild(file)
+		if err != nil {
+			log.Fatalf("build %v: %v", name, err)
+		}
+		go b.pool.Loop(ctx)
+		builds[name] = b
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	osutil.HandleInterrupts(vm.Shutdown)
+	go func() {
+		// Interrupted jobs remain queued and are restarted next time.
+		<-vm.Shutdown
+		done()
+	}()
+
+	svc, err := newService(cfg.Workdir, names, cfg.ReprosPerBuild,
+		func(ctx context.Context, job *Job, crashLog []byte) *manager.ReproResult {
+			return runRepro(ctx, builds[job.Build], crashLog)
+		})
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	srv := &http.Server{
+		Addr:    cfg.HTTP,
+		Handler: svc.Handler(),
+	}
+	stopped := make(chan struct{})
+	go func() {
+		svc.Loop(ctx)
+		done()
+		if err := srv.Shutdown(context.Background()); err != nil {
+			log.Logf(0, "failed to shut down HTTP server: %v", err)
+		}
+		close(stopped)
+	}()
+	log.Logf(0, "serving %v builds on %v", len(names), cfg.HTTP)
+	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatalf("failed to serve HTTP: %v", err)
+	}
+	<-stopped
+}
+
+func loadBuild(file string) (*build, error) {
+	cfg, err := mgrconfig.LoadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	reporter, err := report.NewReporter(cfg)
+	if err != nil {
+		return nil, err
+	}
+	vmPool, err := vm.Create(cfg, *flagDebug)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create VM pool: %w", err)
+	}
+	pool := vm.NewDispatcher(vmPool, nil)
+	pool.ReserveForRun(vmPool.Count())
+	return &build{
+		cfg:      cfg,
+		reporter: reporter,
+		pool:     pool,
+	}, nil
+}
+
+func runRepro(ctx context.Context, b *build, crashLog []byte) *manager.ReproResult {
+	res, stats, err := repro.Run(ctx, crashLog, repro.Environment{
+		Config:   b.cfg,
+		Features: flatrpc.AllFeatures,
+		Reporter: b.reporter,
+		Pool:     b.pool,
+	})
+	ret := &manager.ReproResult{
+		Repro: res,
+		Stats: stats,
+		Err:   err,
+	}
+	if err == nil && res != nil && b.cfg.StraceBin != "" {
+		strace := repro.RunStrace(res, b.cfg, b.reporter, b.pool)
+		// Similarly to syz-manager, only keep strace output that shows the same bug.
+		if strace.IsSameBug(res) {
+			ret.Strace = strace
+		}
+	}
+	return ret
+}
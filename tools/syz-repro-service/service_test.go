@@ -0,0 +1,170 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/manager"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/repro"
+	"github.com/google/syzkaller/prog"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService(t *testing.T) {
+	target, err := prog.GetTarget(targets.TestOS, targets.TestArch64)
+	require.NoError(t, err)
+	p, err := target.Deserialize([]byte("test()\n"), prog.NonStrict)
+	require.NoError(t, err)
+	run := func(ctx context.Context, job *Job, crashLog []byte) *manager.ReproResult {
+		if !strings.Contains(string(crashLog), "KASAN") {
+			return &manager.ReproResult{Err: errors.New("no programs")}
+		}
+		return &manager.ReproResult{
+			Repro: &repro.Result{
+				Prog: p,
+				Report: &report.Report{
+					Title:  "KASAN: use-after-free in foo",
+					Report: []byte("the report"),
+				},
+			},
+			Stats: &repro.Stats{Log: []byte("the repro log")},
+		}
+	}
+	dir := t.TempDir()
+	svc, err := newService(dir, []string{"next"}, 1, run)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go svc.Loop(ctx)
+	server := httptest.NewServer(svc.Handler())
+	defer server.Close()
+
+	resp, err := http.Post(server.URL+"/api/repro?build=prod", "text/plain", strings.NewReader("log"))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	job1 := postLog(t, server.URL, "next", "KASAN: use-after-free")
+	assert.Equal(t, 1, job1.ID)
+	job2 := postLog(t, server.URL, "next", "nothing here")
+	assert.Equal(t, 2, job2.ID)
+
+	job1 = waitJob(t, server.URL, job1.ID)
+	assert.Equal(t, JobDone, job1.Status)
+	assert.True(t, job1.Repro)
+	assert.Equal(t, "KASAN: use-after-free in foo", job1.Title)
+	assert.Equal(t, []string{crashLogFile, reproFile, reportFile, reproStatFile}, job1.Artifacts)
+	assert.Equal(t, "test()\n", getBody(t, server.URL+"/api/artifact?id=1&name="+reproFile))
+	assert.Equal(t, "KASAN: use-after-free", getBody(t, server.URL+"/api/artifact?id=1&name="+crashLogFile))
+
+	job2 = waitJob(t, server.URL, job2.ID)
+	assert.Equal(t, JobFailed, job2.Status)
+	assert.False(t, job2.Repro)
+	assert.Equal(t, "no programs", job2.Error)
+
+	resp, err = http.Get(server.URL + "/api/artifact?id=2&name=../1/" + reproFile)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	var jobs []*Job
+	require.NoError(t, json.Unmarshal([]byte(getBody(t, server.URL+"/api/jobs")), &jobs))
+	assert.Equal(t, []*Job{job1, job2}, jobs)
+
+	// The finished jobs must survive a restart.
+	svc, err = newService(dir, []string{"next"}, 1, run)
+	require.NoError(t, err)
+	assert.Equal(t, []*Job{job1, job2}, svc.Jobs())
+}
+
+func TestServiceRestart(t *testing.T) {
+	started := make(chan bool)
+	run := func(ctx context.Context, job *Job, crashLog []byte) *manager.ReproResult {
+		started <- true
+		<-ctx.Done()
+		return &manager.ReproResult{Err: ctx.Err()}
+	}
+	dir := t.TempDir()
+	svc, err := newService(dir, []string{"next"}, 1, run)
+	require.NoError(t, err)
+	_, err = svc.Enqueue("next", []byte("log"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan bool)
+	go func() {
+		svc.Loop(ctx)
+		close(done)
+	}()
+	<-started
+	assert.Equal(t, JobRunning, svc.Job(1).Status)
+	cancel()
+	<-done
+	assert.Equal(t, JobQueued, svc.Job(1).Status)
+
+	// The interrupted job is picked up again after a restart.
+	svc, err = newService(dir, []string{"next"}, 1, run)
+	require.NoError(t, err)
+	ctx, cancel = context.WithCancel(context.Background())
+	done = make(chan bool)
+	go func() {
+		svc.Loop(ctx)
+		close(done)
+	}()
+	<-started
+	assert.Equal(t, JobRunning, svc.Job(1).Status)
+	cancel()
+	<-done
+
+	// Jobs for the builds that are no longer configured fail.
+	svc, err = newService(dir, []string{"prod"}, 1, run)
+	require.NoError(t, err)
+	assert.Equal(t, JobFailed, svc.Job(1).Status)
+}
+
+func postLog(t *testing.T, url, build, crashLog string) *Job {
+	resp, err := http.Post(url+"/api/repro?build="+build, "text/plain", strings.NewReader(crashLog))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	job := new(Job)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(job))
+	assert.Equal(t, build, job.Build)
+	return job
+}
+
+func waitJob(t *testing.T, url string, id int) *Job {
+	for start := time.Now(); time.Since(start) < time.Minute; time.Sleep(10 * time.Millisecond) {
+		job := new(Job)
+		body := getBody(t, url+"/api/job?id="+strconv.Itoa(id))
+		require.NoError(t, json.Unmarshal([]byte(body), job))
+		if job.Status == JobDone || job.Status == JobFailed {
+			return job
+		}
+	}
+	t.Fatalf("job %v has not finished", id)
+	return nil
+}
+
+func getBody(t *testing.T, url string) string {
+	resp, err := http.Get(url)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return string(data)
+}
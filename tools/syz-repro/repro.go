@@ -11,7 +11,10 @@ import (
 	"path/filepath"
 
 	"github.com/google/syzkaller/pkg/csource"
+	"github.com/google/syzkaller/pkg/debugtracer"
 	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/instance"
+	"github.com/google/syzkaller/pkg/kconfig"
 	"github.com/google/syzkaller/pkg/log"
 	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
@@ -28,6 +31,15 @@ var (
 	flagCRepro = flag.String("crepro", filepath.Join(".", "repro.c"), "output c file (repro.c)")
 	flagTitle  = flag.String("title", "", "where to save the title of the reproduced bug")
 	flagStrace = flag.String("strace", "", "output strace log (strace_bin must be set)")
+	flagFtrace = flag.String("ftrace", "", "output ftrace log (ftrace must be configured)")
+
+	flagMinFeatures = flag.Bool("minimize_features", false, "disable executor features not needed for the repro")
+	flagBaseline    = flag.String("baseline_config", "", "minimize the kernel config against this baseline config "+
+		"(requires kernel_src in the manager config, rebuilds the kernel many times)")
+	flagKernelCfg = flag.String("kernel_config", "", "config of the crashed kernel (kernel_obj/.config by default)")
+	flagMinConfig = flag.String("min_config", filepath.Join(".", "repro.config"), "output minimized kernel config")
+	flagCompiler  = flag.String("compiler", "gcc", "compiler to rebuild the kernel with")
+	flagUserspace = flag.String("userspace", "", "userspace dir to build the image with (see syz-ci)")
 )
 
 func main() {
@@ -64,15 +76,19 @@ func main() {
 	pool.ReserveForRun(count)
 
 	ctx, done := context.WithCancel(context.Background())
+	var res *repro.Result
 	go func() {
 		defer done()
 
-		res, stats, err := repro.Run(ctx, data, repro.Environment{
+		env := repro.Environment{
 			Config:   cfg,
 			Features: flatrpc.AllFeatures,
 			Reporter: reporter,
 			Pool:     pool,
-		})
+		}
+		var stats *repro.Stats
+		var err error
+		res, stats, err = repro.Run(ctx, data, env)
 		if err != nil {
 			log.Logf(0, "reproduction failed: %v", err)
 		}
@@ -86,6 +102,14 @@ func main() {
 		if res == nil {
 			return
 		}
+		if *flagMinFeatures {
+			minRes, _, err := repro.MinimizeFeatures(ctx, res, env)
+			if err != nil {
+				log.Fatalf("failed to minimize features: %v", err)
+			}
+			res = minRes
+			fmt.Printf("needed features: %v\n", res.Opts.EnabledFeatures())
+		}
 
 		fmt.Printf("opts: %+v crepro: %v\n\n", res.Opts, res.CRepro)
 		progSerialized := res.Prog.Serialize()
@@ -106,8 +130,67 @@ func main() {
 			result := repro.RunStrace(res, cfg, reporter, pool)
 			recordStraceResult(result, *flagStrace)
 		}
+		if *flagFtrace != "" {
+			result := repro.RunFtrace(res, cfg, reporter, pool)
+			recordFtraceResult(result, *flagFtrace)
+		}
 	}()
 	pool.Loop(ctx)
+	if res != nil && *flagBaseline != "" {
+		minimizeConfig(res, count)
+	}
+}
+
+func minimizeConfig(res *repro.Result, testVMs int) {
+	// The kernel is rebuilt in a separate workdir, the main config is not modified.
+	cfg, err := mgrconfig.LoadFile(*flagConfig)
+	if err != nil {
+		log.Fatalf("%v: %v", *flagConfig, err)
+	}
+	if cfg.KernelSrc == "" {
+		log.Fatalf("kernel_src must be set in the manager config for config minimization")
+	}
+	kernelConfig := *flagKernelCfg
+	if kernelConfig == "" {
+		kernelConfig = filepath.Join(cfg.KernelObj, ".config")
+	}
+	original, err := os.ReadFile(kernelConfig)
+	if err != nil {
+		log.Fatalf("failed to read the kernel config: %v", err)
+	}
+	baseline, err := os.ReadFile(*flagBaseline)
+	if err != nil {
+		log.Fatalf("failed to read the baseline config: %v", err)
+	}
+	kconf, err := kconfig.Parse(cfg.SysTarget, filepath.Join(cfg.KernelSrc, "Kconfig"))
+	if err != nil {
+		log.Fatalf("failed to parse Kconfig: %v", err)
+	}
+	cfg.Workdir = filepath.Join(cfg.Workdir, "minconfig")
+	env, err := instance.NewEnv(cfg, nil, nil)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	minConfig, err := repro.MinimizeConfig(res, &repro.ConfigMinimization{
+		Env: env,
+		Build: instance.BuildKernelConfig{
+			MakeBin:      instance.MakeBin,
+			CompilerBin:  *flagCompiler,
+			UserspaceDir: *flagUserspace,
+		},
+		KConfig:  kconf,
+		Config:   original,
+		Baseline: baseline,
+		TestVMs:  testVMs,
+		Tracer:   &debugtracer.GenericTracer{TraceWriter: os.Stdout, WithTime: true},
+	})
+	if err != nil {
+		log.Fatalf("failed to minimize the kernel config: %v", err)
+	}
+	if err := osutil.WriteFile(*flagMinConfig, minConfig); err != nil {
+		log.Fatalf("failed to write the minimized config: %v", err)
+	}
+	fmt.Printf("minimized kernel config saved to %s\n", *flagMinConfig)
 }
 
 func recordTitle(res *repro.Result, fileName string) {
@@ -151,3 +234,22 @@ func recordStraceResult(result *repro.StraceResult, fileName string) {
 		log.Logf(0, "failed to write strace output to file: %v", err)
 	}
 }
+
+func recordFtraceResult(result *repro.FtraceResult, fileName string) {
+	if result.Error != nil {
+		log.Logf(0, "failed to run ftrace: %v", result.Error)
+	}
+	if result.Report != nil {
+		log.Logf(0, "with ftrace repro crashed with title: %s", result.Report.Title)
+	} else if result.Error == nil {
+		log.Logf(0, "repro didn't crash with ftrace")
+	}
+	if len(result.Trace) == 0 {
+		return
+	}
+	if err := osutil.WriteFile(fileName, result.Trace); err == nil {
+		fmt.Printf("ftrace log saved to %s\n", fileName)
+	} else {
+		log.Logf(0, "failed to write ftrace output to file: %v", err)
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// syz-worker runs VMs on behalf of a syz-manager running on another machine
+// (see the "remote" VM type). Usage:
+//
+//	syz-worker -config=worker.cfg -token=token.txt [-addr=:7070]
+//
+// The config describes the local VM pool, e.g.:
+//
+//	{
+//		"target": "linux/amd64",
+//		"workdir": "/syzkaller/worker",
+//		"image": "/syzkaller/bullseye.img",
+//		"sshkey": "/syzkaller/bullseye.id_rsa",
+//		"type": "qemu",
+//		"vm": {"count": 8, "kernel": "/linux/arch/x86/boot/bzImage", "cpu": 2, "mem": 2048}
+//	}
+//
+// The token file contains a secret shared with the manager (token_file in the remote VM config).
+// The manager config then lists the workers:
+//
+//	"type": "remote",
+//	"vm": {"workers": ["lab1:7070", "lab2:7070"], "token_file": "token.txt"}
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/remote"
+	"github.com/google/syzkaller/vm/vmimpl"
+
+	_ "github.com/google/syzkaller/vm"
+)
+
+var (
+	flagConfig = flag.String("config", "", "worker configuration file")
+	flagToken  = flag.String("token", "", "file with the secret token shared with the manager")
+	flagAddr   = flag.String("addr", ":7070", "address to listen on for manager connections")
+	flagDebug  = flag.Bool("debug", false, "dump all VM output to console")
+)
+
+type Config struct {
+	Name     string          `json:"name"`
+	Target   string          `json:"target"` // OS/arch, e.g. "linux/amd64"
+	Workdir  string          `json:"workdir"`
+	Image    string          `json:"image"`
+	SSHKey   string          `json:"sshkey"`
+	SSHUser  string          `json:"ssh_user"`
+	Slowdown int             `json:"slowdown"` // see mgrconfig.Config.Experimental.Slowdown
+	Type     string          `json:"type"`
+	VM       json.RawMessage `json:"vm"`
+}
+
+func main() {
+	flag.Parse()
+	if *flagConfig == "" || *flagToken == "" {
+		flag.PrintDefaults()
+		log.Fatalf("usage: syz-worker -config=worker.cfg -token=token.txt")
+	}
+	cfg := &Config{
+		Name:     "worker",
+		SSHUser:  "root",
+		Slowdown: 1,
+	}
+	if err := config.LoadFile(*flagConfig, cfg); err != nil {
+		log.Fatalf("%v: %v", *flagConfig, err)
+	}
+	token, err := remote.ReadToken(*flagToken)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	targetOS, targetArch, err := splitTarget(cfg.Target)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	target := targets.Get(targetOS, targetArch)
+	if target == nil {
+		log.Fatalf("unknown target %v", cfg.Target)
+	}
+	if cfg.Type == "remote" {
+		log.Fatalf("workers can't use remote VMs")
+	}
+	typ, ok := vmimpl.Types[cfg.Type]
+	if !ok {
+		log.Fatalf("unknown VM type %q", cfg.Type)
+	}
+	if cfg.Workdir == "" || cfg.Slowdown <= 0 {
+		log.Fatalf("workdir and a positive slowdown must be specified")
+	}
+	cfg.Workdir = osutil.Abs(cfg.Workdir)
+	// Each connected manager gets own pool that runs the diagnostics configured in the manager.
+	newPool := func(diagnostics []mgrconfig.Diagnostic) (vmimpl.Pool, error) {
+		return typ.Ctor(&vmimpl.Env{
+			Name:        cfg.Name,
+			OS:          targetOS,
+			Arch:        targetArch,
+			Workdir:     cfg.Workdir,
+			Image:       osutil.Abs(cfg.Image),
+			SSHKey:      osutil.Abs(cfg.SSHKey),
+			SSHUser:     cfg.SSHUser,
+			Timeouts:    target.Timeouts(cfg.Slowdown),
+			Debug:       *flagDebug,
+			Config:      cfg.VM,
+			Diagnostics: diagnostics,
+		})
+	}
+	// Check the config right away.
+	pool, err := newPool(nil)
+	if err != nil {
+		log.Fatalf("failed to create VM pool: %v", err)
+	}
+	count := pool.Count()
+	if closer, ok := pool.(io.Closer); ok {
+		closer.Close()
+	}
+	worker, err := remote.NewWorker(newPool, cfg.Type, cfg.Workdir, token)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	ln, err := net.Listen("tcp", *flagAddr)
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	log.Logf(0, "serving %v %v VMs on %v", count, cfg.Type, ln.Addr())
+	log.Fatal(worker.Serve(ln))
+}
+
+func splitTarget(target string) (string, string, error) {
+	targetOS, targetArch, ok := strings.Cut(target, "/")
+	if !ok {
+		return "", "", fmt.Errorf("bad target %q, want OS/arch", target)
+	}
+	return targetOS, targetArch, nil
+}
@@ -0,0 +1,262 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package vm
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// ConsoleRecorder saves the full console output of Instance.Run calls along with
+// the time when each piece of output was received.
+// For each VM only the last few recordings are kept.
+// A recording is a gzip stream (ModTime is the run start time, Comment is the command)
+// of chunks, each chunk is uvarint(ms since start) + uvarint(len) + data.
+type ConsoleRecorder struct {
+	dir  string
+	runs int
+	mu   sync.Mutex
+	seq  map[int]int
+}
+
+// ConsoleRecord describes a saved recording.
+type ConsoleRecord struct {
+	VM      int
+	Seq     int
+	File    string
+	Size    int64
+	Start   time.Time
+	Command string
+}
+
+// ConsoleChunk is a piece of console output received Time after the start of the run.
+type ConsoleChunk struct {
+	Time time.Duration
+	Data []byte
+}
+
+func NewConsoleRecorder(dir string, runs int) (*ConsoleRecorder, error) {
+	if err := osutil.MkdirAll(dir); err != nil {
+		return nil, err
+	}
+	return &ConsoleRecorder{
+		dir:  dir,
+		runs: runs,
+		seq:  make(map[int]int),
+	}, nil
+}
+
+// List returns recordings of the VM, the most recent first.
+func (cr *ConsoleRecorder) List(vm int) ([]*ConsoleRecord, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.list(vm)
+}
+
+func (cr *ConsoleRecorder) list(vm int) ([]*ConsoleRecord, error) {
+	dir := cr.vmDir(vm)
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var res []*ConsoleRecord
+	for _, file := range files {
+		seq, err := strconv.Atoi(strings.TrimSuffix(file.Name(), ".gz"))
+		if err != nil {
+			continue
+		}
+		rec := &ConsoleRecord{
+			VM:   vm,
+			Seq:  seq,
+			File: filepath.Join(dir, file.Name()),
+		}
+		if info, err := file.Info(); err == nil {
+			rec.Size = info.Size()
+		}
+		if f, err := os.Open(rec.File); err == nil {
+			if gz, err := gzip.NewReader(f); err == nil {
+				rec.Start = gz.ModTime
+				rec.Command = gz.Comment
+			}
+			f.Close()
+		}
+		res = append(res, rec)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Seq > res[j].Seq
+	})
+	return res, nil
+}
+
+func (cr *ConsoleRecorder) vmDir(vm int) string {
+	return filepath.Join(cr.dir, fmt.Sprintf("vm-%v", vm))
+}
+
+// start creates a new recording for the VM and removes the oldest ones.
+func (cr *ConsoleRecorder) start(vm int, command string) (*consoleWriter, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	old, err := cr.list(vm)
+	if err != nil {
+		return nil, err
+	}
+	seq, ok := cr.seq[vm]
+	if !ok && len(old) != 0 {
+		// Continue the numbering after a manager restart.
+		seq = old[0].Seq
+	}
+	seq++
+	cr.seq[vm] = seq
+	for i := cr.runs - 1; i < len(old); i++ {
+		os.Remove(old[i].File)
+	}
+	if err := osutil.MkdirAll(cr.vmDir(vm)); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(filepath.Join(cr.vmDir(vm), fmt.Sprintf("%v.gz", seq)))
+	if err != nil {
+		return nil, err
+	}
+	w := &consoleWriter{
+		f:     f,
+		gz:    gzip.NewWriter(f),
+		start: time.Now(),
+	}
+	w.lastFlush = w.start
+	w.gz.ModTime = w.start
+	w.gz.Comment = strings.Map(func(r rune) rune {
+		// The gzip header can only hold Latin-1 strings without zero bytes.
+		if r == 0 || r > 0xff {
+			return '?'
+		}
+		return r
+	}, command)
+	return w, nil
+}
+
+type consoleWriter struct {
+	f         *os.File
+	gz        *gzip.Writer
+	start     time.Time
+	lastFlush time.Time
+	buf       []byte
+	err       error
+}
+
+// Flush the compressor from time to time, so that recordings of runs in progress are readable.
+const consoleFlushPeriod = 5 * time.Second
+
+func (w *consoleWriter) write(data []byte) {
+	if w.err != nil || len(data) == 0 {
+		return
+	}
+	w.buf = binary.AppendUvarint(w.buf[:0], uint64(time.Since(w.start).Milliseconds()))
+	w.buf = binary.AppendUvarint(w.buf, uint64(len(data)))
+	if _, err := w.gz.Write(w.buf); err != nil {
+		w.fail(err)
+		return
+	}
+	if _, err := w.gz.Write(data); err != nil {
+		w.fail(err)
+		return
+	}
+	if time.Since(w.lastFlush) > consoleFlushPeriod {
+		w.lastFlush = time.Now()
+		if err := w.gz.Flush(); err != nil {
+			w.fail(err)
+		}
+	}
+}
+
+func (w *consoleWriter) fail(err error) {
+	log.Logf(0, "failed to record console output: %v", err)
+	w.err = err
+}
+
+func (w *consoleWriter) close() {
+	if err := w.gz.Close(); err != nil && w.err == nil {
+		w.fail(err)
+	}
+	w.f.Close()
+}
+
+// Console output is received in chunks of at most vmimpl.OutputMerger buffer size.
+const maxConsoleChunk = 64 << 20
+
+// ReadConsoleRecord reads a recording saved by ConsoleRecorder.
+// Recordings of runs that are still in progress are returned up to the last complete chunk.
+func ReadConsoleRecord(r io.Reader) (*ConsoleRecord, []ConsoleChunk, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer gz.Close()
+	rec := &ConsoleRecord{
+		Start:   gz.ModTime,
+		Command: gz.Comment,
+	}
+	rd := bufio.NewReader(gz)
+	var chunks []ConsoleChunk
+	for {
+		ms, err := binary.ReadUvarint(rd)
+		if err != nil {
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return rec, chunks, nil
+			}
+			return nil, nil, err
+		}
+		size, err := binary.ReadUvarint(rd)
+		if err != nil {
+			return rec, chunks, nil
+		}
+		if size > maxConsoleChunk {
+			return nil, nil, fmt.Errorf("corrupted console record: chunk size %v", size)
+		}
+		data := make([]byte, size)
+		if _, err := io.ReadFull(rd, data); err != nil {
+			return rec, chunks, nil
+		}
+		chunks = append(chunks, ConsoleChunk{
+			Time: time.Duration(ms) * time.Millisecond,
+			Data: data,
+		})
+	}
+}
+
+// FormatConsoleRecord writes the recorded output with each line prefixed
+// by the time when it started to arrive.
+func FormatConsoleRecord(w io.Writer, chunks []ConsoleChunk) error {
+	buf := new(bytes.Buffer)
+	lineStart := true
+	for _, chunk := range chunks {
+		for _, c := range chunk.Data {
+			if lineStart {
+				fmt.Fprintf(buf, "[%10.3f] ", chunk.Time.Seconds())
+				lineStart = false
+			}
+			buf.WriteByte(c)
+			lineStart = c == '\n'
+		}
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
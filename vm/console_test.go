@@ -0,0 +1,61 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsoleRecorder(t *testing.T) {
+	dir := t.TempDir()
+	cr, err := NewConsoleRecorder(dir, 2)
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		w, err := cr.start(1, "cmd é")
+		require.NoError(t, err)
+		w.write([]byte("line 1\nline"))
+		w.write(nil)
+		w.write([]byte(" 2\n"))
+		w.close()
+	}
+	records, err := cr.List(1)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, 3, records[0].Seq)
+	assert.Equal(t, 2, records[1].Seq)
+	assert.Equal(t, "cmd é", records[0].Command)
+	empty, err := cr.List(0)
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	f, err := os.Open(records[0].File)
+	require.NoError(t, err)
+	defer f.Close()
+	rec, chunks, err := ReadConsoleRecord(f)
+	require.NoError(t, err)
+	assert.Equal(t, records[0].Start, rec.Start)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, "line 1\nline", string(chunks[0].Data))
+	assert.Equal(t, " 2\n", string(chunks[1].Data))
+	buf := new(bytes.Buffer)
+	require.NoError(t, FormatConsoleRecord(buf, chunks))
+	assert.Equal(t, fmt.Sprintf("[%10.3f] line 1\n[%10.3f] line 2\n",
+		chunks[0].Time.Seconds(), chunks[0].Time.Seconds()), buf.String())
+
+	// The numbering continues after a restart.
+	cr, err = NewConsoleRecorder(dir, 2)
+	require.NoError(t, err)
+	w, err := cr.start(1, "")
+	require.NoError(t, err)
+	w.close()
+	records, err = cr.List(1)
+	require.NoError(t, err)
+	assert.Equal(t, 4, records[0].Seq)
+}
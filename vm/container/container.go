@@ -0,0 +1,353 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package container runs host fuzzer targets on the local machine inside of Linux namespaces.
+// Every command runs in new user, mount, pid, network, ipc and uts namespaces,
+// so it can't reach the host network and all of its processes die together with it.
+// Resource limits are enforced with cgroup v2 if a delegated cgroup is configured.
+// The container root is mapped to an unprivileged host user, so it can access only
+// the files that are accessible to other users on the host (and own workdir).
+// This provides better isolation than running the executor directly on the host
+// and does not require root privileges.
+package container
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+func init() {
+	vmimpl.Register("container", vmimpl.Type{
+		Ctor:       ctor,
+		Overcommit: true,
+	})
+}
+
+type Config struct {
+	// Number of containers to run in parallel (1 by default).
+	Count int `json:"count"`
+	// Parent cgroup v2 dir for per-container cgroups, e.g. a dir in a cgroup subtree
+	// delegated to the user by systemd. The cpu, memory and pids controllers
+	// must be enabled in its cgroup.subtree_control.
+	// Required if any of the limits below is set.
+	Cgroup string `json:"cgroup"`
+	// Number of CPUs available to each container (no limit by default).
+	CPU int `json:"cpu"`
+	// Amount of memory available to each container in MiB (no limit by default).
+	Mem int `json:"mem"`
+	// Max number of processes in each container (no limit by default).
+	Pids int `json:"pids"`
+	// If syz-manager runs as root, the ids of the container with index i are mapped
+	// to the 65536 host ids starting from id_base+i*65536 (1<<20 by default),
+	// so that the container root has no access to the host files beyond other users.
+	// These host ids must not be used by anything else.
+	IDBase int `json:"id_base"`
+}
+
+// The number of uids/gids available in each container.
+const containerIDs = 1 << 16
+
+type Pool struct {
+	env *vmimpl.Env
+	cfg *Config
+}
+
+type instance struct {
+	cfg     *Config
+	workdir string
+	cgroup  string
+	hostID  int // host uid/gid of the container root if running as root
+	port    int
+	merger  *vmimpl.OutputMerger
+	mu      sync.Mutex
+	cmds    map[*exec.Cmd]bool
+}
+
+func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
+	cfg := &Config{
+		Count:  1,
+		IDBase: 1 << 20,
+	}
+	if err := config.LoadData(env.Config, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse container vm config: %w", err)
+	}
+	if cfg.Count < 1 || cfg.Count > 1024 {
+		return nil, fmt.Errorf("invalid config param count: %v, want [1, 1024]", cfg.Count)
+	}
+	if runtime.GOOS != targets.Linux {
+		return nil, fmt.Errorf("container vm type is supported only on linux hosts")
+	}
+	// Other targets run own kernels which can't be put into a container.
+	if target := targets.Get(env.OS, env.Arch); target == nil || !target.HostFuzzer && env.OS != targets.TestOS {
+		return nil, fmt.Errorf("container vm type is supported only for host fuzzer targets")
+	}
+	if cfg.IDBase <= 0 || uint64(cfg.IDBase)+uint64(cfg.Count)*containerIDs > math.MaxUint32 {
+		return nil, fmt.Errorf("invalid config param id_base: %v", cfg.IDBase)
+	}
+	if cfg.CPU < 0 || cfg.Mem < 0 || cfg.Pids < 0 {
+		return nil, fmt.Errorf("container cpu/mem/pids limits must not be negative")
+	}
+	if cfg.Cgroup == "" {
+		if cfg.CPU != 0 || cfg.Mem != 0 || cfg.Pids != 0 {
+			return nil, fmt.Errorf("container cpu/mem/pids limits require cgroup")
+		}
+	} else if !osutil.IsExist(filepath.Join(cfg.Cgroup, "cgroup.subtree_control")) {
+		return nil, fmt.Errorf("cgroup %v is not a cgroup v2 dir", cfg.Cgroup)
+	}
+	pool := &Pool{
+		env: env,
+		cfg: cfg,
+	}
+	return pool, nil
+}
+
+func (pool *Pool) Count() int {
+	return pool.cfg.Count
+}
+
+func (pool *Pool) Create(_ context.Context, workdir string, index int) (vmimpl.Instance, error) {
+	inst := &instance{
+		cfg:     pool.cfg,
+		workdir: workdir,
+		cmds:    make(map[*exec.Cmd]bool),
+	}
+	if os.Getuid() == 0 {
+		// The container root must be able to create files in its workdir.
+		inst.hostID = pool.cfg.IDBase + index*containerIDs
+		if err := os.Chown(workdir, inst.hostID, inst.hostID); err != nil {
+			return nil, fmt.Errorf("failed to chown workdir: %w", err)
+		}
+	}
+	if pool.cfg.Cgroup != "" {
+		inst.cgroup = filepath.Join(pool.cfg.Cgroup, fmt.Sprintf("%v-%v", pool.env.Name, index))
+		if err := inst.createCgroup(); err != nil {
+			return nil, err
+		}
+	}
+	var tee io.Writer
+	if pool.env.Debug {
+		tee = os.Stdout
+	}
+	inst.merger = vmimpl.NewOutputMerger(tee)
+	return inst, nil
+}
+
+func (inst *instance) createCgroup() error {
+	// Kill the previous instance in case it's still running.
+	removeCgroup(inst.cgroup)
+	if err := os.Mkdir(inst.cgroup, 0755); err != nil {
+		return fmt.Errorf("failed to create cgroup: %w", err)
+	}
+	limits := map[string]string{}
+	if inst.cfg.CPU != 0 {
+		const period = 100000
+		limits["cpu.max"] = fmt.Sprintf("%v %v", inst.cfg.CPU*period, period)
+	}
+	if inst.cfg.Mem != 0 {
+		limits["memory.max"] = fmt.Sprint(inst.cfg.Mem << 20)
+	}
+	if inst.cfg.Pids != 0 {
+		limits["pids.max"] = fmt.Sprint(inst.cfg.Pids)
+	}
+	for file, val := range limits {
+		if err := os.WriteFile(filepath.Join(inst.cgroup, file), []byte(val), 0); err != nil {
+			removeCgroup(inst.cgroup)
+			return fmt.Errorf("failed to set cgroup limit: %w", err)
+		}
+	}
+	return nil
+}
+
+func removeCgroup(dir string) {
+	if !osutil.IsExist(dir) {
+		return
+	}
+	// cgroup.kill is supported since Linux 5.14, kill the processes one-by-one on older kernels.
+	if err := os.WriteFile(filepath.Join(dir, "cgroup.kill"), []byte("1"), 0); err != nil {
+		procs, _ := os.ReadFile(filepath.Join(dir, "cgroup.procs"))
+		for _, pid := range strings.Fields(string(procs)) {
+			var p int
+			if _, err := fmt.Sscan(pid, &p); err == nil {
+				syscall.Kill(p, syscall.SIGKILL)
+			}
+		}
+	}
+	// The dir can be removed only after all processes are reaped.
+	for i := 0; i < 100; i++ {
+		if err := syscall.Rmdir(dir); err == nil || errors.Is(err, syscall.ENOENT) {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func (inst *instance) Close() error {
+	inst.mu.Lock()
+	for cmd := range inst.cmds {
+		cmd.Process.Kill()
+	}
+	inst.mu.Unlock()
+	if inst.cgroup != "" {
+		removeCgroup(inst.cgroup)
+	}
+	inst.merger.Wait()
+	return nil
+}
+
+func (inst *instance) Forward(port int) (string, error) {
+	if inst.port != 0 {
+		return "", fmt.Errorf("forward port is already setup")
+	}
+	inst.port = port
+	// The container has own network namespace, so the connection is passed on stdin.
+	return "stdin:0", nil
+}
+
+func (inst *instance) Copy(hostSrc string) (string, error) {
+	dst := filepath.Join(inst.workdir, filepath.Base(hostSrc))
+	if err := osutil.CopyFile(hostSrc, dst); err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+func (inst *instance) Run(ctx context.Context, command string) (
+	<-chan []byte, <-chan error, error) {
+	cmd := osutil.Command("/bin/sh", "-c", command)
+	cmd.Dir = inst.workdir
+	cgroupFile, err := inst.isolate(cmd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if cgroupFile != nil {
+		defer cgroupFile.Close()
+	}
+	managerSock, err := inst.managerProxy()
+	if err != nil {
+		return nil, nil, err
+	}
+	if managerSock != nil {
+		defer managerSock.Close()
+		cmd.Stdin = managerSock
+	}
+	rpipe, wpipe, err := osutil.LongPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer wpipe.Close()
+	cmd.Stdout = wpipe
+	cmd.Stderr = wpipe
+	if err := cmd.Start(); err != nil {
+		rpipe.Close()
+		return nil, nil, fmt.Errorf("failed to start the container: %w", err)
+	}
+	inst.merger.Add("cmd", rpipe)
+	inst.mu.Lock()
+	inst.cmds[cmd] = true
+	inst.mu.Unlock()
+
+	errc := make(chan error, 1)
+	go func() {
+		var err error
+		select {
+		case <-ctx.Done():
+			err = vmimpl.ErrTimeout
+			cmd.Process.Kill()
+			cmd.Wait()
+		case err = <-inst.merger.Err:
+			// The command is the init process of the pid namespace,
+			// once it exits, the rest of the processes are killed by the kernel.
+			if cmdErr := cmd.Wait(); cmdErr == nil {
+				// If the command exited successfully, we got EOF error from merger.
+				// But in this case no error has happened and the EOF is expected.
+				err = nil
+			}
+		}
+		inst.mu.Lock()
+		delete(inst.cmds, cmd)
+		inst.mu.Unlock()
+		errc <- err
+	}()
+	return inst.merger.Output, errc, nil
+}
+
+// managerProxy returns a unix socket connected to the forwarded manager port.
+func (inst *instance) managerProxy() (*os.File, error) {
+	if inst.port == 0 {
+		return nil, nil
+	}
+	socks, err := syscall.Socketpair(syscall.AF_UNIX, syscall.SOCK_STREAM, 0)
+	if err != nil {
+		return nil, err
+	}
+	// The host end must not leak into the container, otherwise it never sees EOF.
+	syscall.CloseOnExec(socks[0])
+	hostSock := os.NewFile(uintptr(socks[0]), "host unix proxy")
+	guestSock := os.NewFile(uintptr(socks[1]), "guest unix proxy")
+	// FileConn dups the fd, the result is close-on-exec as well.
+	hostConn, err := net.FileConn(hostSock)
+	hostSock.Close()
+	if err != nil {
+		guestSock.Close()
+		return nil, err
+	}
+	conn, err := net.Dial("tcp", fmt.Sprintf("localhost:%v", inst.port))
+	if err != nil {
+		hostConn.Close()
+		guestSock.Close()
+		return nil, err
+	}
+	go func() {
+		io.Copy(hostConn, conn)
+		// The other goroutine may still be reading from hostConn, and closing a socket
+		// does not interrupt a pending read, so the container would never see EOF.
+		// Shut down only the write side instead.
+		hostConn.(*net.UnixConn).CloseWrite()
+	}()
+	go func() {
+		io.Copy(conn, hostConn)
+		conn.Close()
+		hostConn.Close()
+	}()
+	return guestSock, nil
+}
+
+func (inst *instance) Info() ([]byte, error) {
+	info := fmt.Sprintf("container: cgroup=%q cpu=%v mem=%v pids=%v\n",
+		inst.cgroup, inst.cfg.CPU, inst.cfg.Mem, inst.cfg.Pids)
+	return []byte(info), nil
+}
+
+func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
+	if inst.cgroup == "" {
+		return nil, false
+	}
+	// Resource limit hits are the most likely reason of unexplained crashes and hangs.
+	var diag []byte
+	for _, file := range []string{"memory.events", "pids.events", "cpu.stat"} {
+		data, err := os.ReadFile(filepath.Join(inst.cgroup, file))
+		if err != nil {
+			continue
+		}
+		diag = append(diag, fmt.Sprintf("%v:\n%s\n", file, data)...)
+	}
+	return diag, false
+}
@@ -0,0 +1,146 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package container
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/vmimpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func createInstance(t *testing.T, cfg string) *instance {
+	if runtime.GOOS != targets.Linux {
+		t.Skip("containers are supported only on linux")
+	}
+	if err := exec.Command("unshare", "-Urnp", "-f", "true").Run(); err != nil {
+		t.Skipf("namespaces are not available: %v", err)
+	}
+	dir := t.TempDir()
+	// The container root may be mapped to an unprivileged host user, which needs to reach the workdir.
+	require.NoError(t, os.Chmod(filepath.Dir(dir), 0755))
+	pool, err := ctor(&vmimpl.Env{
+		Name:    "test",
+		OS:      targets.TestOS,
+		Arch:    targets.TestArch64,
+		Workdir: dir,
+		Config:  []byte(cfg),
+	})
+	require.NoError(t, err)
+	vmInst, err := pool.Create(context.Background(), dir, 0)
+	require.NoError(t, err)
+	inst := vmInst.(*instance)
+	t.Cleanup(func() { inst.Close() })
+	return inst
+}
+
+func runCommand(t *testing.T, inst *instance, timeout time.Duration, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	outc, errc, err := inst.Run(ctx, command)
+	require.NoError(t, err)
+	var output []byte
+	for {
+		select {
+		case out := <-outc:
+			output = append(output, out...)
+		case err := <-errc:
+			// Drain the output that is still buffered in the merger.
+			time.Sleep(100 * time.Millisecond)
+			for len(outc) != 0 {
+				output = append(output, <-outc...)
+			}
+			return string(output), err
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	inst := createInstance(t, `{}`)
+	src := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, osutil.WriteFile(src, []byte("data")))
+	file, err := inst.Copy(src)
+	require.NoError(t, err)
+
+	// The command must be the init process of a new pid namespace.
+	output, err := runCommand(t, inst, time.Minute, fmt.Sprintf("echo pid=$$; cat %v", file))
+	require.NoError(t, err)
+	assert.Equal(t, "pid=1\ndata", strings.TrimSpace(output))
+
+	output, err = runCommand(t, inst, time.Minute, "exit 1")
+	assert.Error(t, err, output)
+
+	_, err = runCommand(t, inst, time.Second, "sleep 1000")
+	assert.ErrorIs(t, err, vmimpl.ErrTimeout)
+}
+
+func TestHostFiles(t *testing.T) {
+	if os.Getuid() != 0 {
+		t.Skip("requires root")
+	}
+	inst := createInstance(t, `{}`)
+	dir := t.TempDir()
+	require.NoError(t, os.Chmod(filepath.Dir(dir), 0755))
+	require.NoError(t, os.Chmod(dir, 0755))
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, []byte("data"), 0644))
+	// The container root is not the host root, so it can read, but not modify the host file.
+	output, err := runCommand(t, inst, time.Minute,
+		fmt.Sprintf("id -u; cat %v; echo; echo foo > %v; touch ./workdir-file", file, file))
+	require.NoError(t, err, output)
+	assert.Contains(t, output, "0\ndata\n")
+	assert.Contains(t, output, "Permission denied")
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, "data", string(data))
+	assert.FileExists(t, filepath.Join(inst.workdir, "workdir-file"))
+}
+
+func TestForward(t *testing.T) {
+	inst := createInstance(t, `{}`)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		conn.Write([]byte("hello from manager\n"))
+		conn.Close()
+	}()
+	addr, err := inst.Forward(ln.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, err)
+	assert.Equal(t, "stdin:0", addr)
+	// The network is isolated, the manager is reachable only via stdin.
+	output, err := runCommand(t, inst, time.Minute, "cat <&0")
+	require.NoError(t, err)
+	assert.Equal(t, "hello from manager\n", output)
+}
+
+func TestConfig(t *testing.T) {
+	env := &vmimpl.Env{
+		OS:     targets.TestOS,
+		Arch:   targets.TestArch64,
+		Config: []byte(`{"mem": 1024}`),
+	}
+	_, err := ctor(env)
+	assert.ErrorContains(t, err, "require cgroup")
+	env.OS, env.Arch = targets.Linux, targets.AMD64
+	env.Config = []byte(`{}`)
+	_, err = ctor(env)
+	assert.ErrorContains(t, err, "host fuzzer targets")
+}
@@ -0,0 +1,49 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// isolate makes cmd start in new namespaces and in the instance cgroup.
+// The returned cgroup file (if any) must be closed after the command has started.
+func (inst *instance) isolate(cmd *exec.Cmd) (*os.File, error) {
+	attr := cmd.SysProcAttr
+	if attr == nil {
+		attr = new(syscall.SysProcAttr)
+		cmd.SysProcAttr = attr
+	}
+	// The user namespace is used even when running as root: mounts in a mount namespace
+	// owned by a less privileged user namespace don't propagate back to the host.
+	attr.Cloneflags = syscall.CLONE_NEWUSER | syscall.CLONE_NEWNS | syscall.CLONE_NEWPID |
+		syscall.CLONE_NEWNET | syscall.CLONE_NEWIPC | syscall.CLONE_NEWUTS
+	if uid := os.Getuid(); uid == 0 {
+		// Keep all ids valid (executor sandboxes switch to nobody), but don't map the host root,
+		// otherwise the container would be able to modify any host file.
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: inst.hostID, Size: containerIDs}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: inst.hostID, Size: containerIDs}}
+		attr.GidMappingsEnableSetgroups = true
+		// The host root is not mapped, so switch to the container root explicitly.
+		attr.Credential = &syscall.Credential{Uid: 0, Gid: 0}
+	} else {
+		// Unprivileged users can map only own ids.
+		attr.UidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: uid, Size: 1}}
+		attr.GidMappings = []syscall.SysProcIDMap{{ContainerID: 0, HostID: os.Getgid(), Size: 1}}
+		attr.GidMappingsEnableSetgroups = false
+	}
+	if inst.cgroup == "" {
+		return nil, nil
+	}
+	f, err := os.Open(inst.cgroup)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open cgroup: %w", err)
+	}
+	attr.UseCgroupFD = true
+	attr.CgroupFD = int(f.Fd())
+	return f, nil
+}
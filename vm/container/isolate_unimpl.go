@@ -0,0 +1,16 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+//go:build !linux
+
+package container
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+func (inst *instance) isolate(cmd *exec.Cmd) (*os.File, error) {
+	return nil, fmt.Errorf("containers are supported only on linux")
+}
@@ -0,0 +1,126 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+)
+
+type FailureKind int
+
+const (
+	// The instance failed to boot.
+	BootFailure FailureKind = iota
+	// The instance failed because of an infrastructure problem.
+	InfraFailure
+	// The connection to a running instance was lost.
+	// It's often caused by a kernel bug, so it's only tracked, but does not lead to quarantine.
+	LostConnection
+)
+
+// Health is the failure history of a pool instance.
+type Health struct {
+	BootFailures    int
+	InfraErrors     int
+	LostConnections int
+	// The number of failures since the last successful run.
+	Consecutive int
+	// The instance is not restarted until this time.
+	QuarantinedUntil time.Time
+}
+
+const (
+	// The quarantine period doubles with every next failure.
+	quarantineBase = time.Minute
+	quarantineMax  = 2 * time.Hour
+)
+
+// SetQuarantine enables quarantine of the instances that fail threshold times in a row:
+// they are taken out of rotation for exponentially growing periods.
+// Quarantine is disabled by default (threshold 0), but the failures are tracked anyway.
+func (p *Pool[T]) SetQuarantine(threshold int) {
+	p.quarantine.Store(int64(threshold))
+}
+
+// ReportFailure records a failure of the instance that happened while running a job.
+func (p *Pool[T]) ReportFailure(idx int, kind FailureKind) {
+	if idx < 0 || idx >= len(p.instances) {
+		return
+	}
+	p.instances[idx].recordFailure(kind, int(p.quarantine.Load()))
+}
+
+// infraErrorer is implemented by errors that are caused by the infrastructure
+// rather than by the kernel (see vm.InfraErrorer).
+type infraErrorer interface {
+	InfraError() (string, []byte)
+}
+
+func bootFailureKind(err error) FailureKind {
+	var infraErr infraErrorer
+	if errors.As(err, &infraErr) {
+		return InfraFailure
+	}
+	return BootFailure
+}
+
+func (pi *poolInstance[T]) recordFailure(kind FailureKind, threshold int) {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	health := &pi.info.Health
+	pi.runFailed = true
+	switch kind {
+	case BootFailure:
+		health.BootFailures++
+	case InfraFailure:
+		health.InfraErrors++
+	case LostConnection:
+		health.LostConnections++
+		return
+	}
+	health.Consecutive++
+	if threshold <= 0 || health.Consecutive < threshold {
+		return
+	}
+	period := quarantineMax
+	if shift := health.Consecutive - threshold; shift < 16 {
+		period = min(quarantineBase<<shift, quarantineMax)
+	}
+	health.QuarantinedUntil = time.Now().Add(period)
+	log.Logf(0, "pool: quarantining instance %d for %v after %d consecutive failures",
+		pi.idx, period, health.Consecutive)
+}
+
+// recordRun is called after a job has finished on the instance.
+func (pi *poolInstance[T]) recordRun() {
+	pi.mu.Lock()
+	defer pi.mu.Unlock()
+	if pi.runFailed {
+		return
+	}
+	if !pi.info.Health.QuarantinedUntil.IsZero() {
+		log.Logf(0, "pool: instance %d has recovered", pi.idx)
+	}
+	pi.info.Health.Consecutive = 0
+	pi.info.Health.QuarantinedUntil = time.Time{}
+}
+
+// waitQuarantine returns false if ctx was cancelled while waiting.
+func (pi *poolInstance[T]) waitQuarantine(ctx context.Context) bool {
+	wait := time.Until(pi.getInfo().Health.QuarantinedUntil)
+	if wait <= 0 {
+		return true
+	}
+	pi.status(StateQuarantined)
+	select {
+	case <-time.After(wait):
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
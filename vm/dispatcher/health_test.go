@@ -0,0 +1,127 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testQuarantine = 3
+
+func TestInstanceQuarantine(t *testing.T) {
+	inst := &poolInstance[*nilInstance]{}
+	inst.reset(func() {})
+	for i := 0; i < testQuarantine-1; i++ {
+		inst.recordFailure(BootFailure, testQuarantine)
+	}
+	// Lost connections are likely kernel bugs, they are not counted.
+	inst.recordFailure(LostConnection, testQuarantine)
+	assert.True(t, inst.getInfo().Health.QuarantinedUntil.IsZero())
+
+	inst.recordFailure(InfraFailure, testQuarantine)
+	until := inst.getInfo().Health.QuarantinedUntil
+	assert.InDelta(t, quarantineBase, time.Until(until), float64(time.Second))
+
+	// The period grows exponentially.
+	inst.recordFailure(InfraFailure, testQuarantine)
+	until = inst.getInfo().Health.QuarantinedUntil
+	assert.InDelta(t, 2*quarantineBase, time.Until(until), float64(time.Second))
+	for i := 0; i < 10; i++ {
+		inst.recordFailure(InfraFailure, testQuarantine)
+	}
+	until = inst.getInfo().Health.QuarantinedUntil
+	assert.InDelta(t, quarantineMax, time.Until(until), float64(time.Second))
+
+	// A failed run does not reset the history.
+	inst.recordRun()
+	assert.False(t, inst.getInfo().Health.QuarantinedUntil.IsZero())
+
+	// A successful one does.
+	inst.reset(func() {})
+	inst.recordRun()
+	health := inst.getInfo().Health
+	assert.Equal(t, Health{
+		BootFailures:    testQuarantine - 1,
+		InfraErrors:     12,
+		LostConnections: 1,
+	}, health)
+}
+
+func TestInstanceNoQuarantine(t *testing.T) {
+	inst := &poolInstance[*nilInstance]{}
+	inst.reset(func() {})
+	for i := 0; i < 10; i++ {
+		inst.recordFailure(InfraFailure, 0)
+	}
+	health := inst.getInfo().Health
+	assert.True(t, health.QuarantinedUntil.IsZero())
+	assert.Equal(t, 10, health.Consecutive)
+}
+
+type testInfraError struct{}
+
+func (testInfraError) Error() string {
+	return "infra error"
+}
+
+func (testInfraError) InfraError() (string, []byte) {
+	return "infra error", nil
+}
+
+func TestPoolQuarantine(t *testing.T) {
+	const count = 3
+	pool := makePool(count)
+	mgr := NewPool[*testInstance](
+		count,
+		func(_ context.Context, idx int) (*testInstance, error) {
+			if idx == 0 {
+				return nil, fmt.Errorf("wrapped: %w", testInfraError{})
+			}
+			pool[idx].reset()
+			return &pool[idx], nil
+		},
+		func(ctx context.Context, inst *testInstance, _ UpdateInfo) {
+			pool[inst.Index()].run(ctx)
+		},
+	)
+	mgr.SetQuarantine(testQuarantine)
+	done := make(chan bool)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		mgr.Loop(ctx)
+		close(done)
+	}()
+	go func() {
+		for range mgr.BootErrors {
+		}
+	}()
+
+	// The broken instance eventually gets quarantined, while the others keep working.
+	for mgr.State()[0].State != StateQuarantined {
+		time.Sleep(10 * time.Millisecond)
+	}
+	health := mgr.State()[0].Health
+	assert.Equal(t, testQuarantine, health.InfraErrors)
+	assert.Equal(t, testQuarantine, health.Consecutive)
+	pool[1].waitRun()
+	pool[2].waitRun()
+
+	// Infrastructure errors during runs count as well.
+	for i := 0; i < testQuarantine; i++ {
+		mgr.ReportFailure(1, InfraFailure)
+	}
+	pool[1].stopRun()
+	for mgr.State()[1].State != StateQuarantined {
+		time.Sleep(10 * time.Millisecond)
+	}
+	assert.Equal(t, testQuarantine, mgr.State()[1].Health.InfraErrors)
+
+	cancel()
+	<-done
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"io"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/syzkaller/pkg/log"
@@ -38,6 +39,14 @@ type Pool[T Instance] struct {
 	cv        *sync.Cond
 	instances []*poolInstance[T]
 	paused    bool
+	// The number of instances that are allowed to run (the rest are parked).
+	active  int
+	scaling *scaling
+
+	bootAttempts atomic.Int64
+	bootFailures atomic.Int64
+	// The number of consecutive failures that leads to quarantine (0 means no quarantine).
+	quarantine atomic.Int64
 }
 
 const bootErrorChanCap = 16
@@ -58,6 +67,7 @@ func NewPool[T Instance](count int, creator CreateInstance[T], def Runner[T]) *P
 		creator:    creator,
 		defaultJob: def,
 		instances:  instances,
+		active:     count,
 		jobs:       make(chan Runner[T]),
 		mu:         mu,
 		cv:         sync.NewCond(mu),
@@ -91,10 +101,10 @@ func (p *Pool[T]) TogglePause(paused bool) {
 	}
 }
 
-func (p *Pool[T]) waitUnpaused() {
+func (p *Pool[T]) waitUnpaused(ctx context.Context, inst *poolInstance[T]) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	for p.paused {
+	for (p.paused || inst.parked) && ctx.Err() == nil {
 		p.cv.Wait()
 	}
 }
@@ -102,6 +112,13 @@ func (p *Pool[T]) waitUnpaused() {
 func (p *Pool[T]) Loop(ctx context.Context) {
 	var wg sync.WaitGroup
 	wg.Add(len(p.instances))
+	go func() {
+		// Wake up the paused and parked instances.
+		<-ctx.Done()
+		p.mu.Lock()
+		p.cv.Broadcast()
+		p.mu.Unlock()
+	}()
 	for _, inst := range p.instances {
 		go func() {
 			for ctx.Err() == nil {
@@ -114,19 +131,32 @@ func (p *Pool[T]) Loop(ctx context.Context) {
 }
 
 func (p *Pool[T]) runInstance(ctx context.Context, inst *poolInstance[T]) {
-	p.waitUnpaused()
+	p.waitUnpaused(ctx, inst)
+	if ctx.Err() != nil {
+		return
+	}
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	log.Logf(2, "pool: booting instance %d", inst.idx)
 
 	inst.reset(cancel)
+	defer inst.status(StateOffline)
+	if !inst.waitQuarantine(ctx) {
+		return
+	}
 
 	start := time.Now()
 	inst.status(StateBooting)
-	defer inst.status(StateOffline)
 
 	obj, err := p.creator(ctx, inst.idx)
+	if ctx.Err() == nil {
+		p.bootAttempts.Add(1)
+	}
 	if err != nil {
+		if ctx.Err() == nil {
+			p.bootFailures.Add(1)
+			inst.recordFailure(bootFailureKind(err), int(p.quarantine.Load()))
+		}
 		p.reportBootError(ctx, err)
 		return
 	}
@@ -153,6 +183,7 @@ func (p *Pool[T]) runInstance(ctx context.Context, inst *poolInstance[T]) {
 
 	inst.status(StateRunning)
 	job(ctx, obj, inst.updateInfo)
+	inst.recordRun()
 }
 
 func (p *Pool[T]) reportBootError(ctx context.Context, err error) {
@@ -181,19 +212,27 @@ func (p *Pool[T]) ReserveForRun(count int) {
 		panic("trying to reserve more VMs than present")
 	}
 
-	var free, reserved []*poolInstance[T]
+	var free, parked, reserved []*poolInstance[T]
 	for _, inst := range p.instances {
 		if inst.reserved() {
 			reserved = append(reserved, inst)
+		} else if inst.parked {
+			parked = append(parked, inst)
 		} else {
 			free = append(free, inst)
 		}
 	}
+	// Prefer the already running instances, but reserved instances must always be active.
+	free = append(free, parked...)
 
 	needReserve := count - len(reserved)
 	for i := 0; i < needReserve; i++ {
 		log.Logf(2, "pool: reserving instance %d", free[i].idx)
 		free[i].reserve(p.jobs)
+		if free[i].parked {
+			free[i].unpark()
+			p.cv.Broadcast()
+		}
 	}
 
 	needFree := len(reserved) - count
@@ -201,6 +240,7 @@ func (p *Pool[T]) ReserveForRun(count int) {
 		log.Logf(2, "pool: releasing instance %d", reserved[i].idx)
 		reserved[i].free(p.defaultJob)
 	}
+	p.applyActiveLocked()
 }
 
 // Run blocks until it has found an instance to execute job and until job has finished.
@@ -233,6 +273,9 @@ type Info struct {
 	Status     string
 	LastUpdate time.Time
 	Reserved   bool
+	// The instance is stopped by the scaling policy.
+	Parked bool
+	Health Health
 
 	// The optional callbacks.
 	MachineInfo    func() []byte
@@ -261,6 +304,10 @@ type poolInstance[T Instance] struct {
 	jobChan     chan Runner[T]
 	switchToJob chan Runner[T]
 	stop        func()
+	// Protected by the pool mutex.
+	parked bool
+	// Whether a failure was reported since the last reset().
+	runFailed bool
 }
 
 type InstanceState int
@@ -270,6 +317,7 @@ const (
 	StateBooting
 	StateWaiting
 	StateRunning
+	StateQuarantined
 )
 
 // reset() and status() may be called concurrently to all other methods.
@@ -282,7 +330,10 @@ func (pi *poolInstance[T]) reset(stop func()) {
 		State:      StateOffline,
 		LastUpdate: time.Now(),
 		Reserved:   pi.info.Reserved,
+		Parked:     pi.info.Parked,
+		Health:     pi.info.Health,
 	}
+	pi.runFailed = false
 	pi.stop = stop
 	pi.switchToJob = make(chan Runner[T])
 }
@@ -320,6 +371,21 @@ func (pi *poolInstance[T]) reserve(ch chan Runner[T]) {
 	pi.mu.Unlock()
 }
 
+func (pi *poolInstance[T]) park() {
+	pi.mu.Lock()
+	pi.stop()
+	pi.parked = true
+	pi.info.Parked = true
+	pi.mu.Unlock()
+}
+
+func (pi *poolInstance[T]) unpark() {
+	pi.mu.Lock()
+	pi.parked = false
+	pi.info.Parked = false
+	pi.mu.Unlock()
+}
+
 func (pi *poolInstance[T]) free(job Runner[T]) {
 	pi.mu.Lock()
 	if pi.job != nil {
@@ -0,0 +1,179 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// ScaleMetrics is the input of a scaling decision.
+type ScaleMetrics struct {
+	// The total number of instances in the pool.
+	Total int
+	// The number of instances that are currently allowed to run.
+	Active int
+	// The number of instances reserved for custom runners (e.g. for bug reproduction).
+	// Reserved instances are always kept active.
+	Reserved int
+	// The share of failed instance boots since the previous decision (0 if there were no boots).
+	BootFailureRate float64
+	Host            osutil.HostLoad
+}
+
+// ScalingPolicy decides how many instances of the pool should be running.
+type ScalingPolicy interface {
+	// Scale returns the desired number of active instances.
+	// The pool clamps the result to the configured bounds.
+	Scale(m ScaleMetrics) int
+}
+
+// LoadPolicy shrinks the pool when the host is overloaded or instances fail to boot
+// and gradually grows it back when the host is idle.
+type LoadPolicy struct {
+	// Shrink the pool if CPU or memory pressure (in percent) exceeds these values.
+	MaxCPUPressure float64
+	MaxMemPressure float64
+	// Grow the pool only if CPU pressure is below this value.
+	MinCPUPressure float64
+	// Shrink the pool if the share of failed boots exceeds this value (0 disables the check).
+	MaxBootFailureRate float64
+}
+
+func (lp *LoadPolicy) Scale(m ScaleMetrics) int {
+	switch {
+	case m.Host.CPUPressure > lp.MaxCPUPressure || m.Host.MemPressure > lp.MaxMemPressure:
+		// Back off quickly, someone else needs the machine.
+		return m.Active - max(1, m.Active/4)
+	case lp.MaxBootFailureRate > 0 && m.BootFailureRate > lp.MaxBootFailureRate:
+		return m.Active - 1
+	case m.Host.CPUPressure < lp.MinCPUPressure:
+		return m.Active + 1
+	}
+	return m.Active
+}
+
+type scaling struct {
+	policy   ScalingPolicy
+	min, max int
+	// The boot counters at the time of the previous decision.
+	lastAttempts int64
+	lastFailures int64
+}
+
+// SetScaling enables dynamic adjustment of the number of running instances.
+// The number of active instances is kept within [min, max], but reserved
+// instances are always active.
+func (p *Pool[T]) SetScaling(policy ScalingPolicy, minActive, maxActive int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	maxActive = min(maxActive, len(p.instances))
+	minActive = min(minActive, maxActive)
+	p.scaling = &scaling{
+		policy: policy,
+		min:    minActive,
+		max:    maxActive,
+	}
+	p.active = max(minActive, min(p.active, maxActive))
+	p.applyActiveLocked()
+}
+
+// ScaleLoop periodically re-evaluates the scaling policy set by SetScaling.
+// If the host load is not available (e.g. on non-linux hosts), the policy
+// is evaluated with zero host load, i.e. only on the boot failures.
+func (p *Pool[T]) ScaleLoop(ctx context.Context, interval time.Duration) {
+	_, err := osutil.ReadHostLoad()
+	hostLoad := err == nil
+	if !hostLoad {
+		log.Logf(0, "pool: host load based scaling is disabled: %v", err)
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+		var load osutil.HostLoad
+		if hostLoad {
+			load, err = osutil.ReadHostLoad()
+			if err != nil {
+				log.Logf(0, "pool: failed to query host load: %v", err)
+				continue
+			}
+		}
+		p.scale(load)
+	}
+}
+
+// Active returns the number of instances that are currently allowed to run.
+func (p *Pool[T]) Active() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.active
+}
+
+func (p *Pool[T]) scale(load osutil.HostLoad) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	s := p.scaling
+	if s == nil {
+		return
+	}
+	attempts, failures := p.bootAttempts.Load(), p.bootFailures.Load()
+	m := ScaleMetrics{
+		Total:    len(p.instances),
+		Active:   p.active,
+		Reserved: p.reservedLocked(),
+		Host:     load,
+	}
+	if attempts > s.lastAttempts {
+		m.BootFailureRate = float64(failures-s.lastFailures) / float64(attempts-s.lastAttempts)
+	}
+	s.lastAttempts, s.lastFailures = attempts, failures
+	active := max(s.min, min(s.policy.Scale(m), s.max))
+	if active == p.active {
+		return
+	}
+	log.Logf(0, "pool: scaling from %v to %v active instances (%v, boot failures %.0f%%)",
+		p.active, active, load, m.BootFailureRate*100)
+	p.active = active
+	p.applyActiveLocked()
+}
+
+func (p *Pool[T]) reservedLocked() int {
+	reserved := 0
+	for _, inst := range p.instances {
+		if inst.reserved() {
+			reserved++
+		}
+	}
+	return reserved
+}
+
+// applyActiveLocked parks or unparks instances to match the desired active count.
+// Reserved instances are never parked, so the actual number of active instances may be larger.
+func (p *Pool[T]) applyActiveLocked() {
+	active := p.reservedLocked()
+	for _, inst := range p.instances {
+		if inst.reserved() {
+			continue
+		}
+		if active < p.active {
+			active++
+			if inst.parked {
+				log.Logf(2, "pool: unparking instance %d", inst.idx)
+				inst.unpark()
+				p.cv.Broadcast()
+			}
+		} else if !inst.parked {
+			log.Logf(2, "pool: parking instance %d", inst.idx)
+			inst.park()
+		}
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package dispatcher
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadPolicy(t *testing.T) {
+	policy := &LoadPolicy{
+		MaxCPUPressure:     20,
+		MaxMemPressure:     10,
+		MinCPUPressure:     5,
+		MaxBootFailureRate: 0.5,
+	}
+	tests := []struct {
+		metrics ScaleMetrics
+		active  int
+	}{
+		{ScaleMetrics{Active: 8, Host: osutil.HostLoad{CPUPressure: 10}}, 8},
+		{ScaleMetrics{Active: 8, Host: osutil.HostLoad{CPUPressure: 1}}, 9},
+		{ScaleMetrics{Active: 8, Host: osutil.HostLoad{CPUPressure: 50}}, 6},
+		{ScaleMetrics{Active: 2, Host: osutil.HostLoad{CPUPressure: 50}}, 1},
+		{ScaleMetrics{Active: 8, Host: osutil.HostLoad{MemPressure: 30}}, 6},
+		{ScaleMetrics{Active: 8, BootFailureRate: 1}, 7},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.active, policy.Scale(test.metrics), "test #%v", i)
+	}
+}
+
+type fixedPolicy struct {
+	active  atomic.Int64
+	metrics ScaleMetrics
+}
+
+func (fp *fixedPolicy) Scale(m ScaleMetrics) int {
+	fp.metrics = m
+	return int(fp.active.Load())
+}
+
+func TestPoolScaling(t *testing.T) {
+	const count = 4
+	var running atomic.Int64
+	mgr := NewPool[*nilInstance](
+		count,
+		func(_ context.Context, idx int) (*nilInstance, error) {
+			return &nilInstance{}, nil
+		},
+		func(ctx context.Context, _ *nilInstance, _ UpdateInfo) {
+			running.Add(1)
+			<-ctx.Done()
+			running.Add(-1)
+		},
+	)
+	done := make(chan bool)
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		mgr.Loop(ctx)
+		close(done)
+	}()
+	waitRunning := func(want int) {
+		for running.Load() != int64(want) {
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	waitRunning(count)
+
+	policy := new(fixedPolicy)
+	policy.active.Store(count)
+	mgr.SetScaling(policy, 1, 3)
+	assert.Equal(t, 3, mgr.Active())
+	waitRunning(3)
+
+	policy.active.Store(0)
+	mgr.scale(osutil.HostLoad{})
+	assert.Equal(t, 1, mgr.Active())
+	assert.Equal(t, 3, policy.metrics.Active)
+	waitRunning(1)
+
+	// Reserved instances are always active.
+	mgr.ReserveForRun(2)
+	assert.Equal(t, 1, mgr.Active())
+	parked := 0
+	for _, info := range mgr.State() {
+		if info.Parked {
+			assert.False(t, info.Reserved)
+			parked++
+		}
+	}
+	assert.Equal(t, 2, parked)
+	waitRunning(0)
+
+	mgr.ReserveForRun(0)
+	policy.active.Store(count)
+	mgr.scale(osutil.HostLoad{})
+	assert.Equal(t, 0, policy.metrics.Reserved)
+	assert.Equal(t, 1, policy.metrics.Active)
+	assert.Equal(t, 3, mgr.Active())
+	waitRunning(3)
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,263 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package firecracker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// apiClient talks to the Firecracker REST API served over a unix socket.
+// See https://github.com/firecracker-microvm/firecracker/blob/main/src/firecracker/swagger/firecracker.yaml
+type apiClient struct {
+	client *http.Client
+}
+
+func newAPIClient(sock string) *apiClient {
+	return &apiClient{
+		client: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+					var d net.Dialer
+					return d.DialContext(ctx, "unix", sock)
+				},
+			},
+			Timeout: time.Minute,
+		},
+	}
+}
+
+func (api *apiClient) put(path string, req any) error {
+	return api.call(http.MethodPut, path, req)
+}
+
+func (api *apiClient) patch(path string, req any) error {
+	return api.call(http.MethodPatch, path, req)
+}
+
+func (api *apiClient) call(method, path string, req any) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	httpReq, err := http.NewRequest(method, "http://localhost"+path, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	resp, err := api.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("firecracker api %v %v failed: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	var fault struct {
+		FaultMessage string `json:"fault_message"`
+	}
+	body, _ := io.ReadAll(resp.Body)
+	if json.Unmarshal(body, &fault) != nil || fault.FaultMessage == "" {
+		fault.FaultMessage = string(body)
+	}
+	return fmt.Errorf("firecracker api %v %v failed: %v: %v", method, path, resp.Status, fault.FaultMessage)
+}
+
+// wait waits until Firecracker starts serving the API socket.
+func (api *apiClient) wait(ctx context.Context, exited <-chan error) error {
+	for {
+		resp, err := api.client.Get("http://localhost/")
+		if err == nil {
+			resp.Body.Close()
+			return nil
+		}
+		select {
+		case <-time.After(10 * time.Millisecond):
+		case err := <-exited:
+			return fmt.Errorf("firecracker exited: %w", err)
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+type bootSource struct {
+	KernelImagePath string `json:"kernel_image_path"`
+	InitrdPath      string `json:"initrd_path,omitempty"`
+	BootArgs        string `json:"boot_args"`
+}
+
+type drive struct {
+	DriveID      string `json:"drive_id"`
+	PathOnHost   string `json:"path_on_host"`
+	IsRootDevice bool   `json:"is_root_device"`
+	IsReadOnly   bool   `json:"is_read_only"`
+}
+
+type machineConfig struct {
+	VcpuCount  int `json:"vcpu_count"`
+	MemSizeMib int `json:"mem_size_mib"`
+}
+
+type vsock struct {
+	GuestCID int    `json:"guest_cid"`
+	UDSPath  string `json:"uds_path"`
+}
+
+type action struct {
+	ActionType string `json:"action_type"`
+}
+
+type vmState struct {
+	State string `json:"state"`
+}
+
+type snapshotCreate struct {
+	SnapshotType string `json:"snapshot_type"`
+	SnapshotPath string `json:"snapshot_path"`
+	MemFilePath  string `json:"mem_file_path"`
+}
+
+type memBackend struct {
+	BackendType string `json:"backend_type"`
+	BackendPath string `json:"backend_path"`
+}
+
+type snapshotLoad struct {
+	SnapshotPath string     `json:"snapshot_path"`
+	MemBackend   memBackend `json:"mem_backend"`
+	ResumeVM     bool       `json:"resume_vm"`
+}
+
+// vsockProxy accepts TCP connections on a local port and forwards them to a guest vsock port
+// using the Firecracker host-initiated connection protocol:
+// https://github.com/firecracker-microvm/firecracker/blob/main/docs/vsock.md
+type vsockProxy struct {
+	ln    net.Listener
+	uds   string
+	port  int
+	mu    sync.Mutex
+	conns map[net.Conn]bool
+}
+
+func newVsockProxy(uds string, port int) (*vsockProxy, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	proxy := &vsockProxy{
+		ln:    ln,
+		uds:   uds,
+		port:  port,
+		conns: make(map[net.Conn]bool),
+	}
+	go proxy.loop()
+	return proxy, nil
+}
+
+func (proxy *vsockProxy) Port() int {
+	return proxy.ln.Addr().(*net.TCPAddr).Port
+}
+
+func (proxy *vsockProxy) Close() error {
+	err := proxy.ln.Close()
+	proxy.mu.Lock()
+	for conn := range proxy.conns {
+		conn.Close()
+	}
+	proxy.conns = nil
+	proxy.mu.Unlock()
+	return err
+}
+
+func (proxy *vsockProxy) loop() {
+	for {
+		conn, err := proxy.ln.Accept()
+		if err != nil {
+			return
+		}
+		go proxy.serve(conn)
+	}
+}
+
+func (proxy *vsockProxy) serve(conn net.Conn) {
+	guest, rd, err := proxy.dial()
+	if err != nil {
+		conn.Close()
+		return
+	}
+	if !proxy.track(conn, guest) {
+		return
+	}
+	defer proxy.untrack(conn, guest)
+	done := make(chan bool, 2)
+	go func() {
+		io.Copy(guest, conn)
+		done <- true
+	}()
+	go func() {
+		io.Copy(conn, rd)
+		done <- true
+	}()
+	<-done
+}
+
+// dial connects to the guest port and returns the connection along with a reader
+// that holds any data the guest sent right after the handshake.
+func (proxy *vsockProxy) dial() (net.Conn, io.Reader, error) {
+	conn, err := net.DialTimeout("unix", proxy.uds, time.Minute)
+	if err != nil {
+		return nil, nil, err
+	}
+	conn.SetDeadline(time.Now().Add(time.Minute))
+	if _, err := fmt.Fprintf(conn, "CONNECT %v\n", proxy.port); err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	rd := bufio.NewReader(conn)
+	reply, err := rd.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("vsock handshake failed: %w", err)
+	}
+	if !strings.HasPrefix(reply, "OK ") {
+		conn.Close()
+		return nil, nil, fmt.Errorf("vsock handshake failed: %q", reply)
+	}
+	conn.SetDeadline(time.Time{})
+	return conn, rd, nil
+}
+
+func (proxy *vsockProxy) track(conns ...net.Conn) bool {
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	if proxy.conns == nil {
+		for _, conn := range conns {
+			conn.Close()
+		}
+		return false
+	}
+	for _, conn := range conns {
+		proxy.conns[conn] = true
+	}
+	return true
+}
+
+func (proxy *vsockProxy) untrack(conns ...net.Conn) {
+	proxy.mu.Lock()
+	defer proxy.mu.Unlock()
+	for _, conn := range conns {
+		conn.Close()
+		delete(proxy.conns, conn)
+	}
+}
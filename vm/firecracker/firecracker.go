@@ -0,0 +1,512 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package firecracker provides support for Firecracker microVMs.
+// See https://github.com/firecracker-microvm/firecracker
+//
+// Firecracker VMs have no network devices, the host talks to the guest over vsock.
+// The image must run sshd accessible on vsock port ssh_port, e.g. with
+// "socat VSOCK-LISTEN:22,fork TCP:127.0.0.1:22" started at boot.
+// With fast_boot, one VM is booted and snapshotted, and all instances are
+// restored from this snapshot instead of booting the kernel from scratch.
+// Snapshot mode (the snapshot manager config parameter) is supported as well,
+// the executor talks to the host over vsock, see snapshot.go.
+package firecracker
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+func init() {
+	vmimpl.Register("firecracker", vmimpl.Type{
+		Ctor:       ctor,
+		Overcommit: true,
+	})
+}
+
+type Config struct {
+	// Number of VMs to use (default: 1).
+	Count int `json:"count"`
+	// Path to the firecracker binary (default: "firecracker" in PATH).
+	Firecracker string `json:"firecracker"`
+	// Uncompressed kernel image (vmlinux on x86_64, Image on arm64).
+	Kernel string `json:"kernel"`
+	// Optional initrd.
+	Initrd string `json:"initrd"`
+	// Additional kernel command line arguments.
+	Cmdline string `json:"cmdline"`
+	// Number of VM vCPUs (default: 2).
+	CPU int `json:"cpu"`
+	// Amount of VM memory in MiB (default: 1024).
+	Mem int `json:"mem"`
+	// Guest vsock port where sshd is listening (default: 22).
+	SSHPort int `json:"ssh_port"`
+	// Restore instances from a snapshot of a booted VM (default: false).
+	FastBoot bool `json:"fast_boot"`
+}
+
+type Pool struct {
+	env *vmimpl.Env
+	cfg *Config
+
+	// The fast boot snapshot is created on the first Create call.
+	snapshotMu  sync.Mutex
+	snapshotDir string
+}
+
+type instance struct {
+	vmimpl.SSHOptions
+	cfg         *Config
+	env         *vmimpl.Env
+	index       int
+	workdir     string
+	debug       bool
+	cmd         *exec.Cmd
+	exited      chan error
+	api         *apiClient
+	proxy       *vsockProxy
+	merger      *vmimpl.OutputMerger
+	consolew    io.WriteCloser
+	forwardPort int
+	restored    bool
+	snapshot    *snapshot
+}
+
+const (
+	apiSockName   = "api.sock"
+	vsockName     = "v.sock"
+	rootfsName    = "rootfs.img"
+	snapshotName  = "vm.snapshot"
+	memoryName    = "vm.memory"
+	guestCID      = 3
+	bootArgs      = "console=ttyS0 reboot=k panic=1 pci=off"
+	sshBannerWait = 10 * time.Second
+)
+
+func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
+	cfg := &Config{
+		Count:       1,
+		Firecracker: "firecracker",
+		CPU:         2,
+		Mem:         1024,
+		SSHPort:     22,
+	}
+	if err := config.LoadData(env.Config, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse firecracker vm config: %w", err)
+	}
+	if cfg.Count < 1 || cfg.Count > 1024 {
+		return nil, fmt.Errorf("invalid config param count: %v, want [1, 1024]", cfg.Count)
+	}
+	if cfg.CPU < 1 || cfg.CPU > 32 {
+		return nil, fmt.Errorf("invalid config param cpu: %v, want [1, 32]", cfg.CPU)
+	}
+	if cfg.Mem < 128 {
+		return nil, fmt.Errorf("invalid config param mem: %v, want at least 128", cfg.Mem)
+	}
+	if cfg.Kernel == "" {
+		return nil, fmt.Errorf("config param kernel is empty")
+	}
+	if !osutil.IsExist(cfg.Kernel) {
+		return nil, fmt.Errorf("kernel file %q does not exist", cfg.Kernel)
+	}
+	cfg.Kernel = osutil.Abs(cfg.Kernel)
+	if cfg.Initrd != "" {
+		cfg.Initrd = osutil.Abs(cfg.Initrd)
+	}
+	if !osutil.IsExist(env.Image) {
+		return nil, fmt.Errorf("image file %q does not exist", env.Image)
+	}
+	pool := &Pool{
+		env: env,
+		cfg: cfg,
+	}
+	return pool, nil
+}
+
+func (pool *Pool) Count() int {
+	return pool.cfg.Count
+}
+
+func (pool *Pool) Create(ctx context.Context, workdir string, index int) (vmimpl.Instance, error) {
+	var snapshotDir string
+	if pool.cfg.FastBoot {
+		var err error
+		if snapshotDir, err = pool.snapshot(ctx); err != nil {
+			return nil, err
+		}
+	}
+	inst := pool.newInstance(workdir, index)
+	if pool.env.Snapshot {
+		if err := inst.snapshotEnable(); err != nil {
+			return nil, err
+		}
+	}
+	var err error
+	if snapshotDir != "" {
+		err = inst.restore(ctx, snapshotDir)
+	} else {
+		err = inst.boot(ctx, pool.env.Image)
+	}
+	if err != nil {
+		inst.Close()
+		return nil, err
+	}
+	return inst, nil
+}
+
+func (pool *Pool) newInstance(workdir string, index int) *instance {
+	return &instance{
+		cfg:     pool.cfg,
+		env:     pool.env,
+		index:   index,
+		workdir: workdir,
+		debug:   pool.env.Debug,
+		SSHOptions: vmimpl.SSHOptions{
+			Addr: "localhost",
+			Key:  pool.env.SSHKey,
+			User: pool.env.SSHUser,
+		},
+	}
+}
+
+// snapshot boots a template VM and snapshots it, unless it has already been done.
+func (pool *Pool) snapshot(ctx context.Context) (string, error) {
+	pool.snapshotMu.Lock()
+	defer pool.snapshotMu.Unlock()
+	if pool.snapshotDir != "" {
+		return pool.snapshotDir, nil
+	}
+	dir := filepath.Join(pool.env.Workdir, "firecracker-snapshot")
+	os.RemoveAll(dir)
+	if err := osutil.MkdirAll(dir); err != nil {
+		return "", err
+	}
+	log.Logf(0, "firecracker: booting a VM for the fast boot snapshot")
+	inst := pool.newInstance(dir, -1)
+	defer inst.Close()
+	if err := inst.boot(ctx, pool.env.Image); err != nil {
+		return "", err
+	}
+	if err := inst.api.patch("/vm", vmState{State: "Paused"}); err != nil {
+		return "", err
+	}
+	err := inst.api.put("/snapshot/create", snapshotCreate{
+		SnapshotType: "Full",
+		SnapshotPath: snapshotName,
+		MemFilePath:  memoryName,
+	})
+	if err != nil {
+		return "", err
+	}
+	pool.snapshotDir = dir
+	return dir, nil
+}
+
+// start starts a firecracker process in the instance workdir.
+// All paths in the VM configuration are relative to the workdir,
+// so that VMs restored from the same snapshot don't clash.
+func (inst *instance) start(ctx context.Context) error {
+	outr, outw, err := osutil.LongPipe()
+	if err != nil {
+		return err
+	}
+	inr, inw, err := osutil.LongPipe()
+	if err != nil {
+		outr.Close()
+		outw.Close()
+		return err
+	}
+	cmd := osutil.Command(inst.cfg.Firecracker, "--api-sock", apiSockName)
+	cmd.Dir = inst.workdir
+	cmd.Stdin = inr
+	cmd.Stdout = outw
+	cmd.Stderr = outw
+	if err := cmd.Start(); err != nil {
+		outr.Close()
+		outw.Close()
+		inr.Close()
+		inw.Close()
+		return fmt.Errorf("failed to start %v: %w", inst.cfg.Firecracker, err)
+	}
+	outw.Close()
+	inr.Close()
+	inst.cmd = cmd
+	if inst.consolew != nil {
+		inst.consolew.Close()
+	}
+	inst.consolew = inw
+	exited := make(chan error, 1)
+	inst.exited = exited
+	go func() {
+		// The first receive gets the exit error, the rest return immediately.
+		exited <- cmd.Wait()
+		close(exited)
+	}()
+
+	// In snapshot mode the process is restarted, but the output goes to the same merger.
+	if inst.merger == nil {
+		var tee io.Writer
+		if inst.debug {
+			tee = os.Stdout
+		}
+		inst.merger = vmimpl.NewOutputMerger(tee)
+	}
+	inst.merger.Add("console", outr)
+
+	inst.api = newAPIClient(filepath.Join(inst.workdir, apiSockName))
+	return inst.api.wait(ctx, inst.exited)
+}
+
+func (inst *instance) boot(ctx context.Context, image string) error {
+	if err := osutil.CopyFile(image, filepath.Join(inst.workdir, rootfsName)); err != nil {
+		return err
+	}
+	if err := inst.start(ctx); err != nil {
+		return err
+	}
+	args := bootArgs
+	if inst.cfg.Cmdline != "" {
+		args += " " + inst.cfg.Cmdline
+	}
+	for _, call := range []struct {
+		path string
+		req  any
+	}{
+		{"/boot-source", bootSource{
+			KernelImagePath: inst.cfg.Kernel,
+			InitrdPath:      inst.cfg.Initrd,
+			BootArgs:        args,
+		}},
+		{"/drives/rootfs", drive{
+			DriveID:      "rootfs",
+			PathOnHost:   rootfsName,
+			IsRootDevice: true,
+		}},
+		{"/machine-config", machineConfig{
+			VcpuCount:  inst.cfg.CPU,
+			MemSizeMib: inst.cfg.Mem,
+		}},
+		{"/vsock", vsock{
+			GuestCID: guestCID,
+			UDSPath:  vsockName,
+		}},
+		{"/actions", action{ActionType: "InstanceStart"}},
+	} {
+		if err := inst.api.put(call.path, call.req); err != nil {
+			return err
+		}
+	}
+	return inst.waitReady(ctx)
+}
+
+func (inst *instance) restore(ctx context.Context, snapshotDir string) error {
+	// The snapshot refers to the disk by the relative path, the disk state must match the memory state.
+	err := osutil.CopyFile(filepath.Join(snapshotDir, rootfsName), filepath.Join(inst.workdir, rootfsName))
+	if err != nil {
+		return err
+	}
+	if err := inst.start(ctx); err != nil {
+		return err
+	}
+	err = inst.api.put("/snapshot/load", snapshotLoad{
+		SnapshotPath: filepath.Join(snapshotDir, snapshotName),
+		MemBackend: memBackend{
+			BackendType: "File",
+			BackendPath: filepath.Join(snapshotDir, memoryName),
+		},
+		ResumeVM: true,
+	})
+	if err != nil {
+		return err
+	}
+	inst.restored = true
+	return inst.waitReady(ctx)
+}
+
+// waitReady waits until sshd in the guest starts accepting connections over vsock.
+func (inst *instance) waitReady(ctx context.Context) error {
+	proxy, err := newVsockProxy(filepath.Join(inst.workdir, vsockName), inst.cfg.SSHPort)
+	if err != nil {
+		return err
+	}
+	inst.proxy = proxy
+	inst.Port = proxy.Port()
+
+	var bootOutput []byte
+	bootOutputStop := make(chan bool)
+	bootOutputDone := make(chan bool)
+	go func() {
+		defer close(bootOutputDone)
+		for {
+			select {
+			case out := <-inst.merger.Output:
+				bootOutput = append(bootOutput, out...)
+			case <-bootOutputStop:
+				return
+			}
+		}
+	}()
+	collectOutput := func() []byte {
+		close(bootOutputStop)
+		<-bootOutputDone
+		return bootOutput
+	}
+
+	timeout := time.After(10 * time.Minute * inst.env.Timeouts.Scale)
+	for {
+		if inst.sshAlive() {
+			collectOutput()
+			return nil
+		}
+		select {
+		case <-time.After(100 * time.Millisecond):
+		case err := <-inst.exited:
+			return vmimpl.MakeBootError(fmt.Errorf("firecracker exited: %w", err), collectOutput())
+		case <-timeout:
+			return vmimpl.MakeBootError(vmimpl.ErrCantSSH, collectOutput())
+		case <-ctx.Done():
+			collectOutput()
+			return ctx.Err()
+		case <-vmimpl.Shutdown:
+			collectOutput()
+			return fmt.Errorf("shutdown in progress")
+		}
+	}
+}
+
+func (inst *instance) sshAlive() bool {
+	conn, rd, err := inst.proxy.dial()
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(sshBannerWait))
+	banner, err := bufio.NewReader(rd).ReadString('\n')
+	return err == nil && strings.HasPrefix(banner, "SSH-")
+}
+
+func (inst *instance) Close() error {
+	if inst.proxy != nil {
+		inst.proxy.Close()
+	}
+	if inst.snapshot != nil {
+		inst.snapshotClose()
+	}
+	inst.kill()
+	if inst.merger != nil {
+		inst.merger.Wait()
+	}
+	return nil
+}
+
+func (inst *instance) kill() {
+	if inst.consolew != nil {
+		inst.consolew.Close()
+		inst.consolew = nil
+	}
+	if inst.cmd != nil {
+		inst.cmd.Process.Kill()
+		<-inst.exited
+		inst.cmd = nil
+	}
+}
+
+func (inst *instance) Forward(port int) (string, error) {
+	if port == 0 {
+		return "", fmt.Errorf("vm/firecracker: forward port is zero")
+	}
+	if inst.forwardPort != 0 {
+		return "", fmt.Errorf("vm/firecracker: forward port is already set")
+	}
+	inst.forwardPort = port
+	return fmt.Sprintf("localhost:%v", port), nil
+}
+
+func (inst *instance) Copy(hostSrc string) (string, error) {
+	vmDst := filepath.Join(inst.targetDir(), filepath.Base(hostSrc))
+	args := append(vmimpl.SCPArgs(inst.debug, inst.Key, inst.Port, false),
+		hostSrc, inst.User+"@"+inst.Addr+":"+vmDst)
+	if inst.debug {
+		log.Logf(0, "running command: scp %#v", args)
+	}
+	if _, err := osutil.RunCmd(10*time.Minute*inst.env.Timeouts.Scale, "", "scp", args...); err != nil {
+		return "", err
+	}
+	return vmDst, nil
+}
+
+func (inst *instance) targetDir() string {
+	if inst.User == "root" {
+		return "/root"
+	}
+	return "/home/" + inst.User
+}
+
+func (inst *instance) Run(ctx context.Context, command string) (
+	<-chan []byte, <-chan error, error) {
+	rpipe, wpipe, err := osutil.LongPipe()
+	if err != nil {
+		return nil, nil, err
+	}
+	inst.merger.Add("ssh", rpipe)
+
+	var sshArgs []string
+	if inst.forwardPort != 0 {
+		sshArgs = vmimpl.SSHArgsForward(inst.debug, inst.Key, inst.Port, inst.forwardPort, false)
+	} else {
+		sshArgs = vmimpl.SSHArgs(inst.debug, inst.Key, inst.Port, false)
+	}
+	args := append(sshArgs, inst.User+"@"+inst.Addr, "cd "+inst.targetDir()+" && "+command)
+	if inst.debug {
+		log.Logf(0, "running command: ssh %#v", args)
+	}
+	cmd := osutil.Command("ssh", args...)
+	cmd.Dir = inst.workdir
+	cmd.Stdout = wpipe
+	cmd.Stderr = wpipe
+	if err := cmd.Start(); err != nil {
+		wpipe.Close()
+		return nil, nil, err
+	}
+	wpipe.Close()
+	return vmimpl.Multiplex(ctx, cmd, inst.merger, vmimpl.MultiplexConfig{
+		Debug: inst.debug,
+		Scale: inst.env.Timeouts.Scale,
+	})
+}
+
+func (inst *instance) Info() ([]byte, error) {
+	info := fmt.Sprintf("%v --api-sock %v\nkernel: %v\nrestored from snapshot: %v\n",
+		inst.cfg.Firecracker, apiSockName, inst.cfg.Kernel, inst.restored)
+	return []byte(info), nil
+}
+
+func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
+	if inst.env.OS == targets.Linux {
+		if output, wait, handled := vmimpl.DiagnoseLinux(rep, inst.env.Diagnostics, inst.ssh); handled {
+			return output, wait
+		}
+	}
+	return nil, false
+}
+
+func (inst *instance) ssh(args ...string) ([]byte, error) {
+	sshArgs := append(vmimpl.SSHArgs(inst.debug, inst.Key, inst.Port, false), inst.User+"@"+inst.Addr)
+	return osutil.RunCmd(time.Minute*inst.env.Timeouts.Scale, "", "ssh", append(sshArgs, args...)...)
+}
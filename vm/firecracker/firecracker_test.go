@@ -0,0 +1,317 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package firecracker
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/sys/targets"
+	"github.com/google/syzkaller/vm/vmimpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fakeEnv = "SYZ_FAKE_FIRECRACKER"
+
+// The test binary doubles as a fake firecracker binary.
+func TestMain(m *testing.M) {
+	if os.Getenv(fakeEnv) != "" {
+		fakeFirecracker()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// fakeFirecracker implements the subset of the Firecracker API used by the pool.
+// Instead of a guest, it serves an SSH banner and echoes data on vsock connections.
+func fakeFirecracker() {
+	apiSock := flag.String("api-sock", "", "")
+	flag.Parse()
+	ln, err := net.Listen("unix", *apiSock)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to listen: %v\n", err)
+		os.Exit(1)
+	}
+	var udsPath string
+	reply := func(w http.ResponseWriter, err error) {
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"fault_message": err.Error()})
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {})
+	for _, path := range []string{"/boot-source", "/drives/rootfs", "/machine-config"} {
+		mux.HandleFunc("PUT "+path, func(w http.ResponseWriter, r *http.Request) {
+			reply(w, nil)
+		})
+	}
+	mux.HandleFunc("PUT /vsock", func(w http.ResponseWriter, r *http.Request) {
+		var req vsock
+		err := json.NewDecoder(r.Body).Decode(&req)
+		udsPath = req.UDSPath
+		reply(w, err)
+	})
+	mux.HandleFunc("PUT /actions", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Printf("Linux version fake\n")
+		go fakeExecutor(udsPath, false)
+		reply(w, serveVsock(udsPath))
+	})
+	mux.HandleFunc("PATCH /vm", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, nil)
+	})
+	mux.HandleFunc("PUT /snapshot/create", func(w http.ResponseWriter, r *http.Request) {
+		var req snapshotCreate
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			reply(w, err)
+			return
+		}
+		if err := os.WriteFile(req.MemFilePath, nil, 0600); err != nil {
+			reply(w, err)
+			return
+		}
+		reply(w, os.WriteFile(req.SnapshotPath, []byte(udsPath), 0600))
+	})
+	mux.HandleFunc("PUT /snapshot/load", func(w http.ResponseWriter, r *http.Request) {
+		var req snapshotLoad
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			reply(w, err)
+			return
+		}
+		data, err := os.ReadFile(req.SnapshotPath)
+		if err != nil {
+			reply(w, err)
+			return
+		}
+		if _, err := os.Stat(req.MemBackend.BackendPath); err != nil {
+			reply(w, err)
+			return
+		}
+		if _, err := os.Stat(rootfsName); err != nil {
+			reply(w, err)
+			return
+		}
+		fmt.Printf("restored from snapshot\n")
+		udsPath = string(data)
+		// The executor is started only after a fast boot restore.
+		go fakeExecutor(udsPath, filepath.Base(req.SnapshotPath) == snapshotStateName)
+		reply(w, serveVsock(udsPath))
+	})
+	http.Serve(ln, mux)
+}
+
+func serveVsock(path string) error {
+	ln, err := net.Listen("unix", path)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				rd := bufio.NewReader(conn)
+				if line, err := rd.ReadString('\n'); err != nil || line != "CONNECT 22\n" {
+					fmt.Fprintf(conn, "FAILURE\n")
+					return
+				}
+				fmt.Fprintf(conn, "OK 1073741824\nSSH-2.0-fake\r\n")
+				io.Copy(conn, rd)
+			}()
+		}
+	}()
+	return nil
+}
+
+// fakeExecutor talks the snapshot protocol to the host, if the host listens for it.
+func fakeExecutor(udsPath string, restored bool) {
+	conn, err := net.Dial("unix", fmt.Sprintf("%v_%v", udsPath, snapshotPort))
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	recv := func() (flatrpc.SnapshotState, []byte) {
+		var msg snapshotMsg
+		if err := binary.Read(conn, binary.LittleEndian, &msg); err != nil {
+			return 0, nil
+		}
+		data := make([]byte, msg.Size)
+		io.ReadFull(conn, data)
+		return msg.State, data
+	}
+	send := func(state flatrpc.SnapshotState, data []byte) {
+		binary.Write(conn, binary.LittleEndian, snapshotMsg{State: state, Size: uint32(len(data))})
+		conn.Write(data)
+	}
+	if restored {
+		if state, input := recv(); state == flatrpc.SnapshotStateExecute {
+			fmt.Printf("executing %s\n", input)
+			send(flatrpc.SnapshotStateExecuted, append([]byte("result of "), input...))
+		}
+		return
+	}
+	if state, input := recv(); state != flatrpc.SnapshotStateHandshake || string(input) != "handshake" {
+		return
+	}
+	send(flatrpc.SnapshotStateReady, nil)
+	if state, _ := recv(); state == flatrpc.SnapshotStateSnapshotted {
+		send(flatrpc.SnapshotStateExecuted, nil)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	for _, fastBoot := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fast_boot=%v", fastBoot), func(t *testing.T) {
+			testCreate(t, fastBoot)
+		})
+	}
+}
+
+func testCreate(t *testing.T, fastBoot bool) {
+	dir, pool := createPool(t, fastBoot, false)
+	assert.Equal(t, 2, pool.Count())
+
+	for i := 0; i < pool.Count(); i++ {
+		workdir := filepath.Join(dir, fmt.Sprint(i))
+		require.NoError(t, osutil.MkdirAll(workdir))
+		vmInst, err := pool.Create(context.Background(), workdir, i)
+		require.NoError(t, err)
+		inst := vmInst.(*instance)
+		assert.Equal(t, fastBoot, inst.restored)
+		assert.FileExists(t, filepath.Join(workdir, rootfsName))
+
+		// The guest sshd must be reachable through the local port.
+		conn, err := net.DialTimeout("tcp", fmt.Sprintf("127.0.0.1:%v", inst.Port), time.Minute)
+		require.NoError(t, err)
+		rd := bufio.NewReader(conn)
+		banner, err := rd.ReadString('\n')
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(banner, "SSH-"), banner)
+		fmt.Fprintf(conn, "ping\n")
+		line, err := rd.ReadString('\n')
+		require.NoError(t, err)
+		assert.Equal(t, "ping\n", line)
+		conn.Close()
+
+		addr, err := inst.Forward(1234)
+		require.NoError(t, err)
+		assert.Equal(t, "localhost:1234", addr)
+		require.NoError(t, inst.Close())
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	for _, fastBoot := range []bool{false, true} {
+		t.Run(fmt.Sprintf("fast_boot=%v", fastBoot), func(t *testing.T) {
+			testSnapshot(t, fastBoot)
+		})
+	}
+}
+
+func testSnapshot(t *testing.T, fastBoot bool) {
+	dir, pool := createPool(t, fastBoot, true)
+	workdir := filepath.Join(dir, "0")
+	require.NoError(t, osutil.MkdirAll(workdir))
+	vmInst, err := pool.Create(context.Background(), workdir, 0)
+	require.NoError(t, err)
+	defer vmInst.Close()
+	inst := vmInst.(*instance)
+	require.NoError(t, inst.SetupSnapshot([]byte("handshake")))
+	assert.FileExists(t, filepath.Join(workdir, snapshotStateName))
+	assert.FileExists(t, filepath.Join(workdir, snapshotMemName))
+
+	rootfs := filepath.Join(workdir, rootfsName)
+	for _, input := range []string{"prog1", "prog2"} {
+		res, output, err := inst.RunSnapshot(time.Minute, []byte(input))
+		require.NoError(t, err)
+		assert.Equal(t, "result of "+input, string(res))
+		// The console output may be delayed.
+		for !strings.Contains(string(output), "executing "+input) {
+			time.Sleep(10 * time.Millisecond)
+			output = append(output, inst.readOutput()...)
+		}
+		assert.Contains(t, string(output), "restored from snapshot")
+		// The disk must be restored to the snapshot state before the next run.
+		data, err := os.ReadFile(rootfs)
+		require.NoError(t, err)
+		assert.Equal(t, "image", string(data))
+		require.NoError(t, osutil.WriteFile(rootfs, []byte("modified by "+input)))
+	}
+}
+
+func createPool(t *testing.T, fastBoot, snapshot bool) (string, vmimpl.Pool) {
+	t.Setenv(fakeEnv, "1")
+	bin, err := os.Executable()
+	require.NoError(t, err)
+	// Unix socket paths are limited to ~100 bytes, t.TempDir() may be too long.
+	dir, err := os.MkdirTemp("", "syz-firecracker")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	kernel := filepath.Join(dir, "vmlinux")
+	image := filepath.Join(dir, "image")
+	require.NoError(t, osutil.WriteFile(kernel, nil))
+	require.NoError(t, osutil.WriteFile(image, []byte("image")))
+	cfg, err := json.Marshal(map[string]any{
+		"count":       2,
+		"firecracker": bin,
+		"kernel":      kernel,
+		"fast_boot":   fastBoot,
+	})
+	require.NoError(t, err)
+	pool, err := ctor(&vmimpl.Env{
+		OS:       targets.Linux,
+		Workdir:  dir,
+		Image:    image,
+		Timeouts: targets.Timeouts{Scale: 1},
+		Snapshot: snapshot,
+		Config:   cfg,
+	})
+	require.NoError(t, err)
+	return dir, pool
+}
+
+func TestBootFailure(t *testing.T) {
+	dir, err := os.MkdirTemp("", "syz-firecracker")
+	require.NoError(t, err)
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	kernel := filepath.Join(dir, "vmlinux")
+	image := filepath.Join(dir, "image")
+	require.NoError(t, osutil.WriteFile(kernel, nil))
+	require.NoError(t, osutil.WriteFile(image, nil))
+	cfg, err := json.Marshal(map[string]any{
+		"firecracker": "false",
+		"kernel":      kernel,
+	})
+	require.NoError(t, err)
+	pool, err := ctor(&vmimpl.Env{
+		OS:       targets.Linux,
+		Workdir:  dir,
+		Image:    image,
+		Timeouts: targets.Timeouts{Scale: 1},
+		Config:   cfg,
+	})
+	require.NoError(t, err)
+	_, err = pool.Create(context.Background(), dir, 0)
+	assert.ErrorContains(t, err, "firecracker exited")
+}
@@ -0,0 +1,240 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package firecracker
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/google/syzkaller/pkg/flatrpc"
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+// Firecracker VMs have no ivshmem devices that are used in snapshot mode with qemu.
+// Instead, the executor connects to the host over vsock (Firecracker forwards guest-initiated
+// connections to port P to the <vsock uds>_P unix socket) and the host and the executor
+// exchange the snapshot states along with the input/output data as messages.
+// Once the executor is ready, the VM is paused and a full snapshot is created.
+// To run an input, the firecracker process is restarted and the snapshot is loaded.
+// Firecracker resets all vsock connections on restore, so the restored executor
+// connects again and receives the input.
+type snapshot struct {
+	ln   *net.UnixListener
+	conn net.Conn
+	// The rootfs state after the last reset, the disk is reset only if it was modified.
+	disk os.FileInfo
+}
+
+const (
+	// Must match kSnapshotVsockPort in executor/snapshot.h.
+	snapshotPort      = 7788
+	snapshotStateName = "syz.snapshot"
+	snapshotMemName   = "syz.memory"
+	snapshotDiskName  = "syz.rootfs"
+)
+
+// snapshotMsg is the header of the messages, it's followed by Size bytes of input/output data.
+type snapshotMsg struct {
+	State flatrpc.SnapshotState
+	Size  uint32
+	_     uint32
+}
+
+func (inst *instance) snapshotEnable() error {
+	sockPath := filepath.Join(inst.workdir, fmt.Sprintf("%v_%v", vsockName, snapshotPort))
+	ln, err := net.ListenUnix("unix", &net.UnixAddr{Name: sockPath, Net: "unix"})
+	if err != nil {
+		return fmt.Errorf("firecracker: unix listen on %v failed: %w", sockPath, err)
+	}
+	inst.snapshot = &snapshot{ln: ln}
+	return nil
+}
+
+func (inst *instance) snapshotClose() {
+	if inst.snapshot.conn != nil {
+		inst.snapshot.conn.Close()
+	}
+	inst.snapshot.ln.Close()
+}
+
+func (inst *instance) SetupSnapshot(input []byte) error {
+	if inst.snapshot == nil {
+		return fmt.Errorf("firecracker: snapshot mode is not enabled")
+	}
+	if err := inst.snapshotAccept(10 * time.Minute * inst.env.Timeouts.Scale); err != nil {
+		return fmt.Errorf("%w\n%s", err, inst.readOutput())
+	}
+	// Tell executor that we are ready to snapshot and wait for an ack.
+	if err := inst.snapshotSend(flatrpc.SnapshotStateHandshake, input); err != nil {
+		return err
+	}
+	if err := inst.snapshotWait(flatrpc.SnapshotStateReady, 10*time.Minute); err != nil {
+		return fmt.Errorf("executor does not start snapshot handshake: %w\n%s", err, inst.readOutput())
+	}
+	if err := inst.api.patch("/vm", vmState{State: "Paused"}); err != nil {
+		return err
+	}
+	err := inst.api.put("/snapshot/create", snapshotCreate{
+		SnapshotType: "Full",
+		SnapshotPath: snapshotStateName,
+		MemFilePath:  snapshotMemName,
+	})
+	if err != nil {
+		return err
+	}
+	// The snapshot does not include the disk, so save it separately while the VM is paused.
+	rootfs := filepath.Join(inst.workdir, rootfsName)
+	if err := osutil.CopyFile(rootfs, filepath.Join(inst.workdir, snapshotDiskName)); err != nil {
+		return err
+	}
+	if inst.snapshot.disk, err = os.Stat(rootfs); err != nil {
+		return err
+	}
+	if err := inst.api.patch("/vm", vmState{State: "Resumed"}); err != nil {
+		return err
+	}
+	if err := inst.snapshotSend(flatrpc.SnapshotStateSnapshotted, nil); err != nil {
+		return err
+	}
+	if err := inst.snapshotWait(flatrpc.SnapshotStateExecuted, time.Minute); err != nil {
+		return fmt.Errorf("executor has not confirmed snapshot handshake: %w\n%s", err, inst.readOutput())
+	}
+	return nil
+}
+
+func (inst *instance) RunSnapshot(timeout time.Duration, input []byte) (result, output []byte, err error) {
+	if err := inst.snapshotRestore(); err != nil {
+		return nil, nil, fmt.Errorf("%w\n%s", err, inst.readOutput())
+	}
+	if err := inst.snapshotAccept(timeout); err != nil {
+		return nil, nil, fmt.Errorf("%w\n%s", err, inst.readOutput())
+	}
+	if err := inst.snapshotSend(flatrpc.SnapshotStateExecute, input); err != nil {
+		return nil, nil, fmt.Errorf("%w\n%s", err, inst.readOutput())
+	}
+	// If the executor does not reply in time, the kernel has probably crashed or hanged,
+	// the console output will tell.
+	state, res, err := inst.snapshotRecv(timeout)
+	if err != nil || state != flatrpc.SnapshotStateExecuted && state != flatrpc.SnapshotStateFailed {
+		res = nil
+	}
+	return res, inst.readOutput(), nil
+}
+
+// snapshotRestore restarts firecracker with the VM state at the time of the snapshot.
+func (inst *instance) snapshotRestore() error {
+	inst.kill()
+	if err := inst.snapshotResetDisk(); err != nil {
+		return err
+	}
+	// The new process creates the sockets again.
+	for _, name := range []string{apiSockName, vsockName} {
+		os.Remove(filepath.Join(inst.workdir, name))
+	}
+	if err := inst.start(context.Background()); err != nil {
+		return err
+	}
+	return inst.api.put("/snapshot/load", snapshotLoad{
+		SnapshotPath: filepath.Join(inst.workdir, snapshotStateName),
+		MemBackend: memBackend{
+			BackendType: "File",
+			BackendPath: filepath.Join(inst.workdir, snapshotMemName),
+		},
+		ResumeVM: true,
+	})
+}
+
+func (inst *instance) snapshotResetDisk() error {
+	rootfs := filepath.Join(inst.workdir, rootfsName)
+	if stat, err := os.Stat(rootfs); err == nil && stat.Size() == inst.snapshot.disk.Size() &&
+		stat.ModTime().Equal(inst.snapshot.disk.ModTime()) {
+		return nil
+	}
+	if err := osutil.CopyFile(filepath.Join(inst.workdir, snapshotDiskName), rootfs); err != nil {
+		return err
+	}
+	stat, err := os.Stat(rootfs)
+	if err != nil {
+		return err
+	}
+	inst.snapshot.disk = stat
+	return nil
+}
+
+func (inst *instance) snapshotAccept(timeout time.Duration) error {
+	if inst.snapshot.conn != nil {
+		inst.snapshot.conn.Close()
+		inst.snapshot.conn = nil
+	}
+	inst.snapshot.ln.SetDeadline(time.Now().Add(timeout))
+	conn, err := inst.snapshot.ln.Accept()
+	if err != nil {
+		return fmt.Errorf("executor has not connected over vsock: %w", err)
+	}
+	inst.snapshot.conn = conn
+	return nil
+}
+
+func (inst *instance) snapshotSend(state flatrpc.SnapshotState, data []byte) error {
+	conn := inst.snapshot.conn
+	conn.SetWriteDeadline(time.Now().Add(time.Minute))
+	buf, err := binary.Append(nil, binary.LittleEndian, snapshotMsg{
+		State: state,
+		Size:  uint32(len(data)),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(buf, data...)); err != nil {
+		return fmt.Errorf("firecracker: snapshot vsock write failed: %w", err)
+	}
+	return nil
+}
+
+func (inst *instance) snapshotRecv(timeout time.Duration) (flatrpc.SnapshotState, []byte, error) {
+	conn := inst.snapshot.conn
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	var msg snapshotMsg
+	if err := binary.Read(conn, binary.LittleEndian, &msg); err != nil {
+		return 0, nil, err
+	}
+	if msg.Size > uint32(flatrpc.ConstMaxOutputSize) {
+		return 0, nil, fmt.Errorf("too large snapshot output: %v", msg.Size)
+	}
+	data := make([]byte, msg.Size)
+	if _, err := io.ReadFull(conn, data); err != nil {
+		return 0, nil, err
+	}
+	return msg.State, data, nil
+}
+
+func (inst *instance) snapshotWait(want flatrpc.SnapshotState, timeout time.Duration) error {
+	state, _, err := inst.snapshotRecv(timeout)
+	if err != nil {
+		return err
+	}
+	if state != want {
+		return fmt.Errorf("executor snapshot state %v, want %v",
+			flatrpc.EnumNamesSnapshotState[state], flatrpc.EnumNamesSnapshotState[want])
+	}
+	return nil
+}
+
+func (inst *instance) readOutput() []byte {
+	var output []byte
+	for {
+		select {
+		case out := <-inst.merger.Output:
+			output = append(output, out...)
+		default:
+			return output
+		}
+	}
+}
@@ -404,7 +404,7 @@ func waitForConsoleConnect(merger *vmimpl.OutputMerger) error {
 func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
 	switch inst.env.OS {
 	case targets.Linux:
-		output, wait, _ := vmimpl.DiagnoseLinux(rep, inst.ssh)
+		output, wait, _ := vmimpl.DiagnoseLinux(rep, inst.env.Diagnostics, inst.ssh)
 		return output, wait
 	case targets.FreeBSD:
 		return vmimpl.DiagnoseFreeBSD(inst.consolew)
@@ -0,0 +1,59 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package qemu
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"unsafe"
+
+	"golang.org/x/sys/unix"
+)
+
+// availableCPUs returns the host CPUs the current process is allowed to run on.
+func availableCPUs() ([]int, error) {
+	var set unix.CPUSet
+	if err := unix.SchedGetaffinity(0, &set); err != nil {
+		return nil, fmt.Errorf("failed to get cpu affinity: %w", err)
+	}
+	var cpus []int
+	for cpu := 0; cpu < int(unsafe.Sizeof(set))*8; cpu++ {
+		if set.IsSet(cpu) {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+func setAffinity(tid int, cpus []int) error {
+	var set unix.CPUSet
+	for _, cpu := range cpus {
+		set.Set(cpu)
+	}
+	if err := unix.SchedSetaffinity(tid, &set); err != nil {
+		return fmt.Errorf("failed to set cpu affinity of thread %v: %w", tid, err)
+	}
+	return nil
+}
+
+// pinProcess restricts all existing threads of the process to the CPUs.
+// Threads created later inherit the affinity of the creating thread.
+func pinProcess(pid int, cpus []int) error {
+	tasks, err := os.ReadDir(fmt.Sprintf("/proc/%v/task", pid))
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		tid, err := strconv.Atoi(task.Name())
+		if err != nil {
+			continue
+		}
+		if err := setAffinity(tid, cpus); err != nil && !errors.Is(err, unix.ESRCH) {
+			return err
+		}
+	}
+	return nil
+}
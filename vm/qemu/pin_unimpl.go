@@ -0,0 +1,24 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+//go:build !linux
+
+package qemu
+
+import (
+	"fmt"
+)
+
+var errPinNotImplemented = fmt.Errorf("cpu pinning is supported only on linux hosts")
+
+func availableCPUs() ([]int, error) {
+	return nil, errPinNotImplemented
+}
+
+func setAffinity(tid int, cpus []int) error {
+	return errPinNotImplemented
+}
+
+func pinProcess(pid int, cpus []int) error {
+	return errPinNotImplemented
+}
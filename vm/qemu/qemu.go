@@ -21,6 +21,7 @@ import (
 
 	"github.com/google/syzkaller/pkg/config"
 	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/sys/targets"
@@ -74,10 +75,36 @@ type Config struct {
 	CPU int `json:"cpu"`
 	// Amount of VM memory in MiB (1024 by default).
 	Mem int `json:"mem"`
+	// Guest CPU topology (optional). Unset values default to 1,
+	// sockets*cores*threads must be equal to cpu.
+	Sockets int `json:"sockets"`
+	Cores   int `json:"cores"`
+	Threads int `json:"threads"`
+	// Guest NUMA nodes (optional). The total number of CPUs and amount of memory
+	// in the nodes must be equal to cpu and mem.
+	NUMA []NUMANode `json:"numa"`
+	// Pin VMs to host CPUs (false by default). The VM with index i gets CPUs
+	// [i*cpu, (i+1)*cpu) from host_cpus and each vCPU thread is pinned to own CPU.
+	// Requires count*cpu host CPUs.
+	PinCPUs bool `json:"pin_cpus"`
+	// Host CPUs to pin VMs to in the kernel cpu list format, e.g. "0-15,32-47"
+	// (all CPUs available to the manager by default).
+	HostCPUs string `json:"host_cpus"`
 	// For building kernels without -snapshot for pkg/build (true by default).
 	Snapshot bool `json:"snapshot"`
 	// Magic key used to dongle macOS to the device.
 	AppleSmcOsk string `json:"apple_smc_osk"`
+	// Record the VM execution with QEMU icount record/replay (false by default).
+	// Recording works only with TCG, so KVM-specific QEMU arguments are dropped.
+	// The recording can be saved with vm.Instance.SaveRecording and replayed
+	// with tools/syz-replay. Not supported for 9p images and in snapshot mode.
+	RecordReplay bool `json:"record_replay"`
+	// Format of the guest memory dump to take on kernel crashes (no dumps by default).
+	// "elf" produces an ELF core file, "kdump-zlib", "kdump-lzo" and "kdump-snappy"
+	// produce a compressed kdump vmcore that can be analyzed with the crash utility.
+	// The kernel must not reboot on panic (e.g. panic=0 or a large panic= value),
+	// otherwise QEMU exits because of -no-reboot before the dump is taken.
+	MemoryDump string `json:"memory_dump"`
 }
 
 type Pool struct {
@@ -86,6 +113,7 @@ type Pool struct {
 	target     *targets.Target
 	archConfig *archConfig
 	version    string
+	hostCPUs   []int
 }
 
 type instance struct {
@@ -94,6 +122,7 @@ type instance struct {
 	target     *targets.Target
 	archConfig *archConfig
 	version    string
+	hostCPUs   []int // host CPUs the VM is pinned to (if pin_cpus is set)
 	args       []string
 	image      string
 	debug      bool
@@ -103,6 +132,7 @@ type instance struct {
 	timeouts    targets.Timeouts
 	monport     int
 	forwardPort int
+	diagnostics []mgrconfig.Diagnostic
 	mon         net.Conn
 	monEnc      *json.Encoder
 	monDec      *json.Decoder
@@ -302,6 +332,44 @@ func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
 	if cfg.Mem < 128 || cfg.Mem > 1048576 {
 		return nil, fmt.Errorf("bad qemu mem: %v, want [128-1048576]", cfg.Mem)
 	}
+	if err := validateTopology(cfg); err != nil {
+		return nil, err
+	}
+	var hostCPUs []int
+	if cfg.PinCPUs {
+		var err error
+		if cfg.HostCPUs != "" {
+			hostCPUs, err = parseCPUList(cfg.HostCPUs)
+		} else {
+			hostCPUs, err = availableCPUs()
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(hostCPUs) < cfg.Count*cfg.CPU {
+			return nil, fmt.Errorf("pin_cpus requires count*cpu = %v host cpus, but only %v are available",
+				cfg.Count*cfg.CPU, len(hostCPUs))
+		}
+	} else if cfg.HostCPUs != "" {
+		return nil, fmt.Errorf("host_cpus can only be specified with pin_cpus")
+	}
+	switch cfg.MemoryDump {
+	case "", "elf", "kdump-zlib", "kdump-lzo", "kdump-snappy":
+	default:
+		return nil, fmt.Errorf("bad qemu memory_dump: %q, want one of elf/kdump-zlib/kdump-lzo/kdump-snappy",
+			cfg.MemoryDump)
+	}
+	if cfg.RecordReplay {
+		if env.Image == "9p" {
+			return nil, fmt.Errorf("record_replay is not supported for 9p images")
+		}
+		if env.Snapshot {
+			return nil, fmt.Errorf("record_replay is not supported in snapshot mode")
+		}
+		if !archConfig.UseNewQemuImageOptions && cfg.ImageDevice != "hda" {
+			return nil, fmt.Errorf("record_replay requires image_device hda")
+		}
+	}
 	cfg.Kernel = osutil.Abs(cfg.Kernel)
 	cfg.Initrd = osutil.Abs(cfg.Initrd)
 
@@ -317,6 +385,7 @@ func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
 		version:    version,
 		target:     targets.Get(env.OS, env.Arch),
 		archConfig: archConfig,
+		hostCPUs:   hostCPUs,
 	}
 	return pool, nil
 }
@@ -387,6 +456,10 @@ func (pool *Pool) ctor(workdir, sshkey, sshuser string, index int) (*instance, e
 			User: sshuser,
 		},
 	}
+	inst.diagnostics = pool.env.Diagnostics
+	if pool.cfg.PinCPUs {
+		inst.hostCPUs = pinnedCPUs(pool.hostCPUs, pool.cfg.CPU, index)
+	}
 	if pool.env.Snapshot {
 		inst.snapshot = new(snapshot)
 	}
@@ -450,6 +523,11 @@ func (inst *instance) boot() error {
 		log.Logf(0, "running command: %v %#v", inst.cfg.Qemu, args)
 	}
 	inst.args = args
+	if inst.cfg.RecordReplay {
+		if err := inst.saveReplayInfo(); err != nil {
+			return err
+		}
+	}
 	qemu := osutil.Command(inst.cfg.Qemu, args...)
 	qemu.Stdout = inst.wpipe
 	qemu.Stderr = inst.wpipe
@@ -460,6 +538,12 @@ func (inst *instance) boot() error {
 	inst.wpipe = nil
 	inst.qemu = qemu
 	// Qemu has started.
+	if inst.hostCPUs != nil {
+		// Pin what's already there, the vCPU threads are pinned individually once the VM is up.
+		if err := pinProcess(qemu.Process.Pid, inst.hostCPUs); err != nil {
+			return err
+		}
+	}
 
 	// Start output merger.
 	var tee io.Writer
@@ -497,13 +581,18 @@ func (inst *instance) boot() error {
 		return vmimpl.MakeBootError(err, bootOutput)
 	}
 	bootOutputStop <- true
+	if inst.hostCPUs != nil {
+		if err := inst.pinVCPUs(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
 func (inst *instance) buildQemuArgs() ([]string, error) {
 	args := []string{
 		"-m", strconv.Itoa(inst.cfg.Mem),
-		"-smp", strconv.Itoa(inst.cfg.CPU),
+		"-smp", smpArg(inst.cfg),
 		"-chardev", fmt.Sprintf("socket,id=SOCKSYZ,server=on,wait=off,host=localhost,port=%v", inst.monport),
 		"-mon", "chardev=SOCKSYZ,mode=control",
 		"-display", "none",
@@ -511,16 +600,23 @@ func (inst *instance) buildQemuArgs() ([]string, error) {
 		"-no-reboot",
 		"-name", fmt.Sprintf("VM-%v", inst.index),
 	}
+	args = append(args, numaArgs(inst.cfg)...)
 	if inst.archConfig.RngDev != "" {
 		args = append(args, "-device", inst.archConfig.RngDev)
 	}
 	templateDir := filepath.Join(inst.workdir, "template")
-	args = append(args, splitArgs(inst.cfg.QemuArgs, templateDir, inst.index)...)
+	qemuArgs := splitArgs(inst.cfg.QemuArgs, templateDir, inst.index)
+	if inst.cfg.RecordReplay {
+		qemuArgs = tcgArgs(qemuArgs)
+	}
+	args = append(args, qemuArgs...)
 	args = append(args,
 		"-device", inst.cfg.NetDev+",netdev=net0",
 		"-netdev", fmt.Sprintf("user,id=net0,restrict=on,hostfwd=tcp:127.0.0.1:%v-:22", inst.Port),
 	)
-	if inst.image == "9p" {
+	if inst.cfg.RecordReplay {
+		args = append(args, inst.recordArgs()...)
+	} else if inst.image == "9p" {
 		args = append(args,
 			"-fsdev", "local,id=fsdev0,path=/,security_model=none,readonly",
 			"-device", "virtio-9p-pci,fsdev=fsdev0,mount_tag=/dev/root",
@@ -725,18 +821,22 @@ func (inst *instance) Run(ctx context.Context, command string) (
 
 func (inst *instance) Info() ([]byte, error) {
 	info := fmt.Sprintf("%v\n%v %q\n", inst.version, inst.cfg.Qemu, inst.args)
+	if inst.hostCPUs != nil {
+		info += fmt.Sprintf("pinned to host cpus %v\n", inst.hostCPUs)
+	}
 	return []byte(info), nil
 }
 
 func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
+	var ret []byte
+	wait := false
 	if inst.target.OS == targets.Linux {
-		if output, wait, handled := vmimpl.DiagnoseLinux(rep, inst.ssh); handled {
-			return output, wait
-		}
+		// The register dump is still useful after the diagnostic commands (e.g. for hangs).
+		ret, wait, _ = vmimpl.DiagnoseLinux(rep, inst.diagnostics, inst.ssh)
 	}
 	// TODO: we don't need registers on all reports. Probably only relevant for "crashes"
 	// (NULL derefs, paging faults, etc), but is not useful for WARNING/BUG/HANG (?).
-	ret := []byte(fmt.Sprintf("%s Registers:\n", time.Now().Format("15:04:05 ")))
+	ret = append(ret, []byte(fmt.Sprintf("%s Registers:\n", time.Now().Format("15:04:05 ")))...)
 	for cpu := 0; cpu < inst.cfg.CPU; cpu++ {
 		regs, err := inst.hmp("info registers", cpu)
 		if err == nil {
@@ -747,7 +847,7 @@ func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
 			ret = append(ret, []byte(fmt.Sprintf("Failed reading regs: %v\n", err))...)
 		}
 	}
-	return ret, false
+	return ret, wait
 }
 
 func (inst *instance) ssh(args ...string) ([]byte, error) {
@@ -8,6 +8,8 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/google/syzkaller/pkg/log"
@@ -123,6 +125,36 @@ func (inst *instance) qmp(cmd *qmpCommand) (interface{}, error) {
 	return resp.Return, nil
 }
 
+type dumpGuestMemory struct {
+	Paging   bool   `json:"paging"`
+	Protocol string `json:"protocol"`
+	Format   string `json:"format"`
+}
+
+// DumpMemory saves the guest memory with the dump-guest-memory command.
+// The command pauses the VM and returns only after the dump is written.
+func (inst *instance) DumpMemory(file string) (bool, error) {
+	if inst.cfg.MemoryDump == "" {
+		return false, nil
+	}
+	file, err := filepath.Abs(file)
+	if err != nil {
+		return true, err
+	}
+	_, err = inst.qmp(&qmpCommand{
+		Execute: "dump-guest-memory",
+		Arguments: &dumpGuestMemory{
+			Protocol: "file:" + file,
+			Format:   inst.cfg.MemoryDump,
+		},
+	})
+	if err != nil {
+		os.Remove(file)
+		return true, fmt.Errorf("qemu dump-guest-memory failed: %w", err)
+	}
+	return true, nil
+}
+
 func (inst *instance) hmp(cmd string, cpu int) (string, error) {
 	if inst.debug {
 		log.Logf(0, "qemu: running hmp command: %v", cmd)
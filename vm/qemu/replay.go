@@ -0,0 +1,189 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package qemu
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/syzkaller/pkg/osutil"
+)
+
+const (
+	// ReplayLogName is the QEMU record/replay log file.
+	ReplayLogName = "replay.bin"
+	// ReplayInfoName is the file that describes how to replay ReplayLogName.
+	ReplayInfoName = "replay.json"
+)
+
+// Replay describes the QEMU invocation that replays a recorded VM execution.
+// Paths to the log in Args are relative to the dir with the recording.
+// The kernel and the image referenced by Args must be the same as during recording.
+type Replay struct {
+	Qemu string
+	Args []string
+}
+
+// LoadReplay loads the recording saved by SaveRecording from dir.
+func LoadReplay(dir string) (*Replay, error) {
+	if !osutil.IsExist(filepath.Join(dir, ReplayLogName)) {
+		return nil, fmt.Errorf("%v does not contain %v", dir, ReplayLogName)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ReplayInfoName))
+	if err != nil {
+		return nil, err
+	}
+	replay := new(Replay)
+	if err := json.Unmarshal(data, replay); err != nil {
+		return nil, fmt.Errorf("failed to parse %v: %w", ReplayInfoName, err)
+	}
+	return replay, nil
+}
+
+// Command returns the command that replays the recording in dir.
+// The VM is stopped at start and waits for a gdb connection on gdbPort.
+func (replay *Replay) Command(dir string, gdbPort int) *exec.Cmd {
+	args := append([]string{}, replay.Args...)
+	args = append(args, "-S", "-gdb", fmt.Sprintf("tcp:127.0.0.1:%v", gdbPort))
+	cmd := osutil.Command(replay.Qemu, args...)
+	cmd.Dir = dir
+	return cmd
+}
+
+// SaveRecording stops the VM and copies the record/replay log into dir.
+func (inst *instance) SaveRecording(dir string) error {
+	if !inst.cfg.RecordReplay {
+		return errors.New("record_replay is not enabled in the qemu config")
+	}
+	if inst.qemu == nil {
+		return errors.New("qemu is not running")
+	}
+	// QEMU flushes the log only on graceful exit. The VM may have already exited
+	// on its own (e.g. on a kernel panic with -no-reboot), so the error is ignored.
+	inst.qmp(&qmpCommand{Execute: "quit"})
+	done := make(chan error, 1)
+	go func() {
+		done <- inst.qemu.Wait()
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Minute * inst.timeouts.Scale):
+		inst.qemu.Process.Kill()
+		<-done
+		inst.qemu = nil
+		return errors.New("qemu did not exit after quit")
+	}
+	inst.qemu = nil
+	for _, name := range []string{ReplayLogName, ReplayInfoName} {
+		if err := osutil.CopyFile(filepath.Join(inst.workdir, name), filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (inst *instance) saveReplayInfo() error {
+	data, err := json.MarshalIndent(&Replay{
+		Qemu: inst.cfg.Qemu,
+		Args: replayArgs(inst.args),
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+	return osutil.WriteFile(filepath.Join(inst.workdir, ReplayInfoName), data)
+}
+
+func (inst *instance) recordArgs() []string {
+	args := []string{
+		"-icount", icountArg("record", filepath.Join(inst.workdir, ReplayLogName)),
+		"-object", "filter-replay,id=replay,netdev=net0",
+	}
+	if inst.image == "" {
+		return args
+	}
+	// Block devices have to go through the blkreplay driver to be replayed.
+	drive := fmt.Sprintf("file=%v,if=none,format=raw,id=img-direct", inst.image)
+	if inst.cfg.Snapshot {
+		drive += ",snapshot=on"
+	}
+	device := "ide-hd,drive=img-blkreplay"
+	if inst.archConfig.UseNewQemuImageOptions {
+		device = "virtio-blk-device,drive=img-blkreplay"
+	}
+	return append(args,
+		"-drive", drive,
+		"-drive", "driver=blkreplay,if=none,image=img-direct,id=img-blkreplay",
+		"-device", device,
+	)
+}
+
+func icountArg(mode, file string) string {
+	return fmt.Sprintf("shift=auto,rr=%v,rrfile=%v", mode, file)
+}
+
+// tcgArgs drops KVM-specific arguments since record/replay works only with TCG.
+// Record/replay also does not work with multi-threaded TCG, so it's switched to single-threaded.
+func tcgArgs(args []string) []string {
+	var ret []string
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-enable-kvm":
+		case args[i] == "-accel" && i+1 < len(args) && strings.HasPrefix(args[i+1], "kvm"):
+			i++
+		case args[i] == "-accel" && i+1 < len(args) && strings.HasPrefix(args[i+1], "tcg"):
+			opts := strings.Split(args[i+1], ",")
+			for j, opt := range opts {
+				if opt == "thread=multi" {
+					opts[j] = "thread=single"
+				}
+			}
+			ret = append(ret, args[i], strings.Join(opts, ","))
+			i++
+		case args[i] == "-cpu" && i+1 < len(args) && strings.HasPrefix(args[i+1], "host"):
+			ret = append(ret, args[i], "max")
+			i++
+		default:
+			ret = append(ret, args[i])
+		}
+	}
+	return ret
+}
+
+// replayArgs converts the recording command line into the replaying one.
+// The monitor and the port forwarding are dropped since they are not needed to replay
+// and the ports may be occupied by now.
+func replayArgs(args []string) []string {
+	var ret []string
+	for i := 0; i < len(args); i++ {
+		if i+1 == len(args) {
+			ret = append(ret, args[i])
+			break
+		}
+		arg, val := args[i], args[i+1]
+		switch {
+		case arg == "-chardev" && strings.Contains(val, "id=SOCKSYZ"), arg == "-mon":
+		case arg == "-netdev":
+			var opts []string
+			for _, opt := range strings.Split(val, ",") {
+				if !strings.HasPrefix(opt, "hostfwd=") {
+					opts = append(opts, opt)
+				}
+			}
+			ret = append(ret, arg, strings.Join(opts, ","))
+		case arg == "-icount":
+			ret = append(ret, arg, icountArg("replay", ReplayLogName))
+		default:
+			ret = append(ret, args[i])
+			continue
+		}
+		i++
+	}
+	return ret
+}
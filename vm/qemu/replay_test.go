@@ -0,0 +1,111 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package qemu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTCGArgs(t *testing.T) {
+	tests := []struct {
+		args   []string
+		result []string
+	}{
+		{
+			args:   []string{"-enable-kvm", "-cpu", "host,migratable=off", "-machine", "q35"},
+			result: []string{"-cpu", "max", "-machine", "q35"},
+		},
+		{
+			args:   []string{"-accel", "kvm", "-cpu", "host", "-accel", "kvm,kernel-irqchip=split"},
+			result: []string{"-cpu", "max"},
+		},
+		{
+			args:   []string{"-machine", "vexpress-a15", "-cpu", "cortex-a15", "-accel", "tcg,thread=multi"},
+			result: []string{"-machine", "vexpress-a15", "-cpu", "cortex-a15", "-accel", "tcg,thread=single"},
+		},
+		{
+			args:   []string{"-accel", "tcg", "-cpu", "max,sve128=on"},
+			result: []string{"-accel", "tcg", "-cpu", "max,sve128=on"},
+		},
+		{
+			args:   nil,
+			result: nil,
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.result, tcgArgs(test.args), "test #%v", i)
+	}
+}
+
+func TestReplayArgs(t *testing.T) {
+	tests := []struct {
+		args   []string
+		result []string
+	}{
+		{
+			args: []string{
+				"-m", "2048",
+				"-chardev", "socket,id=SOCKSYZ,server=on,wait=off,host=localhost,port=1234",
+				"-mon", "chardev=SOCKSYZ,mode=control",
+				"-serial", "stdio",
+				"-no-reboot",
+				"-netdev", "user,id=net0,restrict=on,hostfwd=tcp:127.0.0.1:10022-:22",
+				"-icount", "shift=auto,rr=record,rrfile=/workdir/replay.bin",
+				"-object", "filter-replay,id=replay,netdev=net0",
+			},
+			result: []string{
+				"-m", "2048",
+				"-serial", "stdio",
+				"-no-reboot",
+				"-netdev", "user,id=net0,restrict=on",
+				"-icount", "shift=auto,rr=replay,rrfile=replay.bin",
+				"-object", "filter-replay,id=replay,netdev=net0",
+			},
+		},
+		{
+			// Other chardevs are kept.
+			args:   []string{"-chardev", "file,id=log,path=log", "-snapshot"},
+			result: []string{"-chardev", "file,id=log,path=log", "-snapshot"},
+		},
+		{
+			args:   []string{"-netdev", "user,id=net0,hostfwd=tcp::1-:22,hostfwd=tcp::2-:80,restrict=on"},
+			result: []string{"-netdev", "user,id=net0,restrict=on"},
+		},
+	}
+	for i, test := range tests {
+		assert.Equal(t, test.result, replayArgs(test.args), "test #%v", i)
+	}
+}
+
+func TestRecordArgs(t *testing.T) {
+	inst := &instance{
+		cfg:        &Config{Snapshot: true},
+		archConfig: &archConfig{UseNewQemuImageOptions: true},
+		image:      "/img",
+		workdir:    "/workdir",
+	}
+	assert.Equal(t, []string{
+		"-icount", "shift=auto,rr=record,rrfile=/workdir/replay.bin",
+		"-object", "filter-replay,id=replay,netdev=net0",
+		"-drive", "file=/img,if=none,format=raw,id=img-direct,snapshot=on",
+		"-drive", "driver=blkreplay,if=none,image=img-direct,id=img-blkreplay",
+		"-device", "virtio-blk-device,drive=img-blkreplay",
+	}, inst.recordArgs())
+	// The recorded command line is replayed from the dir with the log.
+	assert.Equal(t, []string{
+		"-icount", "shift=auto,rr=replay,rrfile=replay.bin",
+		"-object", "filter-replay,id=replay,netdev=net0",
+		"-drive", "file=/img,if=none,format=raw,id=img-direct,snapshot=on",
+		"-drive", "driver=blkreplay,if=none,image=img-direct,id=img-blkreplay",
+		"-device", "virtio-blk-device,drive=img-blkreplay",
+	}, replayArgs(inst.recordArgs()))
+
+	inst.image = ""
+	assert.Equal(t, []string{
+		"-icount", "shift=auto,rr=record,rrfile=/workdir/replay.bin",
+		"-object", "filter-replay,id=replay,netdev=net0",
+	}, inst.recordArgs())
+}
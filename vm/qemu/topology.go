@@ -0,0 +1,141 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package qemu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NUMANode describes a guest NUMA node.
+type NUMANode struct {
+	// Number of VM CPUs in the node, CPUs are assigned to nodes in order.
+	// Nodes without CPUs are memory-only nodes.
+	CPU int `json:"cpu"`
+	// Amount of the node memory in MiB.
+	Mem int `json:"mem"`
+}
+
+func validateTopology(cfg *Config) error {
+	if cfg.Sockets < 0 || cfg.Cores < 0 || cfg.Threads < 0 {
+		return fmt.Errorf("qemu sockets/cores/threads must not be negative")
+	}
+	if cfg.Sockets != 0 || cfg.Cores != 0 || cfg.Threads != 0 {
+		sockets, cores, threads := max(cfg.Sockets, 1), max(cfg.Cores, 1), max(cfg.Threads, 1)
+		if sockets*cores*threads != cfg.CPU {
+			return fmt.Errorf("qemu sockets*cores*threads = %v*%v*%v does not match cpu %v",
+				sockets, cores, threads, cfg.CPU)
+		}
+	}
+	if len(cfg.NUMA) != 0 {
+		cpus, mem := 0, 0
+		for i, node := range cfg.NUMA {
+			if node.CPU < 0 || node.Mem <= 0 {
+				return fmt.Errorf("bad qemu numa node %v: cpu %v, mem %v", i, node.CPU, node.Mem)
+			}
+			cpus += node.CPU
+			mem += node.Mem
+		}
+		if cpus != cfg.CPU || mem != cfg.Mem {
+			return fmt.Errorf("qemu numa nodes have %v cpus and %v MiB of memory, want %v and %v",
+				cpus, mem, cfg.CPU, cfg.Mem)
+		}
+	}
+	return nil
+}
+
+func smpArg(cfg *Config) string {
+	arg := strconv.Itoa(cfg.CPU)
+	if cfg.Sockets != 0 || cfg.Cores != 0 || cfg.Threads != 0 {
+		arg += fmt.Sprintf(",sockets=%v,cores=%v,threads=%v",
+			max(cfg.Sockets, 1), max(cfg.Cores, 1), max(cfg.Threads, 1))
+	}
+	return arg
+}
+
+func numaArgs(cfg *Config) []string {
+	var args []string
+	cpu := 0
+	for i, node := range cfg.NUMA {
+		args = append(args,
+			"-object", fmt.Sprintf("memory-backend-ram,id=numa-mem%v,size=%vM", i, node.Mem))
+		arg := fmt.Sprintf("node,nodeid=%v,memdev=numa-mem%v", i, i)
+		if node.CPU != 0 {
+			arg += fmt.Sprintf(",cpus=%v-%v", cpu, cpu+node.CPU-1)
+			cpu += node.CPU
+		}
+		args = append(args, "-numa", arg)
+	}
+	return args
+}
+
+// pinnedCPUs returns the host CPUs the VM with the given index is pinned to.
+func pinnedCPUs(hostCPUs []int, cpus, index int) []int {
+	return hostCPUs[index*cpus : (index+1)*cpus]
+}
+
+// parseCPUList parses CPU lists in the format used by the kernel, e.g. "0-3,8,10-11".
+func parseCPUList(list string) ([]int, error) {
+	var cpus []int
+	seen := make(map[int]bool)
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		first, last, isRange := strings.Cut(part, "-")
+		from, err := strconv.Atoi(first)
+		if err != nil {
+			return nil, fmt.Errorf("bad cpu list %q: %w", list, err)
+		}
+		to := from
+		if isRange {
+			if to, err = strconv.Atoi(last); err != nil {
+				return nil, fmt.Errorf("bad cpu list %q: %w", list, err)
+			}
+		}
+		if from < 0 || to < from {
+			return nil, fmt.Errorf("bad cpu list %q: bad range %q", list, part)
+		}
+		for cpu := from; cpu <= to; cpu++ {
+			if seen[cpu] {
+				return nil, fmt.Errorf("bad cpu list %q: cpu %v is listed twice", list, cpu)
+			}
+			seen[cpu] = true
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus, nil
+}
+
+// pinVCPUs pins each vCPU thread to own host CPU from the instance CPU set.
+func (inst *instance) pinVCPUs() error {
+	if err := pinProcess(inst.qemu.Process.Pid, inst.hostCPUs); err != nil {
+		return err
+	}
+	res, err := inst.qmp(&qmpCommand{Execute: "query-cpus-fast"})
+	if err != nil {
+		return fmt.Errorf("qemu query-cpus-fast failed: %w", err)
+	}
+	list, _ := res.([]interface{})
+	threads := make(map[int]int)
+	for _, item := range list {
+		cpu, _ := item.(map[string]interface{})
+		index, ok1 := cpu["cpu-index"].(float64)
+		tid, ok2 := cpu["thread-id"].(float64)
+		if !ok1 || !ok2 || int(index) >= len(inst.hostCPUs) {
+			return fmt.Errorf("unexpected query-cpus-fast reply: %v", res)
+		}
+		threads[int(tid)] = int(index)
+	}
+	if len(threads) != len(list) {
+		// All vCPUs run on a single thread (e.g. TCG without multi-threading),
+		// pinning to the whole set is the best we can do.
+		return nil
+	}
+	for tid, index := range threads {
+		if err := setAffinity(tid, inst.hostCPUs[index:index+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package qemu
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCPUList(t *testing.T) {
+	cpus, err := parseCPUList("0-3, 8,10-11")
+	assert.NoError(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 8, 10, 11}, cpus)
+	for _, bad := range []string{"", "1-", "3-1", "-1", "1,1", "a"} {
+		_, err := parseCPUList(bad)
+		assert.Error(t, err, bad)
+	}
+	assert.Equal(t, []int{4, 5}, pinnedCPUs([]int{0, 1, 2, 3, 4, 5, 6}, 2, 2))
+}
+
+func TestTopology(t *testing.T) {
+	cfg := &Config{
+		CPU:     8,
+		Mem:     3072,
+		Sockets: 2,
+		Cores:   4,
+		NUMA: []NUMANode{
+			{CPU: 4, Mem: 1024},
+			{CPU: 4, Mem: 1024},
+			{Mem: 1024},
+		},
+	}
+	assert.NoError(t, validateTopology(cfg))
+	assert.Equal(t, "8,sockets=2,cores=4,threads=1", smpArg(cfg))
+	assert.Equal(t, []string{
+		"-object", "memory-backend-ram,id=numa-mem0,size=1024M",
+		"-numa", "node,nodeid=0,memdev=numa-mem0,cpus=0-3",
+		"-object", "memory-backend-ram,id=numa-mem1,size=1024M",
+		"-numa", "node,nodeid=1,memdev=numa-mem1,cpus=4-7",
+		"-object", "memory-backend-ram,id=numa-mem2,size=1024M",
+		"-numa", "node,nodeid=2,memdev=numa-mem2",
+	}, numaArgs(cfg))
+
+	cfg.Threads = 2
+	assert.ErrorContains(t, validateTopology(cfg), "does not match cpu")
+	cfg.Threads = 0
+	cfg.NUMA[2].Mem = 512
+	assert.ErrorContains(t, validateTopology(cfg), "numa nodes")
+	cfg.NUMA = nil
+	cfg.Sockets, cfg.Cores = 0, 0
+	assert.NoError(t, validateTopology(cfg))
+	assert.Equal(t, "8", smpArg(cfg))
+}
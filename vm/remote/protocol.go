@@ -0,0 +1,320 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+)
+
+// The protocol is JSON-RPC (net/rpc/jsonrpc) over a TCP connection that is first
+// authenticated with a shared secret token. Both sides prove the knowledge of the token
+// by sending HMAC of a random nonce chosen by the other side, so the token itself
+// is never sent over the network. After the handshake all traffic is sent in frames
+// encrypted and authenticated with AES-GCM. The keys are derived from the token and
+// both nonces (separate keys for each direction), and frames are numbered, so frames
+// can't be forged, modified, reordered or replayed within or across sessions.
+
+const (
+	rpcService    = "Worker"
+	nonceSize     = 32
+	handshakeTime = time.Minute
+	// RunRead returns after this time even if there is no new output.
+	runReadTimeout = 5 * time.Second
+	// Files are copied in chunks of this size.
+	copyChunkSize = 1 << 20
+	// Writes are split into frames of at most this size.
+	maxFrameSize = 64 << 10
+)
+
+// InfoArgs is sent first on every connection, the worker creates the VM pool for the session.
+type InfoArgs struct {
+	// Diagnostics configured in the manager (see mgrconfig.Config.Diagnostics).
+	Diagnostics []mgrconfig.Diagnostic
+}
+
+type InfoReply struct {
+	Count int    // number of VMs the worker can run in parallel
+	Type  string // VM type used by the worker
+}
+
+type InstanceArgs struct {
+	ID string
+}
+
+type CreateArgs struct {
+	Index int
+}
+
+type CreateReply struct {
+	ID string
+	// Set if the VM failed to boot (see vmimpl.BootError).
+	BootErrorTitle  string
+	BootErrorOutput []byte
+}
+
+// CopyArgs holds one chunk of the copied file, the chunks are sent in order.
+// The file is copied into the VM after the last chunk.
+type CopyArgs struct {
+	ID     string
+	Name   string
+	Offset int64
+	Data   []byte
+	Last   bool
+}
+
+type CopyReply struct {
+	VMFile string
+}
+
+type ForwardArgs struct {
+	ID string
+	// Manager address reachable from the worker machine.
+	ManagerAddr string
+}
+
+type ForwardReply struct {
+	Addr string
+}
+
+type RunStartArgs struct {
+	ID      string
+	Command string
+}
+
+type RunStartReply struct {
+	RunID string
+}
+
+type RunArgs struct {
+	ID    string
+	RunID string
+}
+
+type RunReadReply struct {
+	Output   []byte
+	Finished bool
+	Error    string
+}
+
+type RunStopReply struct{}
+
+type DiagnoseArgs struct {
+	ID    string
+	Title string
+	Type  string // crash type used to select diagnostics on the worker
+}
+
+type DiagnoseReply struct {
+	Diagnosis []byte
+	Wait      bool
+}
+
+type MachineInfoReply struct {
+	Info []byte
+}
+
+type CloseReply struct{}
+
+func serverHandshake(conn net.Conn, token []byte) (io.ReadWriteCloser, error) {
+	conn.SetDeadline(time.Now().Add(handshakeTime))
+	defer conn.SetDeadline(time.Time{})
+	nonce, err := sendNonce(conn)
+	if err != nil {
+		return nil, err
+	}
+	clientNonce, err := readNonce(conn)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkMAC(conn, token, "client", nonce); err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(authMAC(token, "server", clientNonce)); err != nil {
+		return nil, err
+	}
+	return newSecureConn(conn, token, "client", "server", nonce, clientNonce)
+}
+
+func clientHandshake(conn net.Conn, token []byte) (io.ReadWriteCloser, error) {
+	conn.SetDeadline(time.Now().Add(handshakeTime))
+	defer conn.SetDeadline(time.Time{})
+	serverNonce, err := readNonce(conn)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := sendNonce(conn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(authMAC(token, "client", serverNonce)); err != nil {
+		return nil, err
+	}
+	if err := checkMAC(conn, token, "server", nonce); err != nil {
+		return nil, err
+	}
+	return newSecureConn(conn, token, "server", "client", serverNonce, nonce)
+}
+
+func sendNonce(conn net.Conn) ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	_, err := conn.Write(nonce)
+	return nonce, err
+}
+
+func readNonce(conn net.Conn) ([]byte, error) {
+	nonce := make([]byte, nonceSize)
+	if _, err := io.ReadFull(conn, nonce); err != nil {
+		return nil, fmt.Errorf("handshake failed: %w", err)
+	}
+	return nonce, nil
+}
+
+func checkMAC(conn net.Conn, token []byte, side string, nonce []byte) error {
+	mac := make([]byte, sha256.Size)
+	if _, err := io.ReadFull(conn, mac); err != nil {
+		return fmt.Errorf("handshake failed: %w", err)
+	}
+	if !hmac.Equal(mac, authMAC(token, side, nonce)) {
+		return fmt.Errorf("handshake failed: wrong token")
+	}
+	return nil
+}
+
+func authMAC(token []byte, side string, nonce []byte) []byte {
+	mac := hmac.New(sha256.New, token)
+	mac.Write([]byte(side))
+	mac.Write(nonce)
+	return mac.Sum(nil)
+}
+
+// secureConn encrypts and authenticates all data sent over conn after the handshake.
+// Each frame is a 4-byte big-endian size of the sealed data followed by the sealed data.
+// The GCM nonce of a frame is its sequence number in the given direction.
+type secureConn struct {
+	conn     net.Conn
+	readMu   sync.Mutex
+	reader   cipher.AEAD
+	readSeq  uint64
+	readBuf  []byte
+	pending  []byte
+	writeMu  sync.Mutex
+	writer   cipher.AEAD
+	writeSeq uint64
+	writeBuf []byte
+}
+
+// newSecureConn creates a connection that reads frames sent by the readSide
+// and writes frames for the writeSide.
+func newSecureConn(conn net.Conn, token []byte, readSide, writeSide string,
+	serverNonce, clientNonce []byte) (*secureConn, error) {
+	reader, err := sessionCipher(token, readSide, serverNonce, clientNonce)
+	if err != nil {
+		return nil, err
+	}
+	writer, err := sessionCipher(token, writeSide, serverNonce, clientNonce)
+	if err != nil {
+		return nil, err
+	}
+	return &secureConn{
+		conn:   conn,
+		reader: reader,
+		writer: writer,
+	}, nil
+}
+
+// sessionCipher returns the cipher for the frames sent by the side in the session with the given nonces.
+func sessionCipher(token []byte, side string, serverNonce, clientNonce []byte) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, token)
+	mac.Write([]byte("session key " + side))
+	mac.Write(serverNonce)
+	mac.Write(clientNonce)
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (sc *secureConn) Read(data []byte) (int, error) {
+	sc.readMu.Lock()
+	defer sc.readMu.Unlock()
+	if len(sc.pending) == 0 {
+		if err := sc.readFrame(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(data, sc.pending)
+	sc.pending = sc.pending[n:]
+	return n, nil
+}
+
+func (sc *secureConn) readFrame() error {
+	var hdr [4]byte
+	if _, err := io.ReadFull(sc.conn, hdr[:]); err != nil {
+		return err
+	}
+	size := int(binary.BigEndian.Uint32(hdr[:]))
+	if size > maxFrameSize+sc.reader.Overhead() {
+		return fmt.Errorf("too large frame: %v", size)
+	}
+	if cap(sc.readBuf) < size {
+		sc.readBuf = make([]byte, size)
+	}
+	sealed := sc.readBuf[:size]
+	if _, err := io.ReadFull(sc.conn, sealed); err != nil {
+		return err
+	}
+	plain, err := sc.reader.Open(sealed[:0], frameNonce(sc.reader, sc.readSeq), sealed, hdr[:])
+	if err != nil {
+		return fmt.Errorf("corrupted frame: %w", err)
+	}
+	sc.readSeq++
+	sc.pending = plain
+	return nil
+}
+
+func (sc *secureConn) Write(data []byte) (int, error) {
+	sc.writeMu.Lock()
+	defer sc.writeMu.Unlock()
+	written := 0
+	for len(data) != 0 {
+		chunk := data[:min(len(data), maxFrameSize)]
+		data = data[len(chunk):]
+		var hdr [4]byte
+		binary.BigEndian.PutUint32(hdr[:], uint32(len(chunk)+sc.writer.Overhead()))
+		sc.writeBuf = append(sc.writeBuf[:0], hdr[:]...)
+		sc.writeBuf = sc.writer.Seal(sc.writeBuf, frameNonce(sc.writer, sc.writeSeq), chunk, hdr[:])
+		sc.writeSeq++
+		if _, err := sc.conn.Write(sc.writeBuf); err != nil {
+			return written, err
+		}
+		written += len(chunk)
+	}
+	return written, nil
+}
+
+func (sc *secureConn) Close() error {
+	return sc.conn.Close()
+}
+
+func frameNonce(aead cipher.AEAD, seq uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], seq)
+	return nonce
+}
@@ -0,0 +1,332 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+// Package remote implements VMs that run on other machines managed by syz-worker daemons.
+// Each worker runs its own local VM pool (e.g. qemu) and serves it to the manager
+// over a TCP connection that is authenticated with a shared token and encrypted (see protocol.go).
+// The VMs connect back to the manager through the worker, so only the manager RPC port
+// needs to be reachable from the workers.
+// Workers use their own kernel images, it's the user's responsibility to keep them in sync
+// with the kernel used by the manager.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/google/syzkaller/pkg/config"
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+func init() {
+	var _ vmimpl.Infoer = (*instance)(nil)
+	vmimpl.Register("remote", vmimpl.Type{
+		Ctor: ctor,
+	})
+}
+
+type Config struct {
+	// Addresses of syz-worker daemons in the host:port format.
+	Workers []string `json:"workers"`
+	// File with the secret token shared with the workers (see syz-worker -token flag).
+	TokenFile string `json:"token_file"`
+	// Host name or IP address of the manager reachable from the workers
+	// (by default the local address of the connection to the worker is used).
+	ManagerHost string `json:"manager_host"`
+}
+
+type Pool struct {
+	workers []*worker
+	count   int
+}
+
+type worker struct {
+	addr        string
+	token       []byte
+	managerHost string
+	diagnostics []mgrconfig.Diagnostic
+	count       int
+	typ         string
+	mu          sync.Mutex
+	client      *rpc.Client
+	localHost   string
+}
+
+type instance struct {
+	worker *worker
+	id     string
+}
+
+func ctor(env *vmimpl.Env) (vmimpl.Pool, error) {
+	cfg := new(Config)
+	if err := config.LoadData(env.Config, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse remote vm config: %w", err)
+	}
+	if len(cfg.Workers) == 0 {
+		return nil, fmt.Errorf("no workers specified")
+	}
+	if cfg.TokenFile == "" {
+		return nil, fmt.Errorf("token_file is not specified")
+	}
+	token, err := ReadToken(cfg.TokenFile)
+	if err != nil {
+		return nil, err
+	}
+	pool := &Pool{}
+	for _, addr := range cfg.Workers {
+		w := &worker{
+			addr:        addr,
+			token:       token,
+			managerHost: cfg.ManagerHost,
+			diagnostics: env.Diagnostics,
+		}
+		if _, _, err := w.connect(); err != nil {
+			pool.Close()
+			return nil, fmt.Errorf("worker %v: %w", addr, err)
+		}
+		log.Logf(0, "worker %v: %v %v VMs", addr, w.count, w.typ)
+		pool.count += w.count
+		pool.workers = append(pool.workers, w)
+	}
+	return pool, nil
+}
+
+// ReadToken reads the secret token shared by the manager and the workers.
+func ReadToken(file string) ([]byte, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token: %w", err)
+	}
+	token := []byte(strings.TrimSpace(string(data)))
+	if len(token) == 0 {
+		return nil, fmt.Errorf("token file %v is empty", file)
+	}
+	return token, nil
+}
+
+func (pool *Pool) Count() int {
+	return pool.count
+}
+
+func (pool *Pool) Create(_ context.Context, workdir string, index int) (vmimpl.Instance, error) {
+	// VMs are numbered across all workers in the order they are listed in the config.
+	for _, w := range pool.workers {
+		if index >= w.count {
+			index -= w.count
+			continue
+		}
+		var reply CreateReply
+		if err := w.call("Create", &CreateArgs{Index: index}, &reply); err != nil {
+			return nil, fmt.Errorf("worker %v: %w", w.addr, err)
+		}
+		if reply.BootErrorTitle != "" {
+			return nil, vmimpl.BootError{Title: reply.BootErrorTitle, Output: reply.BootErrorOutput}
+		}
+		return &instance{
+			worker: w,
+			id:     reply.ID,
+		}, nil
+	}
+	return nil, fmt.Errorf("invalid VM index %v (count %v)", index, pool.count)
+}
+
+func (pool *Pool) Close() error {
+	for _, w := range pool.workers {
+		w.mu.Lock()
+		if w.client != nil {
+			w.client.Close()
+			w.client = nil
+		}
+		w.mu.Unlock()
+	}
+	return nil
+}
+
+func (w *worker) connect() (*rpc.Client, string, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.client != nil {
+		return w.client, w.localHost, nil
+	}
+	conn, err := net.Dial("tcp", w.addr)
+	if err != nil {
+		return nil, "", err
+	}
+	secure, err := clientHandshake(conn, w.token)
+	if err != nil {
+		conn.Close()
+		return nil, "", err
+	}
+	client := jsonrpc.NewClient(secure)
+	var info InfoReply
+	if err := client.Call(rpcService+".Info", &InfoArgs{Diagnostics: w.diagnostics}, &info); err != nil {
+		client.Close()
+		return nil, "", err
+	}
+	if w.count != 0 && w.count != info.Count {
+		// VM indexes would be shifted, the pool can't work with the worker anymore.
+		client.Close()
+		return nil, "", fmt.Errorf("the number of VMs has changed from %v to %v", w.count, info.Count)
+	}
+	w.count, w.typ = info.Count, info.Type
+	w.client = client
+	w.localHost, _, _ = net.SplitHostPort(conn.LocalAddr().String())
+	return w.client, w.localHost, nil
+}
+
+func (w *worker) call(method string, args, reply any) error {
+	client, _, err := w.connect()
+	if err != nil {
+		return err
+	}
+	err = client.Call(rpcService+"."+method, args, reply)
+	if errors.Is(err, rpc.ErrShutdown) || errors.Is(err, io.ErrUnexpectedEOF) {
+		// The worker has restarted or the network failed, all VMs created over
+		// the connection are gone, reconnect on the next call.
+		w.mu.Lock()
+		if w.client == client {
+			w.client.Close()
+			w.client = nil
+		}
+		w.mu.Unlock()
+	}
+	return err
+}
+
+func (inst *instance) Copy(hostSrc string) (string, error) {
+	f, err := os.Open(hostSrc)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	buf := make([]byte, copyChunkSize)
+	for offset := int64(0); ; {
+		n, err := io.ReadFull(f, buf)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return "", err
+		}
+		args := &CopyArgs{
+			ID:     inst.id,
+			Name:   filepath.Base(hostSrc),
+			Offset: offset,
+			Data:   buf[:n],
+			Last:   n < len(buf),
+		}
+		var reply CopyReply
+		if err := inst.worker.call("Copy", args, &reply); err != nil {
+			return "", err
+		}
+		if args.Last {
+			return reply.VMFile, nil
+		}
+		offset += int64(n)
+	}
+}
+
+func (inst *instance) Forward(port int) (string, error) {
+	_, localHost, err := inst.worker.connect()
+	if err != nil {
+		return "", err
+	}
+	host := inst.worker.managerHost
+	if host == "" {
+		host = localHost
+	}
+	var reply ForwardReply
+	err = inst.worker.call("Forward", &ForwardArgs{
+		ID:          inst.id,
+		ManagerAddr: net.JoinHostPort(host, fmt.Sprint(port)),
+	}, &reply)
+	return reply.Addr, err
+}
+
+func (inst *instance) Run(ctx context.Context, command string) (<-chan []byte, <-chan error, error) {
+	var start RunStartReply
+	if err := inst.worker.call("RunStart", &RunStartArgs{
+		ID:      inst.id,
+		Command: command,
+	}, &start); err != nil {
+		return nil, nil, err
+	}
+	args := &RunArgs{
+		ID:    inst.id,
+		RunID: start.RunID,
+	}
+	outc := make(chan []byte, 10)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			var reply RunReadReply
+			read := make(chan error, 1)
+			go func() {
+				read <- inst.worker.call("RunRead", args, &reply)
+			}()
+			select {
+			case <-ctx.Done():
+				if err := inst.worker.call("RunStop", args, &RunStopReply{}); err != nil {
+					log.Logf(1, "worker %v: failed to stop %v: %v", inst.worker.addr, inst.id, err)
+				}
+				// RunRead returns within runReadTimeout, don't leave it running after we return.
+				<-read
+				errc <- vmimpl.ErrTimeout
+				return
+			case err := <-read:
+				if err != nil {
+					errc <- fmt.Errorf("worker %v: %w", inst.worker.addr, err)
+					return
+				}
+			}
+			if len(reply.Output) != 0 {
+				select {
+				case outc <- reply.Output:
+				case <-ctx.Done():
+				}
+			}
+			if reply.Finished {
+				if reply.Error != "" {
+					errc <- errors.New(reply.Error)
+				} else {
+					errc <- nil
+				}
+				return
+			}
+		}
+	}()
+	return outc, errc, nil
+}
+
+func (inst *instance) Diagnose(rep *report.Report) ([]byte, bool) {
+	args := &DiagnoseArgs{ID: inst.id}
+	if rep != nil {
+		args.Title, args.Type = rep.Title, string(rep.Type)
+	}
+	var reply DiagnoseReply
+	if err := inst.worker.call("Diagnose", args, &reply); err != nil {
+		return nil, false
+	}
+	return reply.Diagnosis, reply.Wait
+}
+
+func (inst *instance) Info() ([]byte, error) {
+	var reply MachineInfoReply
+	if err := inst.worker.call("MachineInfo", &InstanceArgs{ID: inst.id}, &reply); err != nil {
+		return nil, err
+	}
+	return append([]byte(fmt.Sprintf("worker %v\n", inst.worker.addr)), reply.Info...), nil
+}
+
+func (inst *instance) Close() error {
+	return inst.worker.call("Close", &InstanceArgs{ID: inst.id}, &CloseReply{})
+}
@@ -0,0 +1,302 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/vm/vmimpl"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPool is a local VM pool served by the worker in tests.
+type testPool struct {
+	count       int
+	diagnostics []mgrconfig.Diagnostic
+}
+
+type testInstance struct {
+	index       int
+	diagnostics []mgrconfig.Diagnostic
+}
+
+func (pool *testPool) Count() int {
+	return pool.count
+}
+
+func (pool *testPool) Create(_ context.Context, workdir string, index int) (vmimpl.Instance, error) {
+	if index == pool.count-1 {
+		return nil, vmimpl.BootError{Title: "kernel panic at boot", Output: []byte("boot log")}
+	}
+	return &testInstance{index: index, diagnostics: pool.diagnostics}, nil
+}
+
+func (inst *testInstance) Copy(hostSrc string) (string, error) {
+	data, err := os.ReadFile(hostSrc)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("/vm/%v:%s", filepath.Base(hostSrc), data), nil
+}
+
+func (inst *testInstance) Forward(port int) (string, error) {
+	return fmt.Sprintf("127.0.0.1:%v", port), nil
+}
+
+func (inst *testInstance) Run(ctx context.Context, command string) (<-chan []byte, <-chan error, error) {
+	outc := make(chan []byte, 10)
+	errc := make(chan error, 1)
+	go func() {
+		switch command {
+		case "hang":
+			outc <- []byte("hanging\n")
+			<-ctx.Done()
+			errc <- vmimpl.ErrTimeout
+		case "fail":
+			errc <- fmt.Errorf("command failed")
+		default:
+			for _, line := range strings.Split(command, " ") {
+				outc <- []byte(fmt.Sprintf("vm%v: %v\n", inst.index, line))
+			}
+			errc <- nil
+		}
+	}()
+	return outc, errc, nil
+}
+
+func (inst *testInstance) Diagnose(rep *report.Report) ([]byte, bool) {
+	output := "diagnosis: " + rep.Title
+	for _, diag := range inst.diagnostics {
+		output += ", " + diag.Name
+	}
+	return []byte(output), true
+}
+
+func (inst *testInstance) Close() error {
+	return nil
+}
+
+func startWorker(t *testing.T, count int) (string, string) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0600))
+	newPool := func(diagnostics []mgrconfig.Diagnostic) (vmimpl.Pool, error) {
+		return &testPool{count: count, diagnostics: diagnostics}, nil
+	}
+	w, err := NewWorker(newPool, "test", filepath.Join(dir, "workdir"), []byte("secret"))
+	require.NoError(t, err)
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	go w.Serve(ln)
+	return ln.Addr().String(), tokenFile
+}
+
+func createPool(t *testing.T, cfg string) *Pool {
+	pool, err := ctor(&vmimpl.Env{
+		Name:   "test",
+		Config: []byte(cfg),
+		Diagnostics: []mgrconfig.Diagnostic{
+			{Name: "slabinfo", Command: "cat /proc/slabinfo"},
+		},
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { pool.(*Pool).Close() })
+	return pool.(*Pool)
+}
+
+func runCommand(t *testing.T, inst vmimpl.Instance, timeout time.Duration, command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	outc, errc, err := inst.Run(ctx, command)
+	require.NoError(t, err)
+	var output []byte
+	for {
+		select {
+		case out := <-outc:
+			output = append(output, out...)
+		case err := <-errc:
+			for len(outc) != 0 {
+				output = append(output, <-outc...)
+			}
+			return string(output), err
+		}
+	}
+}
+
+func TestRemote(t *testing.T) {
+	addr1, tokenFile := startWorker(t, 2)
+	addr2, _ := startWorker(t, 3)
+	pool := createPool(t, fmt.Sprintf(`{"workers": [%q, %q], "token_file": %q}`, addr1, addr2, tokenFile))
+	require.Equal(t, 5, pool.Count())
+
+	// The last VM of each worker fails to boot.
+	_, err := pool.Create(context.Background(), t.TempDir(), 1)
+	var bootErr vmimpl.BootError
+	require.ErrorAs(t, err, &bootErr)
+	assert.Equal(t, "kernel panic at boot", bootErr.Title)
+	assert.Equal(t, "boot log", string(bootErr.Output))
+
+	inst, err := pool.Create(context.Background(), t.TempDir(), 3)
+	require.NoError(t, err)
+	defer inst.Close()
+
+	src := filepath.Join(t.TempDir(), "executor")
+	require.NoError(t, os.WriteFile(src, []byte("binary"), 0600))
+	file, err := inst.Copy(src)
+	require.NoError(t, err)
+	assert.Equal(t, "/vm/executor:binary", file)
+
+	// Large files are sent in chunks.
+	data := bytes.Repeat([]byte("0123456789"), copyChunkSize/4)
+	require.NoError(t, os.WriteFile(src, data, 0600))
+	file, err = inst.Copy(src)
+	require.NoError(t, err)
+	assert.Equal(t, "/vm/executor:"+string(data), file)
+
+	output, err := runCommand(t, inst, time.Minute, "a b")
+	require.NoError(t, err)
+	assert.Equal(t, "vm1: a\nvm1: b\n", output)
+
+	_, err = runCommand(t, inst, time.Minute, "fail")
+	assert.ErrorContains(t, err, "command failed")
+
+	output, err = runCommand(t, inst, time.Second, "hang")
+	assert.ErrorIs(t, err, vmimpl.ErrTimeout)
+	assert.Equal(t, "hanging\n", output)
+
+	diag, wait := inst.Diagnose(&report.Report{Title: "WARNING in foo"})
+	// The diagnostics configured in the manager are used on the worker.
+	assert.Equal(t, "diagnosis: WARNING in foo, slabinfo", string(diag))
+	assert.True(t, wait)
+}
+
+func TestForward(t *testing.T) {
+	addr, tokenFile := startWorker(t, 2)
+	pool := createPool(t, fmt.Sprintf(`{"workers": [%q], "token_file": %q}`, addr, tokenFile))
+	inst, err := pool.Create(context.Background(), t.TempDir(), 0)
+	require.NoError(t, err)
+	defer inst.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		line, _ := bufio.NewReader(conn).ReadString('\n')
+		conn.Write([]byte("manager: " + line))
+	}()
+	vmAddr, err := inst.Forward(ln.Addr().(*net.TCPAddr).Port)
+	require.NoError(t, err)
+	// The VM connects to the worker, which proxies the connection to the manager.
+	assert.NotEqual(t, ln.Addr().String(), vmAddr)
+	conn, err := net.Dial("tcp", vmAddr)
+	require.NoError(t, err)
+	defer conn.Close()
+	_, err = conn.Write([]byte("hello\n"))
+	require.NoError(t, err)
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	require.NoError(t, err)
+	assert.Equal(t, "manager: hello\n", reply)
+}
+
+func TestAuth(t *testing.T) {
+	addr, _ := startWorker(t, 2)
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("wrong"), 0600))
+	_, err := ctor(&vmimpl.Env{
+		Config: []byte(fmt.Sprintf(`{"workers": [%q], "token_file": %q}`, addr, tokenFile)),
+	})
+	assert.ErrorContains(t, err, "handshake failed")
+}
+
+func TestBusy(t *testing.T) {
+	addr, tokenFile := startWorker(t, 2)
+	cfg := fmt.Sprintf(`{"workers": [%q], "token_file": %q}`, addr, tokenFile)
+	createPool(t, cfg)
+	// Only one manager can use the worker at a time.
+	_, err := ctor(&vmimpl.Env{Config: []byte(cfg)})
+	assert.Error(t, err)
+}
+
+// recordingConn remembers everything written to the connection.
+type recordingConn struct {
+	net.Conn
+	written bytes.Buffer
+}
+
+func (conn *recordingConn) Write(data []byte) (int, error) {
+	conn.written.Write(data)
+	return conn.Conn.Write(data)
+}
+
+func TestSecureConn(t *testing.T) {
+	token := []byte("secret")
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	type result struct {
+		data []byte
+		err  error
+	}
+	results := make(chan result, 2)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		defer conn.Close()
+		secure, err := serverHandshake(conn, token)
+		if err != nil {
+			results <- result{err: err}
+			return
+		}
+		buf := make([]byte, 3*maxFrameSize)
+		_, err = io.ReadFull(secure, buf)
+		results <- result{data: buf, err: err}
+		_, err = secure.Read(buf)
+		results <- result{err: err}
+	}()
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer conn.Close()
+	rec := &recordingConn{Conn: conn}
+	secure, err := clientHandshake(rec, token)
+	require.NoError(t, err)
+	rec.written.Reset()
+
+	data := bytes.Repeat([]byte("0123456789"), 3*maxFrameSize/10+1)[:3*maxFrameSize]
+	n, err := secure.Write(data)
+	require.NoError(t, err)
+	assert.Equal(t, len(data), n)
+	res := <-results
+	require.NoError(t, res.err)
+	assert.Equal(t, data, res.data)
+	// The data is not sent in clear text.
+	assert.False(t, bytes.Contains(rec.written.Bytes(), data[:100]))
+
+	// Frames can't be replayed.
+	_, err = conn.Write(rec.written.Bytes())
+	require.NoError(t, err)
+	res = <-results
+	assert.ErrorContains(t, res.err, "corrupted frame")
+}
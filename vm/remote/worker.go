@@ -0,0 +1,448 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/google/syzkaller/vm/vmimpl"
+)
+
+// Worker serves VMs of a local pool to a remote manager.
+// Only one manager can be connected at a time, all VMs created over a connection
+// are destroyed once the connection is closed.
+type Worker struct {
+	newPool PoolCtor
+	typ     string
+	workdir string
+	token   []byte
+	mu      sync.Mutex
+	busy    bool
+}
+
+// PoolCtor creates the local VM pool for a manager connection,
+// diagnostics are the ones configured in the manager.
+type PoolCtor func(diagnostics []mgrconfig.Diagnostic) (vmimpl.Pool, error)
+
+func NewWorker(newPool PoolCtor, typ, workdir string, token []byte) (*Worker, error) {
+	if len(token) == 0 {
+		return nil, fmt.Errorf("empty token")
+	}
+	if err := osutil.MkdirAll(workdir); err != nil {
+		return nil, err
+	}
+	return &Worker{
+		newPool: newPool,
+		typ:     typ,
+		workdir: workdir,
+		token:   token,
+	}, nil
+}
+
+// Serve accepts manager connections on ln until it's closed.
+func (w *Worker) Serve(ln net.Listener) error {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return err
+		}
+		go w.serveConn(conn)
+	}
+}
+
+func (w *Worker) serveConn(conn net.Conn) {
+	defer conn.Close()
+	secure, err := serverHandshake(conn, w.token)
+	if err != nil {
+		log.Logf(0, "connection from %v: %v", conn.RemoteAddr(), err)
+		return
+	}
+	w.mu.Lock()
+	busy := w.busy
+	w.busy = true
+	w.mu.Unlock()
+	if busy {
+		log.Logf(0, "connection from %v: another manager is already connected", conn.RemoteAddr())
+		return
+	}
+	log.Logf(0, "manager %v connected", conn.RemoteAddr())
+	s := &session{
+		worker:    w,
+		instances: make(map[string]*workerInstance),
+	}
+	server := rpc.NewServer()
+	if err := server.RegisterName(rpcService, s); err != nil {
+		panic(err)
+	}
+	server.ServeCodec(jsonrpc.NewServerCodec(secure))
+	s.close()
+	log.Logf(0, "manager %v disconnected", conn.RemoteAddr())
+	w.mu.Lock()
+	w.busy = false
+	w.mu.Unlock()
+}
+
+type session struct {
+	worker    *Worker
+	mu        sync.Mutex
+	pool      vmimpl.Pool
+	seq       int
+	instances map[string]*workerInstance
+}
+
+type workerInstance struct {
+	inst    vmimpl.Instance
+	workdir string
+	fwd     net.Listener
+	mu      sync.Mutex
+	runs    map[string]*workerRun
+}
+
+type workerRun struct {
+	cancel context.CancelFunc
+	mu     sync.Mutex
+	output []byte
+	done   bool
+	err    error
+	// Signaled when there is new output or the run has finished.
+	ready chan struct{}
+}
+
+func (s *session) Info(args *InfoArgs, reply *InfoReply) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pool == nil {
+		pool, err := s.worker.newPool(args.Diagnostics)
+		if err != nil {
+			return fmt.Errorf("failed to create VM pool: %w", err)
+		}
+		s.pool = pool
+	}
+	reply.Count = s.pool.Count()
+	reply.Type = s.worker.typ
+	return nil
+}
+
+func (s *session) getPool() (vmimpl.Pool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.pool == nil {
+		return nil, fmt.Errorf("the session is not initialized with Info")
+	}
+	return s.pool, nil
+}
+
+func (s *session) Create(args *CreateArgs, reply *CreateReply) error {
+	pool, err := s.getPool()
+	if err != nil {
+		return err
+	}
+	if args.Index < 0 || args.Index >= pool.Count() {
+		return fmt.Errorf("invalid VM index %v (count %v)", args.Index, pool.Count())
+	}
+	workdir, err := os.MkdirTemp(s.worker.workdir, fmt.Sprintf("instance-%v-", args.Index))
+	if err != nil {
+		return err
+	}
+	inst, err := pool.Create(context.Background(), workdir, args.Index)
+	if err != nil {
+		os.RemoveAll(workdir)
+		var bootErr vmimpl.BootError
+		if errors.As(err, &bootErr) {
+			reply.BootErrorTitle, reply.BootErrorOutput = bootErr.Title, bootErr.Output
+			return nil
+		}
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	reply.ID = fmt.Sprintf("vm-%v-%v", args.Index, s.seq)
+	s.instances[reply.ID] = &workerInstance{
+		inst:    inst,
+		workdir: workdir,
+		runs:    make(map[string]*workerRun),
+	}
+	return nil
+}
+
+func (s *session) instance(id string) (*workerInstance, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	inst := s.instances[id]
+	if inst == nil {
+		return nil, fmt.Errorf("unknown instance %v", id)
+	}
+	return inst, nil
+}
+
+func (s *session) Copy(args *CopyArgs, reply *CopyReply) error {
+	inst, err := s.instance(args.ID)
+	if err != nil {
+		return err
+	}
+	file := filepath.Join(inst.workdir, filepath.Base(args.Name))
+	if args.Offset == 0 {
+		os.Remove(file)
+	}
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_CREATE, osutil.DefaultExecPerm)
+	if err != nil {
+		return err
+	}
+	_, err = f.WriteAt(args.Data, args.Offset)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil || !args.Last {
+		return err
+	}
+	reply.VMFile, err = inst.inst.Copy(file)
+	return err
+}
+
+func (s *session) Forward(args *ForwardArgs, reply *ForwardReply) error {
+	inst, err := s.instance(args.ID)
+	if err != nil {
+		return err
+	}
+	// The VM connects to a local port on the worker, and we proxy the connections to the manager.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return err
+	}
+	reply.Addr, err = inst.inst.Forward(ln.Addr().(*net.TCPAddr).Port)
+	if err != nil {
+		ln.Close()
+		return err
+	}
+	inst.mu.Lock()
+	if inst.fwd != nil {
+		inst.fwd.Close()
+	}
+	inst.fwd = ln
+	inst.mu.Unlock()
+	go proxyConns(ln, args.ManagerAddr)
+	return nil
+}
+
+func proxyConns(ln net.Listener, addr string) {
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			manager, err := net.Dial("tcp", addr)
+			if err != nil {
+				log.Logf(0, "failed to connect to manager %v: %v", addr, err)
+				return
+			}
+			defer manager.Close()
+			done := make(chan bool)
+			go func() {
+				io.Copy(manager, conn)
+				manager.(*net.TCPConn).CloseWrite()
+				close(done)
+			}()
+			io.Copy(conn, manager)
+			conn.(*net.TCPConn).CloseWrite()
+			<-done
+		}()
+	}
+}
+
+func (s *session) RunStart(args *RunStartArgs, reply *RunStartReply) error {
+	inst, err := s.instance(args.ID)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	outc, errc, err := inst.inst.Run(ctx, args.Command)
+	if err != nil {
+		cancel()
+		return err
+	}
+	run := &workerRun{
+		cancel: cancel,
+		ready:  make(chan struct{}, 1),
+	}
+	s.mu.Lock()
+	s.seq++
+	reply.RunID = fmt.Sprintf("run-%v", s.seq)
+	s.mu.Unlock()
+	inst.mu.Lock()
+	inst.runs[reply.RunID] = run
+	inst.mu.Unlock()
+	go run.collect(outc, errc)
+	return nil
+}
+
+func (run *workerRun) collect(outc <-chan []byte, errc <-chan error) {
+	for {
+		select {
+		case out, ok := <-outc:
+			if !ok {
+				// Don't spin on the closed channel while waiting for the error.
+				outc = nil
+				continue
+			}
+			run.mu.Lock()
+			run.output = append(run.output, out...)
+			run.mu.Unlock()
+			run.signal()
+		case err := <-errc:
+			// Pick up the output that arrived before the command has finished.
+			for len(outc) != 0 {
+				out := <-outc
+				run.mu.Lock()
+				run.output = append(run.output, out...)
+				run.mu.Unlock()
+			}
+			run.mu.Lock()
+			run.done = true
+			run.err = err
+			run.mu.Unlock()
+			run.signal()
+			return
+		}
+	}
+}
+
+func (run *workerRun) signal() {
+	select {
+	case run.ready <- struct{}{}:
+	default:
+	}
+}
+
+func (s *session) run(args *RunArgs) (*workerInstance, *workerRun, error) {
+	inst, err := s.instance(args.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	inst.mu.Lock()
+	defer inst.mu.Unlock()
+	run := inst.runs[args.RunID]
+	if run == nil {
+		return nil, nil, fmt.Errorf("unknown run %v", args.RunID)
+	}
+	return inst, run, nil
+}
+
+// RunRead returns the new output of the run.
+// It waits for the output to appear for some time to not spin.
+func (s *session) RunRead(args *RunArgs, reply *RunReadReply) error {
+	inst, run, err := s.run(args)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-run.ready:
+	case <-time.After(runReadTimeout):
+	}
+	run.mu.Lock()
+	defer run.mu.Unlock()
+	reply.Output = run.output
+	run.output = nil
+	reply.Finished = run.done
+	if run.err != nil {
+		reply.Error = run.err.Error()
+	}
+	if run.done {
+		inst.mu.Lock()
+		delete(inst.runs, args.RunID)
+		inst.mu.Unlock()
+	}
+	return nil
+}
+
+func (s *session) RunStop(args *RunArgs, reply *RunStopReply) error {
+	inst, run, err := s.run(args)
+	if err != nil {
+		return err
+	}
+	run.cancel()
+	inst.mu.Lock()
+	delete(inst.runs, args.RunID)
+	inst.mu.Unlock()
+	return nil
+}
+
+func (s *session) Diagnose(args *DiagnoseArgs, reply *DiagnoseReply) error {
+	inst, err := s.instance(args.ID)
+	if err != nil {
+		return err
+	}
+	reply.Diagnosis, reply.Wait = inst.inst.Diagnose(&report.Report{
+		Title: args.Title,
+		Type:  crash.Type(args.Type),
+	})
+	return nil
+}
+
+func (s *session) MachineInfo(args *InstanceArgs, reply *MachineInfoReply) error {
+	inst, err := s.instance(args.ID)
+	if err != nil {
+		return err
+	}
+	if infoer, ok := inst.inst.(vmimpl.Infoer); ok {
+		reply.Info, err = infoer.Info()
+	}
+	return err
+}
+
+func (s *session) Close(args *InstanceArgs, reply *CloseReply) error {
+	s.mu.Lock()
+	inst := s.instances[args.ID]
+	delete(s.instances, args.ID)
+	s.mu.Unlock()
+	if inst == nil {
+		return fmt.Errorf("unknown instance %v", args.ID)
+	}
+	return inst.close()
+}
+
+func (inst *workerInstance) close() error {
+	inst.mu.Lock()
+	for _, run := range inst.runs {
+		run.cancel()
+	}
+	if inst.fwd != nil {
+		inst.fwd.Close()
+	}
+	inst.mu.Unlock()
+	err := inst.inst.Close()
+	os.RemoveAll(inst.workdir)
+	return err
+}
+
+func (s *session) close() {
+	s.mu.Lock()
+	instances := s.instances
+	s.instances = nil
+	pool := s.pool
+	s.mu.Unlock()
+	for _, inst := range instances {
+		inst.close()
+	}
+	if closer, ok := pool.(io.Closer); ok {
+		closer.Close()
+	}
+}
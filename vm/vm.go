@@ -33,12 +33,15 @@ import (
 	// Import all VM implementations, so that users only need to import vm.
 	_ "github.com/google/syzkaller/vm/adb"
 	_ "github.com/google/syzkaller/vm/bhyve"
+	_ "github.com/google/syzkaller/vm/container"
 	_ "github.com/google/syzkaller/vm/cuttlefish"
+	_ "github.com/google/syzkaller/vm/firecracker"
 	_ "github.com/google/syzkaller/vm/gce"
 	_ "github.com/google/syzkaller/vm/gvisor"
 	_ "github.com/google/syzkaller/vm/isolated"
 	_ "github.com/google/syzkaller/vm/proxyapp"
 	_ "github.com/google/syzkaller/vm/qemu"
+	_ "github.com/google/syzkaller/vm/remote"
 	_ "github.com/google/syzkaller/vm/starnix"
 	_ "github.com/google/syzkaller/vm/virtualbox"
 	_ "github.com/google/syzkaller/vm/vmm"
@@ -56,6 +59,7 @@ type Pool struct {
 	snapshot           bool
 	hostFuzzer         bool
 	statOutputReceived *stat.Val
+	console            *ConsoleRecorder
 }
 
 type Instance struct {
@@ -130,6 +134,8 @@ func Create(cfg *mgrconfig.Config, debug bool) (*Pool, error) {
 		Debug:     debug,
 		Config:    cfg.VM,
 		KernelSrc: cfg.KernelSrc,
+
+		Diagnostics: cfg.Diagnostics,
 	}
 	impl, err := typ.Ctor(env)
 	if err != nil {
@@ -140,7 +146,15 @@ func Create(cfg *mgrconfig.Config, debug bool) (*Pool, error) {
 		log.Logf(0, "limiting number of VMs from %v to 1 in debug mode", count)
 		count = 1
 	}
+	var console *ConsoleRecorder
+	if cfg.ConsoleRecords > 0 {
+		console, err = NewConsoleRecorder(filepath.Join(cfg.Workdir, "console"), cfg.ConsoleRecords)
+		if err != nil {
+			return nil, err
+		}
+	}
 	return &Pool{
+		console:    console,
 		impl:       impl,
 		typ:        typ,
 		workdir:    env.Workdir,
@@ -158,6 +172,11 @@ func (pool *Pool) Count() int {
 	return pool.count
 }
 
+// Console returns the recorder of VM console output, nil if recording is not enabled.
+func (pool *Pool) Console() *ConsoleRecorder {
+	return pool.console
+}
+
 func (pool *Pool) Create(ctx context.Context, index int) (*Instance, error) {
 	if index < 0 || index >= pool.count {
 		return nil, fmt.Errorf("invalid VM index %v (count %v)", index, pool.count)
@@ -317,6 +336,14 @@ func (inst *Instance) Run(ctx context.Context, reporter *report.Reporter, comman
 		reporter:        reporter,
 		lastExecuteTime: time.Now(),
 	}
+	if inst.pool.console != nil {
+		mon.console, err = inst.pool.console.start(inst.index, command)
+		if err != nil {
+			log.Logf(0, "failed to record console output: %v", err)
+		} else {
+			defer mon.console.close()
+		}
+	}
 	reps := mon.monitorExecution()
 	return mon.output, reps, nil
 }
@@ -328,6 +355,27 @@ func (inst *Instance) Info() ([]byte, error) {
 	return nil, nil
 }
 
+// DumpMemory saves an image of the guest memory into file, e.g. after a kernel crash.
+// Returns false if the VM type does not support memory dumps or they are not enabled.
+func (inst *Instance) DumpMemory(file string) (bool, error) {
+	impl, ok := inst.impl.(vmimpl.MemoryDumper)
+	if !ok {
+		return false, nil
+	}
+	return impl.DumpMemory(file)
+}
+
+// SaveRecording stops the VM and saves the recording of its execution into dir.
+// It must be called before Close and only works if the VM type supports recording
+// and it's enabled in the VM config.
+func (inst *Instance) SaveRecording(dir string) error {
+	impl, ok := inst.impl.(vmimpl.Recorder)
+	if !ok {
+		return errors.New("this VM type does not support execution recording")
+	}
+	return impl.SaveRecording(dir)
+}
+
 func (inst *Instance) diagnose(reps []*report.Report) ([]byte, bool) {
 	if len(reps) == 0 {
 		panic("reps is empty")
@@ -367,6 +415,8 @@ type monitor struct {
 	lastExecuteTime time.Time
 	// extractCalled is used to prevent multiple extractError calls.
 	extractCalled bool
+	// console records the full output, if enabled.
+	console *consoleWriter
 }
 
 func (mon *monitor) monitorExecution() []*report.Report {
@@ -427,6 +477,7 @@ func (mon *monitor) monitorExecution() []*report.Report {
 }
 
 func (mon *monitor) appendOutput(out []byte) ([]*report.Report, bool) {
+	mon.record(out)
 	lastPos := len(mon.output)
 	mon.output = append(mon.output, out...)
 	if bytes.Contains(mon.output[lastPos:], []byte(executedProgramsStart)) {
@@ -529,6 +580,12 @@ func (mon *monitor) createReports(defaultError string) []*report.Report {
 	}
 }
 
+func (mon *monitor) record(out []byte) {
+	if mon.console != nil {
+		mon.console.write(out)
+	}
+}
+
 func (mon *monitor) waitForOutput() {
 	timer := time.NewTimer(vmimpl.WaitForOutputTimeout * mon.inst.pool.timeouts.Scale)
 	defer timer.Stop()
@@ -538,6 +595,7 @@ func (mon *monitor) waitForOutput() {
 			if !ok {
 				return
 			}
+			mon.record(out)
 			mon.output = append(mon.output, out...)
 		case <-timer.C:
 			return
@@ -4,23 +4,84 @@
 package vmimpl
 
 import (
+	"errors"
+	"fmt"
 	"regexp"
-	"strings"
+	"slices"
 
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
 )
 
-// DiagnoseLinux diagnoses some Linux kernel bugs over the provided ssh callback.
-func DiagnoseLinux(rep *report.Report, ssh func(args ...string) ([]byte, error)) (output []byte, wait, handled bool) {
-	if !strings.Contains(rep.Title, "MAX_LOCKDEP") {
-		return nil, false, false
+// LinuxDiagnostics are used by DiagnoseLinux if no diagnostics are configured.
+// Each command is run over ssh in a possibly hung VM, so the more expensive ones
+// (e.g. sysrq for hangs) are left to the manager config.
+var LinuxDiagnostics = []mgrconfig.Diagnostic{
+	{
+		// Dump /proc/lockdep* files on BUG: MAX_LOCKDEP_{KEYS,ENTRIES,CHAINS,CHAIN_HLOCKS} too low!
+		Name:          "lockdep",
+		Command:       "cat /proc/lockdep_stats /proc/lockdep /proc/lockdep_chains",
+		Title:         "MAX_LOCKDEP",
+		MaxOutput:     4 << 20,
+		StripPointers: true,
+	},
+}
+
+const defaultDiagnosticOutput = 64 << 10
+
+var pointerRe = regexp.MustCompile(` *\[?[0-9a-f]{8,}\]?\s*`)
+
+// DiagnoseLinux runs diagnostics matching the report over the provided ssh callback
+// (LinuxDiagnostics if diags is nil). The outputs are concatenated under the diagnostic names.
+func DiagnoseLinux(rep *report.Report, diags []mgrconfig.Diagnostic,
+	ssh func(args ...string) ([]byte, error)) (output []byte, wait, handled bool) {
+	if diags == nil {
+		diags = LinuxDiagnostics
+	}
+	for _, diag := range diags {
+		if !diagnosticMatches(rep, &diag) {
+			continue
+		}
+		res, err := ssh(diag.Command)
+		if diag.StripPointers {
+			res = pointerRe.ReplaceAll(res, nil)
+		}
+		limit := diag.MaxOutput
+		if limit == 0 {
+			limit = defaultDiagnosticOutput
+		}
+		if len(res) > limit {
+			res = append(res[:limit:limit], "\n<truncated>\n"...)
+		}
+		output = append(output, fmt.Sprintf("%v:\n", diag.Name)...)
+		output = append(output, res...)
+		if len(res) != 0 && res[len(res)-1] != '\n' {
+			output = append(output, '\n')
+		}
+		if err != nil {
+			output = append(output, fmt.Sprintf("%v\n", err)...)
+		}
+		handled = true
+		wait = wait || diag.Console
+		var verboseErr *osutil.VerboseError
+		if errors.As(err, &verboseErr) && (verboseErr.ExitCode == 255 || verboseErr.ExitCode == -1) {
+			// The VM is not reachable over ssh (or the command timed out),
+			// the rest will most likely fail as well, and each attempt takes time.
+			break
+		}
+	}
+	return output, wait, handled
+}
+
+func diagnosticMatches(rep *report.Report, diag *mgrconfig.Diagnostic) bool {
+	if len(diag.Types) != 0 && !slices.Contains(diag.Types, rep.Type.String()) {
+		return false
 	}
-	// Dump /proc/lockdep* files on BUG: MAX_LOCKDEP_{KEYS,ENTRIES,CHAINS,CHAIN_HLOCKS} too low!
-	output, err := ssh("cat", "/proc/lockdep_stats", "/proc/lockdep", "/proc/lockdep_chains")
-	if err != nil {
-		output = append(output, err.Error()...)
+	if diag.Title != "" {
+		if matched, _ := regexp.MatchString(diag.Title, rep.Title); !matched {
+			return false
+		}
 	}
-	// Remove mangled pointer values, they take lots of space but don't add any value.
-	output = regexp.MustCompile(` *\[?[0-9a-f]{8,}\]?\s*`).ReplaceAll(output, nil)
-	return output, false, true
+	return true
 }
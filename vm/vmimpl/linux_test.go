@@ -0,0 +1,57 @@
+// Copyright 2026 syzkaller project authors. All rights reserved.
+// Use of this source code is governed by Apache 2 LICENSE that can be found in the LICENSE file.
+
+package vmimpl
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/syzkaller/pkg/mgrconfig"
+	"github.com/google/syzkaller/pkg/osutil"
+	"github.com/google/syzkaller/pkg/report"
+	"github.com/google/syzkaller/pkg/report/crash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiagnoseLinux(t *testing.T) {
+	diags := []mgrconfig.Diagnostic{
+		{Name: "all", Command: "all"},
+		{Name: "hang", Command: "hang", Types: []string{"HANG"}, Console: true},
+		{Name: "big", Command: "big", Title: "^INFO: task hung", MaxOutput: 4},
+		{Name: "unknown", Command: "unknown", Types: []string{"UNKNOWN"}},
+	}
+	var commands []string
+	ssh := func(args ...string) ([]byte, error) {
+		commands = append(commands, strings.Join(args, " "))
+		return []byte(args[0] + " output"), nil
+	}
+	output, wait, handled := DiagnoseLinux(&report.Report{
+		Title: "INFO: task hung in foo",
+		Type:  crash.Hang,
+	}, diags, ssh)
+	assert.True(t, handled)
+	assert.True(t, wait)
+	assert.Equal(t, []string{"all", "hang", "big"}, commands)
+	assert.Equal(t, "all:\nall output\nhang:\nhang output\nbig:\nbig \n<truncated>\n", string(output))
+
+	commands = nil
+	_, wait, handled = DiagnoseLinux(&report.Report{Title: "no output from test machine"}, diags, ssh)
+	assert.True(t, handled)
+	assert.False(t, wait)
+	assert.Equal(t, []string{"all", "unknown"}, commands)
+
+	// Nothing else is tried once ssh fails.
+	commands = nil
+	_, _, handled = DiagnoseLinux(&report.Report{Title: "no output from test machine"}, diags,
+		func(args ...string) ([]byte, error) {
+			commands = append(commands, args[0])
+			return nil, &osutil.VerboseError{Err: fmt.Errorf("ssh failed"), ExitCode: 255}
+		})
+	assert.True(t, handled)
+	assert.Equal(t, []string{"all"}, commands)
+
+	_, _, handled = DiagnoseLinux(&report.Report{Title: "WARNING in foo", Type: crash.Warning}, nil, ssh)
+	assert.False(t, handled)
+}
@@ -22,6 +22,7 @@ import (
 	"time"
 
 	"github.com/google/syzkaller/pkg/log"
+	"github.com/google/syzkaller/pkg/mgrconfig"
 	"github.com/google/syzkaller/pkg/osutil"
 	"github.com/google/syzkaller/pkg/report"
 	"github.com/google/syzkaller/sys/targets"
@@ -70,6 +71,20 @@ type Infoer interface {
 	Info() ([]byte, error)
 }
 
+// MemoryDumper is an optional interface that can be implemented by Instance.
+type MemoryDumper interface {
+	// DumpMemory saves an image of the guest memory into file.
+	// Returns false if memory dumps are not enabled in the VM config.
+	DumpMemory(file string) (bool, error)
+}
+
+// Recorder is an optional interface that can be implemented by Instance.
+type Recorder interface {
+	// SaveRecording stops the VM and saves the recording of its execution into dir.
+	// The only valid operation on the instance afterwards is Close.
+	SaveRecording(dir string) error
+}
+
 // Env contains global constant parameters for a pool of VMs.
 type Env struct {
 	// Unique name
@@ -86,6 +101,8 @@ type Env struct {
 	Debug     bool
 	Config    []byte // json-serialized VM-type-specific config
 	KernelSrc string
+	// Commands to collect kernel state on crashes (nil means the default set for the OS).
+	Diagnostics []mgrconfig.Diagnostic
 }
 
 // BootError is returned by Pool.Create when VM does not boot.